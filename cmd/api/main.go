@@ -1,14 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	_ "sentiment-api/docs" // Import swagger docs
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/budget"
 	"sentiment-api/internal/client"
 	"sentiment-api/internal/config"
+	"sentiment-api/internal/engine"
+	"sentiment-api/internal/experiment"
 	"sentiment-api/internal/handler"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/lexicon"
+	"sentiment-api/internal/limiter"
+	"sentiment-api/internal/middleware"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/provider"
 	"sentiment-api/internal/service"
+	"sentiment-api/internal/shadow"
+	"sentiment-api/internal/storage"
+	"sentiment-api/internal/usage"
 	"sentiment-api/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -42,17 +57,217 @@ func main() {
 		log.Fatal("URL_CHAT_LLM_LLM environment variable is required")
 	}
 
+	// Initialize storage
+	var repository storage.AnalysisRepository
+	var usageRepository storage.UsageRepository
+	if cfg.Storage.Enabled {
+		if cfg.Storage.Driver != "sqlite" {
+			logger.LogError("Unsupported storage driver", logrus.Fields{
+				"driver": cfg.Storage.Driver,
+			})
+			log.Fatalf("Unsupported storage driver: %s", cfg.Storage.Driver)
+		}
+
+		sqliteRepository, err := storage.NewSQLiteRepository(cfg.Storage.Path)
+		if err != nil {
+			logger.LogError("Failed to initialize storage", logrus.Fields{
+				"error": err.Error(),
+			})
+			log.Fatalf("Failed to initialize storage: %v", err)
+		}
+		defer sqliteRepository.Close()
+
+		repository = sqliteRepository
+		usageRepository = sqliteRepository
+		storage.StartRetention(context.Background(), repository, cfg.Storage.RetentionDays, time.Hour)
+		logger.LogInfo("Analysis storage enabled", logrus.Fields{
+			"driver":         cfg.Storage.Driver,
+			"path":           cfg.Storage.Path,
+			"retention_days": cfg.Storage.RetentionDays,
+		})
+	}
+
+	// Initialize usage accounting
+	usageTracker := usage.NewTracker(usageRepository)
+	quotaManager := auth.NewQuotaManager()
+	usageTracker.OnRecord(func(clientID, modelName string, tokenUsage model.TokenUsage) {
+		quotaManager.AddTokens(clientID, tokenUsage.TotalTokens)
+	})
+
+	// Initialize spend guardrails
+	var budgetManager *budget.Manager
+	if cfg.Budget.Enabled {
+		budgetManager, err = budget.NewManager(cfg.Budget)
+		if err != nil {
+			logger.LogError("Failed to initialize budgets", logrus.Fields{
+				"error": err.Error(),
+			})
+			log.Fatalf("Failed to initialize budgets: %v", err)
+		}
+
+		if usageRepository != nil {
+			if err := budgetManager.Restore(context.Background(), usageRepository); err != nil {
+				logger.LogError("Failed to restore budget spend", logrus.Fields{
+					"error": err.Error(),
+				})
+				log.Fatalf("Failed to restore budget spend: %v", err)
+			}
+		} else {
+			logger.LogWarn("Storage is disabled, budget spend is kept in memory and resets on restart", nil)
+		}
+	}
+
+	// Initialize authentication
+	var authMiddleware gin.HandlerFunc
+	if cfg.Auth.Enabled {
+		keyStore, err := auth.NewKeyStore(cfg.Auth)
+		if err != nil {
+			logger.LogError("Failed to load API keys", logrus.Fields{
+				"error": err.Error(),
+			})
+			log.Fatalf("Failed to load API keys: %v", err)
+		}
+
+		jwtValidator, err := auth.NewJWTValidator(cfg.Auth)
+		if err != nil {
+			logger.LogError("Failed to initialize JWT validation", logrus.Fields{
+				"error": err.Error(),
+			})
+			log.Fatalf("Failed to initialize JWT validation: %v", err)
+		}
+
+		if keyStore.Len() == 0 && jwtValidator == nil {
+			logger.LogError("API keys or JWT settings are required when AUTH_ENABLED is true", nil)
+			log.Fatal("API keys or JWT settings are required when AUTH_ENABLED is true")
+		}
+
+		authMiddleware = middleware.Authenticate(keyStore, quotaManager, jwtValidator)
+		logger.LogInfo("Authentication enabled", logrus.Fields{
+			"api_keys": keyStore.Len(),
+			"jwt":      jwtValidator != nil,
+		})
+	} else {
+		logger.LogWarn("Authentication is disabled", nil)
+	}
+
 	// Initialize clients
-	llmClient := client.NewLLMClient(cfg)
+	concurrencyLimiter := limiter.New(cfg.Limiter)
+	providerChain, err := provider.NewChain(cfg.LLM)
+	if err != nil {
+		logger.LogError("Failed to load LLM providers", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to load LLM providers: %v", err)
+	}
+	llmClient := client.NewLLMClient(cfg, concurrencyLimiter, providerChain)
+
+	// Load label schemes
+	labelSchemes, err := labels.NewRegistry(cfg.Analysis.LabelSchemesFile, cfg.Analysis.DefaultLabelScheme)
+	if err != nil {
+		logger.LogError("Failed to load label schemes", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to load label schemes: %v", err)
+	}
+
+	// Initialize ensemble engine
+	ensembleMembers, err := engine.ParseMembers(cfg.Ensemble.Members, llmClient, lexicon.NewAnalyzer())
+	if err != nil {
+		logger.LogError("Failed to parse ensemble members", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to parse ensemble members: %v", err)
+	}
+	ensemble, err := engine.NewEnsemble(ensembleMembers, cfg.Ensemble.Strategy, cfg.Ensemble.ReviewThreshold)
+	if err != nil {
+		logger.LogError("Failed to configure ensemble engine", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to configure ensemble engine: %v", err)
+	}
+
+	// Load engine routing rules
+	engineRouter, err := engine.NewRouter(cfg.Routing.RulesFile, cfg.Routing.DefaultEngine, llmClient, lexicon.NewAnalyzer())
+	if err != nil {
+		logger.LogError("Failed to load routing rules", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to load routing rules: %v", err)
+	}
+	if engineRouter.Len() > 0 {
+		logger.LogInfo("Engine routing enabled", logrus.Fields{
+			"rules": engineRouter.Len(),
+		})
+	}
+
+	// Initialize shadow evaluation
+	var shadowRunner *shadow.Runner
+	if cfg.Shadow.Enabled {
+		shadowRunner, err = shadow.NewRunner(cfg.Shadow, llmClient, lexicon.NewAnalyzer(), budgetManager)
+		if err != nil {
+			logger.LogError("Failed to configure shadow mode", logrus.Fields{
+				"error": err.Error(),
+			})
+			log.Fatalf("Failed to configure shadow mode: %v", err)
+		}
+		logger.LogInfo("Shadow mode enabled", logrus.Fields{
+			"candidate":   shadowRunner.Candidate(),
+			"sample_rate": cfg.Shadow.SampleRate,
+		})
+	}
+
+	// Load A/B experiments
+	experiments, err := experiment.NewRegistry(cfg.Analysis.ExperimentsFile, llmClient, lexicon.NewAnalyzer())
+	if err != nil {
+		logger.LogError("Failed to load experiments", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to load experiments: %v", err)
+	}
+	if active, ok := experiments.Active(); ok {
+		logger.LogInfo("Experiment active", logrus.Fields{
+			"experiment": active.Name,
+			"assign_by":  active.AssignBy,
+			"variants":   len(active.Variants),
+		})
+	}
 
 	// Initialize services
-	sentimentService := service.NewSentimentService(llmClient)
+	sentimentService := service.NewSentimentService(llmClient, usageTracker, budgetManager, repository, cfg.Storage.StoreText, cfg.Analysis, labelSchemes, ensemble, engineRouter, shadowRunner, experiments)
 
 	// Initialize handlers
 	sentimentHandler := handler.NewSentimentHandler(sentimentService)
+	usageHandler := handler.NewUsageHandler(usageTracker)
+	analysisHandler := handler.NewAnalysisHandler(service.NewAnalysisService(repository, cfg.Storage.StoreText, labelSchemes))
+	surveyHandler := handler.NewSurveyHandler(sentimentService)
+	labelHandler := handler.NewLabelHandler(sentimentService)
+	providerHandler := handler.NewProviderHandler(providerChain)
+	shadowHandler := handler.NewShadowHandler(shadowRunner)
+
+	// Initialize CORS policy
+	corsMiddleware, err := middleware.CORS(cfg.CORS)
+	if err != nil {
+		logger.LogError("Failed to load CORS configuration", logrus.Fields{
+			"error": err.Error(),
+		})
+		log.Fatalf("Failed to load CORS configuration: %v", err)
+	}
 
 	// Setup router
-	router := setupRouter(sentimentHandler)
+	router := setupRouter(routerHandlers{
+		sentiment: sentimentHandler,
+		survey:    surveyHandler,
+		analysis:  analysisHandler,
+		usage:     usageHandler,
+		labels:    labelHandler,
+		providers: providerHandler,
+		shadow:    shadowHandler,
+	}, routerMiddleware{
+		auth:    authMiddleware,
+		cors:    corsMiddleware,
+		limiter: concurrencyLimiter,
+		budget:  budgetManager,
+	})
 
 	// Start server
 	address := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -68,8 +283,27 @@ func main() {
 	}
 }
 
+// routerHandlers groups the handlers mounted by setupRouter
+type routerHandlers struct {
+	sentiment *handler.SentimentHandler
+	survey    *handler.SurveyHandler
+	analysis  *handler.AnalysisHandler
+	usage     *handler.UsageHandler
+	labels    *handler.LabelHandler
+	providers *handler.ProviderHandler
+	shadow    *handler.ShadowHandler
+}
+
+// routerMiddleware groups the shared middleware dependencies of setupRouter
+type routerMiddleware struct {
+	auth    gin.HandlerFunc
+	cors    gin.HandlerFunc
+	limiter *limiter.Limiter
+	budget  *budget.Manager
+}
+
 // setupRouter configures and returns the Gin router
-func setupRouter(sentimentHandler *handler.SentimentHandler) *gin.Engine {
+func setupRouter(handlers routerHandlers, mw routerMiddleware) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -78,40 +312,81 @@ func setupRouter(sentimentHandler *handler.SentimentHandler) *gin.Engine {
 	// Add middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
-	router.Use(corsMiddleware())
+	router.Use(middleware.RequestID())
+	router.Use(mw.cors)
 
 	// Health check endpoint
-	router.GET("/health", sentimentHandler.HealthCheck)
+	router.GET("/health", handlers.sentiment.HealthCheck)
 
 	// Swagger documentation endpoint
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// llmRoute guards a handler that calls the LLM with load shedding and budget checks
+	llmRoute := func(priority limiter.Priority, h gin.HandlerFunc) []gin.HandlerFunc {
+		chain := []gin.HandlerFunc{middleware.LoadShedding(mw.limiter, priority)}
+		if mw.budget != nil {
+			chain = append(chain, middleware.BudgetGuard(mw.budget))
+		}
+		return append(chain, h)
+	}
+
+	// requireScope enforces a scope when authentication is enabled
+	requireScope := func(group *gin.RouterGroup, scope string) {
+		if mw.auth != nil {
+			group.Use(middleware.RequireScope(scope))
+		}
+	}
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	if mw.auth != nil {
+		v1.Use(mw.auth)
+	}
 	{
 		sentiment := v1.Group("/sentiment")
+		requireScope(sentiment, auth.ScopeAnalyze)
 		{
-			sentiment.POST("/analyze", sentimentHandler.AnalyzeSentiment)
-			sentiment.GET("/types", sentimentHandler.GetSentiments)
+			sentiment.POST("/analyze", llmRoute(limiter.PriorityInteractive, handlers.sentiment.AnalyzeSentiment)...)
+			sentiment.GET("/types", handlers.labels.GetSentimentTypes)
 		}
-	}
 
-	return router
-}
+		surveys := v1.Group("/surveys")
+		requireScope(surveys, auth.ScopeBatch)
+		{
+			surveys.POST("/submissions", llmRoute(limiter.PriorityBatch, handlers.survey.AnalyzeSubmission)...)
+			surveys.POST("/topics", llmRoute(limiter.PriorityBatch, handlers.survey.AnalyzeTopics)...)
+			surveys.POST("/summaries", llmRoute(limiter.PriorityBatch, handlers.survey.SummarizeAnswers)...)
+		}
 
-// corsMiddleware adds CORS headers
-func corsMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+		analyses := v1.Group("/analyses")
+		requireScope(analyses, auth.ScopeAnalyze)
+		{
+			analyses.GET("", handlers.analysis.ListAnalyses)
+			analyses.GET("/export", handlers.analysis.ExportAnalyses)
+			analyses.GET("/summary", handlers.analysis.GetSummary)
+			analyses.POST("/:id/feedback", handlers.analysis.SubmitFeedback)
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+		experiments := v1.Group("/experiments")
+		requireScope(experiments, auth.ScopeAdmin)
+		{
+			experiments.GET("/:name/summary", handlers.analysis.GetExperimentSummary)
 		}
 
-		c.Next()
+		v1.GET("/usage", handlers.usage.GetUsage)
+
+		providers := v1.Group("/providers")
+		requireScope(providers, auth.ScopeAdmin)
+		{
+			providers.GET("", handlers.providers.GetProviders)
+		}
+
+		shadowReports := v1.Group("/shadow")
+		requireScope(shadowReports, auth.ScopeAdmin)
+		{
+			shadowReports.GET("/report", handlers.shadow.GetReport)
+		}
 	}
+
+	return router
 }
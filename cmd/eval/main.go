@@ -0,0 +1,338 @@
+// Command eval runs a labeled dataset through a sentiment engine and reports
+// accuracy, per-class precision/recall/F1, the confusion matrix and Cohen's
+// kappa. The JSON report it writes is meant to be kept for regression tracking.
+//
+// Usage:
+//
+//	go run ./cmd/eval -dataset testdata/labeled.jsonl -engine llm -output eval_report.json
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/config"
+	"sentiment-api/internal/engine"
+	"sentiment-api/internal/evaluation"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/lexicon"
+	"sentiment-api/internal/provider"
+	"sentiment-api/pkg/logger"
+)
+
+// requestTimeout bounds the analysis of one sample
+const requestTimeout = 60 * time.Second
+
+// Report is the machine-readable result of one evaluation run
+type Report struct {
+	GeneratedAt   time.Time          `json:"generated_at"`
+	Dataset       string             `json:"dataset"`
+	DatasetSHA256 string             `json:"dataset_sha256"`
+	Engine        string             `json:"engine"`
+	LabelScheme   string             `json:"label_scheme"`
+	Samples       int                `json:"samples"`
+	Evaluated     int                `json:"evaluated"`
+	Skipped       int                `json:"skipped"`
+	Errors        int                `json:"errors"`
+	ParseFailures int                `json:"parse_failures"`
+	DurationMs    int64              `json:"duration_ms"`
+	TotalTokens   int64              `json:"total_tokens"`
+	Metrics       evaluation.Metrics `json:"metrics"`
+	Mistakes      []Mistake          `json:"mistakes,omitempty"`
+	Failures      []SampleFailure    `json:"failures,omitempty"`
+}
+
+// Mistake is a sample the engine labeled differently than expected
+type Mistake struct {
+	Index     int    `json:"index"`
+	Expected  string `json:"expected"`
+	Predicted string `json:"predicted"`
+	Text      string `json:"text_jawaban"`
+}
+
+// SampleFailure is a sample that could not be evaluated
+type SampleFailure struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// outcome is the analysis result of one sample
+type outcome struct {
+	predicted   string
+	parseFailed bool
+	tokens      int
+	err         error
+}
+
+func main() {
+	datasetPath := flag.String("dataset", "", "labeled dataset (.jsonl or .csv) with text_pertanyaan, text_jawaban and label")
+	engineName := flag.String("engine", "llm", `engine to evaluate: "lexicon", "llm" or "llm:<model>"`)
+	prompt := flag.String("prompt", engine.PromptStandard, `prompt of LLM engines: "standard" or "nuance"`)
+	schemeID := flag.String("scheme", "", "label scheme id (default: the configured default scheme)")
+	concurrency := flag.Int("concurrency", 4, "samples analyzed in parallel")
+	limit := flag.Int("limit", 0, "evaluate only the first n samples (0 = all)")
+	output := flag.String("output", "eval_report.json", "path of the JSON report (empty to skip)")
+	minAccuracy := flag.Float64("min-accuracy", 0, "exit with status 1 when accuracy is below this value")
+	mistakes := flag.Int("mistakes", 50, "misclassified samples to include in the report")
+	flag.Parse()
+
+	if *datasetPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	// Keep the per-call logs of the client out of the report output
+	logger.InitLogger("warn", cfg.Log.Format)
+
+	samples, err := evaluation.LoadDataset(*datasetPath)
+	if err != nil {
+		log.Fatalf("Failed to load dataset: %v", err)
+	}
+	if *limit > 0 && *limit < len(samples) {
+		samples = samples[:*limit]
+	}
+
+	checksum, err := fileChecksum(*datasetPath)
+	if err != nil {
+		log.Fatalf("Failed to read dataset: %v", err)
+	}
+
+	labelSchemes, err := labels.NewRegistry(cfg.Analysis.LabelSchemesFile, cfg.Analysis.DefaultLabelScheme)
+	if err != nil {
+		log.Fatalf("Failed to load label schemes: %v", err)
+	}
+	scheme := labelSchemes.Default()
+	if *schemeID != "" {
+		if scheme, err = labelSchemes.Get(*schemeID); err != nil {
+			log.Fatalf("Invalid label scheme: %v", err)
+		}
+	}
+
+	// The lexicon engine runs offline, so LLM settings are only needed otherwise
+	var llmClient *client.LLMClient
+	if strings.TrimSpace(*engineName) != engine.LexiconName {
+		if cfg.LLM.ProvidersFile == "" && (cfg.LLM.APIKey == "" || cfg.LLM.URL == "") {
+			log.Fatal("LLM_API_KEY and URL_CHAT_LLM_LLM are required to evaluate an LLM engine")
+		}
+
+		providerChain, err := provider.NewChain(cfg.LLM)
+		if err != nil {
+			log.Fatalf("Failed to load LLM providers: %v", err)
+		}
+		llmClient = client.NewLLMClient(cfg, nil, providerChain)
+	}
+
+	parsed, err := engine.Parse(*engineName, llmClient, lexicon.NewAnalyzer())
+	if err != nil {
+		log.Fatalf("Invalid engine: %v", err)
+	}
+	evalEngine, err := engine.WithPrompt(parsed, *prompt)
+	if err != nil {
+		log.Fatalf("Invalid prompt: %v", err)
+	}
+
+	report := &Report{
+		GeneratedAt:   time.Now().UTC(),
+		Dataset:       *datasetPath,
+		DatasetSHA256: checksum,
+		Engine:        evalEngine.Name(),
+		LabelScheme:   scheme.ID,
+		Samples:       len(samples),
+	}
+
+	// Samples whose label is not in the scheme are reported, never analyzed
+	var valid []evaluation.Sample
+	var indexes []int
+	for i, sample := range samples {
+		label, ok := scheme.Normalize(sample.Label)
+		if !ok {
+			report.Skipped++
+			report.Failures = append(report.Failures, SampleFailure{
+				Index: i,
+				Error: fmt.Sprintf("label %q is not in scheme %s", sample.Label, scheme.ID),
+			})
+			continue
+		}
+		sample.Label = label
+		valid = append(valid, sample)
+		indexes = append(indexes, i)
+	}
+
+	start := time.Now()
+	outcomes := analyzeAll(evalEngine, valid, client.AnalysisOptions{Scheme: scheme}, *concurrency)
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	var expected, predicted []string
+	for i, sample := range valid {
+		result := outcomes[i]
+		report.TotalTokens += int64(result.tokens)
+		if result.err != nil {
+			report.Errors++
+			report.Failures = append(report.Failures, SampleFailure{Index: indexes[i], Error: result.err.Error()})
+			continue
+		}
+		if result.parseFailed {
+			report.ParseFailures++
+		}
+
+		expected = append(expected, sample.Label)
+		predicted = append(predicted, result.predicted)
+		if sample.Label != result.predicted && len(report.Mistakes) < *mistakes {
+			report.Mistakes = append(report.Mistakes, Mistake{
+				Index:     indexes[i],
+				Expected:  sample.Label,
+				Predicted: result.predicted,
+				Text:      sample.TextJawaban,
+			})
+		}
+	}
+
+	report.Evaluated = len(expected)
+	report.Metrics = evaluation.Compute(expected, predicted, scheme.Names())
+
+	printSummary(os.Stdout, report)
+
+	if *output != "" {
+		if err := writeReport(*output, report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+		fmt.Printf("\nReport written to %s\n", *output)
+	}
+
+	if report.Evaluated == 0 {
+		log.Fatal("No sample could be evaluated")
+	}
+	if report.Metrics.Accuracy < *minAccuracy {
+		fmt.Printf("Accuracy %.4f is below the minimum %.4f\n", report.Metrics.Accuracy, *minAccuracy)
+		os.Exit(1)
+	}
+}
+
+// analyzeAll analyzes every sample with a bounded number of workers and
+// returns the outcomes in dataset order
+func analyzeAll(e engine.Engine, samples []evaluation.Sample, opts client.AnalysisOptions, concurrency int) []outcome {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	outcomes := make([]outcome, len(samples))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				outcomes[i] = analyze(e, samples[i], opts)
+			}
+		}()
+	}
+
+	for i := range samples {
+		indexes <- i
+		if (i+1)%50 == 0 {
+			fmt.Fprintf(os.Stderr, "Analyzed %d/%d samples\n", i+1, len(samples))
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return outcomes
+}
+
+// analyze runs one sample through the engine
+func analyze(e engine.Engine, sample evaluation.Sample, opts client.AnalysisOptions) outcome {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	result, err := e.Analyze(ctx, sample.TextPertanyaan, sample.TextJawaban, opts, false)
+	if err != nil {
+		return outcome{err: err}
+	}
+
+	o := outcome{predicted: result.Sentiment, parseFailed: result.ParseFailed}
+	if result.Usage != nil {
+		o.tokens = result.Usage.TotalTokens
+	}
+	return o
+}
+
+// printSummary writes a human-readable summary of the report
+func printSummary(w io.Writer, report *Report) {
+	metrics := report.Metrics
+
+	fmt.Fprintf(w, "Engine:        %s\n", report.Engine)
+	fmt.Fprintf(w, "Label scheme:  %s\n", report.LabelScheme)
+	fmt.Fprintf(w, "Dataset:       %s (%d samples)\n", report.Dataset, report.Samples)
+	fmt.Fprintf(w, "Evaluated:     %d (skipped %d, errors %d, parse failures %d)\n",
+		report.Evaluated, report.Skipped, report.Errors, report.ParseFailures)
+	fmt.Fprintf(w, "Duration:      %s\n", time.Duration(report.DurationMs)*time.Millisecond)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Accuracy:      %.4f\n", metrics.Accuracy)
+	fmt.Fprintf(w, "Macro F1:      %.4f\n", metrics.MacroF1)
+	fmt.Fprintf(w, "Weighted F1:   %.4f\n", metrics.WeightedF1)
+	fmt.Fprintf(w, "Cohen's kappa: %.4f\n", metrics.CohensKappa)
+	fmt.Fprintln(w)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Label\tPrecision\tRecall\tF1\tSupport")
+	for _, class := range metrics.PerClass {
+		fmt.Fprintf(table, "%s\t%.4f\t%.4f\t%.4f\t%d\n", class.Label, class.Precision, class.Recall, class.F1, class.Support)
+	}
+	table.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Confusion matrix (rows: expected, columns: predicted)")
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "\t%s\n", strings.Join(metrics.Labels, "\t"))
+	for i, label := range metrics.Labels {
+		cells := make([]string, len(metrics.Confusion[i]))
+		for j, count := range metrics.Confusion[i] {
+			cells[j] = fmt.Sprint(count)
+		}
+		fmt.Fprintf(table, "%s\t%s\n", label, strings.Join(cells, "\t"))
+	}
+	table.Flush()
+}
+
+// writeReport writes the report as indented JSON
+func writeReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// fileChecksum returns the hex SHA-256 of a file so reports of different
+// dataset versions are not compared by mistake
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,383 @@
+// Command loadtest drives the sentiment API with a configurable concurrency,
+// request rate, duration and dataset, and reports latency percentiles,
+// throughput and errors. Given a second endpoint or base URL it runs the same
+// load against both and compares their speed and labels.
+//
+// Usage:
+//
+//	go run ./cmd/loadtest -base-url http://localhost:8000 -api-key $API_KEY
+//	go run ./cmd/loadtest -compare-url http://staging:8000 -concurrency 8 -duration 1m
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/evaluation"
+)
+
+// maxErrorBody bounds the response body kept for a failed request
+const maxErrorBody = 512
+
+// target is one endpoint under test
+type target struct {
+	name   string
+	base   string
+	url    string
+	params map[string]interface{}
+}
+
+// tester sends the requests of a test run
+type tester struct {
+	client      *http.Client
+	apiKey      string
+	samples     []evaluation.Sample
+	concurrency int
+	rate        float64
+	duration    time.Duration
+	requests    int
+}
+
+func main() {
+	baseURL := flag.String("base-url", "http://localhost:8000", "base URL of the API")
+	endpoint := flag.String("endpoint", "/api/v1/sentiment/analyze", "endpoint to test")
+	compareURL := flag.String("compare-url", "", "base URL to compare against (default: -base-url)")
+	compareEndpoint := flag.String("compare-endpoint", "", "endpoint to compare against (default: -endpoint)")
+	name := flag.String("name", "A", "name of the first target in the results")
+	compareName := flag.String("compare-name", "B", "name of the compared target in the results")
+	apiKey := flag.String("api-key", os.Getenv("SENTIMENT_API_KEY"), "API key sent as X-API-Key (default: $SENTIMENT_API_KEY)")
+	datasetPath := flag.String("dataset", "", "dataset (.jsonl or .csv) with text_pertanyaan, text_jawaban and optional label (default: 30 built-in samples)")
+	params := flag.String("params", "", `JSON object merged into every request body, e.g. {"reasoning":true}`)
+	compareParams := flag.String("compare-params", "", "JSON object merged into the compared target's request bodies (default: -params)")
+	concurrency := flag.Int("concurrency", 1, "requests in flight per target")
+	rate := flag.Float64("rate", 0, "maximum requests per second per target (0 = unlimited)")
+	duration := flag.Duration("duration", 0, "run each target for this long, cycling through the dataset (0 = one pass)")
+	requests := flag.Int("requests", 0, "requests per target (default: one per sample, unlimited with -duration)")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout per request")
+	output := flag.String("output", "sentiment_performance_test.json", "path of the JSON report (empty to skip)")
+	flag.Parse()
+
+	samples := builtinSamples
+	dataset := "builtin"
+	if *datasetPath != "" {
+		loaded, err := evaluation.LoadDataset(*datasetPath)
+		if err != nil {
+			log.Fatalf("Failed to load dataset: %v", err)
+		}
+		samples = loaded
+		dataset = *datasetPath
+	}
+	for i := range samples {
+		if samples[i].TextPertanyaan == "" {
+			samples[i].TextPertanyaan = defaultQuestion
+		}
+	}
+
+	extra, err := parseParams(*params)
+	if err != nil {
+		log.Fatalf("Invalid -params: %v", err)
+	}
+
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+	if *requests <= 0 && *duration <= 0 {
+		*requests = len(samples)
+	}
+
+	targets := []target{{name: *name, base: *baseURL, url: joinURL(*baseURL, *endpoint), params: extra}}
+	if *compareURL != "" || *compareEndpoint != "" || *compareParams != "" {
+		compared := target{name: *compareName, base: *baseURL, params: extra}
+		if *compareURL != "" {
+			compared.base = *compareURL
+		}
+		path := *endpoint
+		if *compareEndpoint != "" {
+			path = *compareEndpoint
+		}
+		compared.url = joinURL(compared.base, path)
+		if *compareParams != "" {
+			if compared.params, err = parseParams(*compareParams); err != nil {
+				log.Fatalf("Invalid -compare-params: %v", err)
+			}
+		}
+		targets = append(targets, compared)
+	}
+
+	t := &tester{
+		client:      &http.Client{Timeout: *timeout},
+		apiKey:      *apiKey,
+		samples:     samples,
+		concurrency: *concurrency,
+		rate:        *rate,
+		duration:    *duration,
+		requests:    *requests,
+	}
+
+	fmt.Println("🧪 Sentiment Analysis Performance Test")
+	for _, tg := range targets {
+		fmt.Printf("   %s: %s\n", tg.name, tg.url)
+	}
+	fmt.Printf("Samples: %d, concurrency: %d, rate: %s, %s\n", len(samples), *concurrency, describeRate(*rate), describeLength(*requests, *duration))
+	fmt.Println(strings.Repeat("=", 60))
+
+	checked := map[string]bool{}
+	for _, tg := range targets {
+		if checked[tg.base] {
+			continue
+		}
+		checked[tg.base] = true
+		if err := checkHealth(tg.base); err != nil {
+			fmt.Printf("❌ Cannot connect to server at %s! Make sure server is running. (%v)\n", tg.base, err)
+			os.Exit(1)
+		}
+	}
+	fmt.Println("✅ Server is running, starting tests...")
+
+	report := &Report{
+		GeneratedAt: time.Now().UTC(),
+		Config: Config{
+			Dataset:     dataset,
+			Concurrency: *concurrency,
+			Rate:        *rate,
+			Requests:    *requests,
+			Timeout:     timeout.String(),
+		},
+		TestData: samples,
+	}
+	if *duration > 0 {
+		report.Config.Duration = duration.String()
+	}
+
+	// Targets run one after another so they do not compete for the server
+	for _, tg := range targets {
+		fmt.Printf("\n🚀 Testing %s (%s)...\n", tg.name, tg.url)
+		report.Targets = append(report.Targets, t.run(tg))
+	}
+
+	if len(report.Targets) == 2 {
+		report.Comparison = compare(report.Targets[0], report.Targets[1], samples)
+		report.Winners = winners(report.Targets[0], report.Targets[1])
+	}
+
+	printResults(os.Stdout, report)
+
+	if *output != "" {
+		if err := writeReport(*output, report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+		fmt.Printf("\n💾 Results saved to %s\n", *output)
+	}
+}
+
+// run sends the configured load to one target
+func (t *tester) run(tg target) *TargetReport {
+	report := &TargetReport{Name: tg.name, URL: tg.url, Params: tg.params, Errors: map[string]int{}}
+
+	jobs := make(chan int)
+	results := make(chan RequestResult)
+
+	var wg sync.WaitGroup
+	for w := 0; w < t.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results <- t.send(tg, index)
+			}
+		}()
+	}
+
+	start := time.Now()
+	go t.dispatch(jobs, start)
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	failed := 0
+	for result := range results {
+		report.Results = append(report.Results, result)
+		if result.Error != "" {
+			report.Errors[result.Error]++
+			failed++
+		}
+		if n := len(report.Results); n%10 == 0 {
+			fmt.Printf("   %s: %d requests done (%d failed)\n", tg.name, n, failed)
+		}
+	}
+
+	summarize(report, t.samples, time.Since(start))
+	return report
+}
+
+// dispatch hands out sample indexes until the request count or duration is
+// reached, pacing them when a rate is set
+func (t *tester) dispatch(jobs chan<- int, start time.Time) {
+	defer close(jobs)
+
+	var tick <-chan time.Time
+	if t.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / t.rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for n := 0; t.requests <= 0 || n < t.requests; n++ {
+		if tick != nil && n > 0 {
+			<-tick
+		}
+		if t.duration > 0 && time.Since(start) >= t.duration {
+			return
+		}
+		jobs <- n % len(t.samples)
+	}
+}
+
+// send analyzes one sample and classifies the outcome
+func (t *tester) send(tg target, index int) RequestResult {
+	sample := t.samples[index]
+	body := map[string]interface{}{}
+	for key, value := range tg.params {
+		body[key] = value
+	}
+	body["text_pertanyaan"] = sample.TextPertanyaan
+	body["text_jawaban"] = sample.TextJawaban
+
+	payload, _ := json.Marshal(body)
+	result := RequestResult{Index: index}
+
+	req, err := http.NewRequest(http.MethodPost, tg.url, bytes.NewReader(payload))
+	if err != nil {
+		result.Error = "request_error"
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		req.Header.Set("X-API-Key", t.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		result.LatencyMs = milliseconds(time.Since(start))
+		result.Error = classifyError(err)
+		return result
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	result.LatencyMs = milliseconds(time.Since(start))
+	result.Status = resp.StatusCode
+	if err != nil {
+		result.Error = classifyError(err)
+		return result
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Sprintf("http_%d", resp.StatusCode)
+		result.Response = truncate(string(data))
+		return result
+	}
+
+	var decoded struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Sentiment string `json:"sentiment"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || !decoded.Success || decoded.Data.Sentiment == "" {
+		result.Error = "invalid_response"
+		result.Response = truncate(string(data))
+		return result
+	}
+
+	result.Sentiment = decoded.Data.Sentiment
+	return result
+}
+
+// classifyError names the kind of a transport error
+func classifyError(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	return "connection_error"
+}
+
+// checkHealth calls the health endpoint of a base URL
+func checkHealth(base string) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(joinURL(base, "/health"))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// parseParams decodes a JSON object of extra request fields
+func parseParams(value string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var params map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// joinURL joins a base URL and an endpoint path
+func joinURL(base, path string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// describeRate formats the rate flag for the header
+func describeRate(rate float64) string {
+	if rate <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%.1f req/s", rate)
+}
+
+// describeLength formats the request count and duration for the header
+func describeLength(requests int, duration time.Duration) string {
+	switch {
+	case duration > 0 && requests > 0:
+		return fmt.Sprintf("up to %d requests or %s per target", requests, duration)
+	case duration > 0:
+		return fmt.Sprintf("%s per target", duration)
+	}
+	return fmt.Sprintf("%d requests per target", requests)
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// truncate shortens a response body kept for debugging
+func truncate(body string) string {
+	if len(body) > maxErrorBody {
+		return body[:maxErrorBody] + "..."
+	}
+	return body
+}
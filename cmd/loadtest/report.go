@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"sentiment-api/internal/evaluation"
+)
+
+// tie is reported when neither target wins a criterion
+const tie = "Tie"
+
+// Report is written to the JSON output file
+type Report struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Config      Config              `json:"config"`
+	TestData    []evaluation.Sample `json:"test_data"`
+	Targets     []*TargetReport     `json:"targets"`
+	Comparison  *Comparison         `json:"comparison,omitempty"`
+	Winners     *Winners            `json:"winners,omitempty"`
+}
+
+// Config is the test configuration recorded in the report
+type Config struct {
+	Dataset     string  `json:"dataset"`
+	Concurrency int     `json:"concurrency"`
+	Rate        float64 `json:"rate_per_second"`
+	Duration    string  `json:"duration,omitempty"`
+	Requests    int     `json:"requests_per_target"`
+	Timeout     string  `json:"timeout"`
+}
+
+// TargetReport holds the results of one endpoint
+type TargetReport struct {
+	Name        string                 `json:"name"`
+	URL         string                 `json:"url"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Requests    int                    `json:"requests"`
+	Successful  int                    `json:"successful"`
+	Failed      int                    `json:"failed"`
+	SuccessRate float64                `json:"success_rate"`
+	TotalTimeS  float64                `json:"total_time_s"`
+	// ThroughputRPS counts successful requests per second
+	ThroughputRPS float64         `json:"throughput_rps"`
+	Latency       LatencyStats    `json:"latency_ms"`
+	Errors        map[string]int  `json:"errors"`
+	LabelAccuracy *float64        `json:"label_accuracy,omitempty"`
+	Results       []RequestResult `json:"results"`
+}
+
+// LatencyStats summarizes the latency of successful requests in milliseconds
+type LatencyStats struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P50    float64 `json:"p50"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+}
+
+// RequestResult is the outcome of one request
+type RequestResult struct {
+	Index     int     `json:"index"`
+	Status    int     `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Sentiment string  `json:"sentiment,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	// Response is the raw body of failed requests, kept for debugging
+	Response string `json:"response,omitempty"`
+}
+
+// Comparison is the agreement between the labels of two targets
+type Comparison struct {
+	Compared      int                `json:"compared"`
+	Agreements    int                `json:"agreements"`
+	Disagreements int                `json:"disagreements"`
+	AgreementRate float64            `json:"agreement_rate"`
+	Details       []ComparisonDetail `json:"details"`
+}
+
+// ComparisonDetail compares the labels of one sample
+type ComparisonDetail struct {
+	Index int    `json:"index"`
+	Text  string `json:"text_jawaban"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+	Agree bool   `json:"agree"`
+}
+
+// Winners names the better target per criterion, or "Tie"
+type Winners struct {
+	FasterAverageResponse string `json:"faster_average_response"`
+	LowerP95Latency       string `json:"lower_p95_latency"`
+	HigherSuccessRate     string `json:"higher_success_rate"`
+	HigherThroughput      string `json:"higher_throughput"`
+	FasterTotalTime       string `json:"faster_total_time"`
+}
+
+// summarize fills the aggregate fields of a target report from its results
+func summarize(report *TargetReport, samples []evaluation.Sample, elapsed time.Duration) {
+	report.Requests = len(report.Results)
+	report.TotalTimeS = round(elapsed.Seconds())
+
+	var latencies []float64
+	labeled, correct := 0, 0
+	for _, result := range report.Results {
+		if result.Error != "" {
+			report.Failed++
+			continue
+		}
+		report.Successful++
+		latencies = append(latencies, result.LatencyMs)
+
+		if expected := samples[result.Index].Label; expected != "" {
+			labeled++
+			if strings.EqualFold(expected, result.Sentiment) {
+				correct++
+			}
+		}
+	}
+
+	if report.Requests > 0 {
+		report.SuccessRate = round(float64(report.Successful) / float64(report.Requests) * 100)
+	}
+	if elapsed > 0 {
+		report.ThroughputRPS = round(float64(report.Successful) / elapsed.Seconds())
+	}
+	if labeled > 0 {
+		accuracy := math.Round(float64(correct)/float64(labeled)*10000) / 10000
+		report.LabelAccuracy = &accuracy
+	}
+
+	report.Latency = latencyStats(latencies)
+}
+
+// latencyStats computes min, max, mean and nearest-rank percentiles
+func latencyStats(latencies []float64) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := append([]float64(nil), latencies...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, latency := range sorted {
+		sum += latency
+	}
+
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	return LatencyStats{
+		Min:    round(sorted[0]),
+		Max:    round(sorted[n-1]),
+		Mean:   round(sum / float64(n)),
+		Median: round(median),
+		P50:    round(percentile(sorted, 50)),
+		P95:    round(percentile(sorted, 95)),
+		P99:    round(percentile(sorted, 99)),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// compare measures how often two targets returned the same label for a sample.
+// The first successful result of each sample is used.
+func compare(a, b *TargetReport, samples []evaluation.Sample) *Comparison {
+	first := func(report *TargetReport) map[int]string {
+		labels := make(map[int]string)
+		for _, result := range report.Results {
+			if _, seen := labels[result.Index]; !seen && result.Error == "" {
+				labels[result.Index] = result.Sentiment
+			}
+		}
+		return labels
+	}
+	labelsA, labelsB := first(a), first(b)
+
+	comparison := &Comparison{Details: []ComparisonDetail{}}
+	for index := range samples {
+		labelA, okA := labelsA[index]
+		labelB, okB := labelsB[index]
+		if !okA || !okB {
+			continue
+		}
+
+		detail := ComparisonDetail{
+			Index: index,
+			Text:  samples[index].TextJawaban,
+			A:     labelA,
+			B:     labelB,
+			Agree: labelA == labelB,
+		}
+		comparison.Compared++
+		if detail.Agree {
+			comparison.Agreements++
+		} else {
+			comparison.Disagreements++
+		}
+		comparison.Details = append(comparison.Details, detail)
+	}
+
+	if comparison.Compared > 0 {
+		comparison.AgreementRate = round(float64(comparison.Agreements) / float64(comparison.Compared) * 100)
+	}
+	return comparison
+}
+
+// winners compares two targets per criterion
+func winners(a, b *TargetReport) *Winners {
+	lower := func(x, y float64) string {
+		switch {
+		case x < y:
+			return a.Name
+		case y < x:
+			return b.Name
+		}
+		return tie
+	}
+	higher := func(x, y float64) string {
+		return lower(-x, -y)
+	}
+	// Latency is only measured on successful requests
+	faster := func(x, y float64) string {
+		switch {
+		case a.Successful == 0 && b.Successful == 0:
+			return tie
+		case a.Successful == 0:
+			return b.Name
+		case b.Successful == 0:
+			return a.Name
+		}
+		return lower(x, y)
+	}
+
+	return &Winners{
+		FasterAverageResponse: faster(a.Latency.Mean, b.Latency.Mean),
+		LowerP95Latency:       faster(a.Latency.P95, b.Latency.P95),
+		HigherSuccessRate:     higher(a.SuccessRate, b.SuccessRate),
+		HigherThroughput:      higher(a.ThroughputRPS, b.ThroughputRPS),
+		FasterTotalTime:       lower(a.TotalTimeS, b.TotalTimeS),
+	}
+}
+
+// printResults writes the human-readable results
+func printResults(w io.Writer, report *Report) {
+	line := strings.Repeat("=", 80)
+	fmt.Fprintf(w, "\n🏆 PERFORMANCE TEST RESULTS\n%s\n", line)
+
+	fmt.Fprintln(w, "📊 SUCCESS RATES:")
+	for _, target := range report.Targets {
+		fmt.Fprintf(w, "   %s: %.1f%% (%d/%d)\n", target.Name, target.SuccessRate, target.Successful, target.Requests)
+	}
+
+	fmt.Fprintln(w, "\n⚡ SPEED COMPARISON:")
+	for _, target := range report.Targets {
+		fmt.Fprintf(w, "   %s Total Time: %.2fs\n", target.Name, target.TotalTimeS)
+	}
+	for _, target := range report.Targets {
+		fmt.Fprintf(w, "   %s Avg Response: %.3fs (min %.3fs, median %.3fs, max %.3fs)\n", target.Name,
+			target.Latency.Mean/1000, target.Latency.Min/1000, target.Latency.Median/1000, target.Latency.Max/1000)
+	}
+	for _, target := range report.Targets {
+		fmt.Fprintf(w, "   %s Latency p50/p95/p99: %.0f/%.0f/%.0f ms\n", target.Name,
+			target.Latency.P50, target.Latency.P95, target.Latency.P99)
+	}
+	for _, target := range report.Targets {
+		fmt.Fprintf(w, "   %s Throughput: %.2f req/s\n", target.Name, target.ThroughputRPS)
+	}
+
+	for _, target := range report.Targets {
+		if len(target.Errors) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n❗ ERRORS (%s):\n", target.Name)
+		kinds := make([]string, 0, len(target.Errors))
+		for kind := range target.Errors {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Fprintf(w, "   %s: %d\n", kind, target.Errors[kind])
+		}
+	}
+
+	if report.Targets[0].LabelAccuracy != nil {
+		fmt.Fprintln(w, "\n🎯 LABEL ACCURACY (against dataset labels):")
+		for _, target := range report.Targets {
+			if target.LabelAccuracy != nil {
+				fmt.Fprintf(w, "   %s: %.1f%%\n", target.Name, *target.LabelAccuracy*100)
+			}
+		}
+	}
+
+	if report.Comparison != nil {
+		fmt.Fprintln(w, "\n🎯 ACCURACY COMPARISON:")
+		fmt.Fprintf(w, "   Compared Samples: %d\n", report.Comparison.Compared)
+		fmt.Fprintf(w, "   Agreement Rate: %.1f%%\n", report.Comparison.AgreementRate)
+		fmt.Fprintf(w, "   Agreements: %d\n", report.Comparison.Agreements)
+		fmt.Fprintf(w, "   Disagreements: %d\n", report.Comparison.Disagreements)
+	}
+
+	if report.Winners != nil {
+		fmt.Fprintln(w, "\n🏅 WINNERS:")
+		fmt.Fprintf(w, "   Faster Average Response: %s\n", report.Winners.FasterAverageResponse)
+		fmt.Fprintf(w, "   Lower p95 Latency: %s\n", report.Winners.LowerP95Latency)
+		fmt.Fprintf(w, "   Higher Success Rate: %s\n", report.Winners.HigherSuccessRate)
+		fmt.Fprintf(w, "   Higher Throughput: %s\n", report.Winners.HigherThroughput)
+		fmt.Fprintf(w, "   Faster Total Time: %s\n", report.Winners.FasterTotalTime)
+	}
+}
+
+// writeReport writes the report as indented JSON
+func writeReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// round rounds to two decimals
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,41 @@
+package main
+
+import "sentiment-api/internal/evaluation"
+
+// defaultQuestion is asked for every built-in sample
+const defaultQuestion = "Bagaimana pendapat Anda tentang produk dan layanan kami?"
+
+// builtinSamples are used when no dataset is given: positive, negative and
+// neutral answers plus edge cases with extreme or mixed language
+var builtinSamples = []evaluation.Sample{
+	{TextJawaban: "Produk ini sangat bagus dan berkualitas tinggi, saya sangat puas", Label: "Positif"},
+	{TextJawaban: "Pelayanannya ramah dan cepat, terima kasih banyak", Label: "Positif"},
+	{TextJawaban: "Saya senang sekali dengan hasilnya, melebihi harapan", Label: "Positif"},
+	{TextJawaban: "Harganya terjangkau dan kualitasnya memuaskan", Label: "Positif"},
+	{TextJawaban: "Pengiriman tepat waktu dan barang sesuai deskripsi", Label: "Positif"},
+	{TextJawaban: "Aplikasinya mudah digunakan dan sangat membantu pekerjaan saya", Label: "Positif"},
+	{TextJawaban: "Luar biasa, pasti akan berlangganan lagi", Label: "Positif"},
+	{TextJawaban: "Timnya profesional dan responsif terhadap keluhan", Label: "Positif"},
+	{TextJawaban: "Produk ini sangat buruk dan mengecewakan", Label: "Negatif"},
+	{TextJawaban: "Pelayanannya lambat dan petugasnya tidak ramah", Label: "Negatif"},
+	{TextJawaban: "Barang yang datang rusak dan tidak ada tanggapan dari penjual", Label: "Negatif"},
+	{TextJawaban: "Harganya terlalu mahal untuk kualitas yang jelek", Label: "Negatif"},
+	{TextJawaban: "Aplikasinya sering error dan membuat saya frustrasi", Label: "Negatif"},
+	{TextJawaban: "Saya kecewa, pesanan saya dibatalkan tanpa alasan", Label: "Negatif"},
+	{TextJawaban: "Jaringannya sering putus, sangat mengganggu", Label: "Negatif"},
+	{TextJawaban: "Tidak akan pernah membeli di sini lagi", Label: "Negatif"},
+	{TextJawaban: "Produknya biasa saja, sesuai dengan harganya", Label: "Netral"},
+	{TextJawaban: "Saya belum mencoba semua fiturnya", Label: "Netral"},
+	{TextJawaban: "Pengiriman memakan waktu tiga hari", Label: "Netral"},
+	{TextJawaban: "Saya menggunakan layanan ini untuk keperluan kantor", Label: "Netral"},
+	{TextJawaban: "Tidak ada komentar khusus", Label: "Netral"},
+	{TextJawaban: "Warnanya hitam dan ukurannya sedang", Label: "Netral"},
+	{TextJawaban: "Saya membeli produk ini bulan lalu", Label: "Netral"},
+	{TextJawaban: "Cukup", Label: "Netral"},
+	{TextJawaban: "SAMPAH!!! Layanan terburuk yang pernah ada!!!", Label: "Negatif"},
+	{TextJawaban: "MANTAP BANGET!!! Terbaik pokoknya!!!", Label: "Positif"},
+	{TextJawaban: "Kualitasnya bagus tapi pengirimannya sangat lambat", Label: "Netral"},
+	{TextJawaban: "Wah hebat sekali, baru dipakai sehari sudah rusak", Label: "Negatif"},
+	{TextJawaban: "Harganya mahal, tapi sepadan dengan kualitasnya", Label: "Positif"},
+	{TextJawaban: "Lumayan lah, ada kurang dan lebihnya", Label: "Netral"},
+}
@@ -15,33 +15,93 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/sentiment/analyze": {
-            "post": {
-                "description": "Analyze sentiment of text based on question and answer pair. Returns one of three sentiment types: Positif, Negatif, or Netral",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/analyses": {
+            "get": {
+                "description": "Search stored analysis results, newest first, with cursor pagination. Non-admin callers only see their own results.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sentiment"
+                    "analyses"
                 ],
-                "summary": "Analyze sentiment of text",
+                "summary": "List stored analyses",
                 "parameters": [
                     {
-                        "description": "Sentiment analysis request containing question and answer pair",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.SentimentRequest"
-                        }
+                        "type": "string",
+                        "description": "Sentiment label",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD or RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD or RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Survey ID",
+                        "name": "survey_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Model that produced the result",
+                        "name": "model",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "A/B experiment the result belongs to",
+                        "name": "experiment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum confidence",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum confidence",
+                        "name": "max_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Words that must appear in the answer (requires STORAGE_STORE_TEXT=true)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful sentiment analysis",
+                        "description": "Page of analyses",
                         "schema": {
                             "allOf": [
                                 {
@@ -51,7 +111,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.SentimentResponse"
+                                            "$ref": "#/definitions/storage.AnalysisPage"
                                         }
                                     }
                                 }
@@ -59,7 +119,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid JSON or missing required fields",
+                        "description": "Invalid filter",
                         "schema": {
                             "allOf": [
                                 {
@@ -76,8 +136,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Internal server error - LLM API failure or processing error",
+                    "503": {
+                        "description": "Storage disabled",
                         "schema": {
                             "allOf": [
                                 {
@@ -97,81 +157,1786 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sentiment/types": {
+        "/api/v1/analyses/export": {
             "get": {
-                "description": "Get list of all supported sentiment values that can be returned by the analysis endpoint",
+                "description": "Export every stored analysis matching the filters as CSV or JSON. Accepts the same filters as the list endpoint.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "sentiment"
+                    "analyses"
+                ],
+                "summary": "Export stored analyses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format: csv or json (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get supported sentiment types",
                 "responses": {
                     "200": {
-                        "description": "List of supported sentiment types",
+                        "description": "Exported analyses",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Storage disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/health": {
+        "/api/v1/analyses/summary": {
             "get": {
-                "description": "Check if the Sentiment Analysis API is running and healthy",
+                "description": "Group stored analyses by survey and question and return counts, percentages, net sentiment score and a trend over time buckets. Pass compare_from and compare_to to compare against a baseline period.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "analyses"
+                ],
+                "summary": "Summarize stored analyses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Survey ID",
+                        "name": "survey_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD or RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD or RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trend bucket: day, week or month (default day)",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Baseline period start",
+                        "name": "compare_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Baseline period end, inclusive",
+                        "name": "compare_to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health check endpoint",
                 "responses": {
                     "200": {
-                        "description": "API is healthy and running",
+                        "description": "Summary report",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SummaryReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Storage disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.APIResponse": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "error": {},
-                "success": {
-                    "type": "boolean",
-                    "example": true
-                }
-            }
         },
-        "model.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Invalid request"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "text_pertanyaan and text_jawaban are required"
+        "/api/v1/analyses/{id}/feedback": {
+            "post": {
+                "description": "Record the correct label of a stored analysis, e.g. after human review. Feedback drives the accuracy reported per experiment variant. The label must belong to the label scheme of the analysis (names and synonyms are accepted, ignoring case). Non-admin callers can only label their own analyses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analyses"
+                ],
+                "summary": "Submit feedback on an analysis",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Analysis ID from the response metadata",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Correct label",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FeedbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feedback stored",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or label outside the scheme",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Analysis not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Storage disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
                 }
             }
         },
-        "model.SentimentRequest": {
-            "type": "object",
-            "required": [
-                "text_jawaban",
-                "text_pertanyaan"
+        "/api/v1/experiments/{name}/summary": {
+            "get": {
+                "description": "Report label distribution, average latency, parse-failure rate and feedback accuracy per variant of an experiment from its stored results. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analyses"
+                ],
+                "summary": "Summarize an A/B experiment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Experiment name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-variant summary",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ExperimentSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Storage disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/providers": {
+            "get": {
+                "description": "Report every provider of the failover chain in order with its health, served and failed call counts and average latency. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get LLM provider health",
+                "responses": {
+                    "200": {
+                        "description": "Provider health",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/provider.Stats"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sentiment/analyze": {
+            "post": {
+                "description": "Analyze sentiment of text based on question and answer pair. Returns one of three sentiment types: Positif, Negatif, or Netral",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sentiment"
+                ],
+                "summary": "Analyze sentiment of text",
+                "parameters": [
+                    {
+                        "description": "Sentiment analysis request containing question and answer pair",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SentimentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful sentiment analysis",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SentimentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid JSON or missing required fields",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - LLM API failure or processing error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sentiment/types": {
+            "get": {
+                "description": "Describe the labels of a label scheme, with descriptions and synonyms. Without the scheme parameter the caller's default scheme is described.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sentiment"
+                ],
+                "summary": "Get supported sentiment types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Label scheme ID",
+                        "name": "scheme",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Label scheme",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SentimentTypes"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown label scheme",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shadow/report": {
+            "get": {
+                "description": "Compare the shadow candidate with the served results on the sampled requests since startup: label agreement, the most common disagreements, and latency, token and cost differences. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shadow"
+                ],
+                "summary": "Get the shadow evaluation report",
+                "responses": {
+                    "200": {
+                        "description": "Shadow report",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ShadowReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Shadow mode disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/surveys/submissions": {
+            "post": {
+                "description": "Analyze every question and answer pair of one respondent's questionnaire and return per-question and overall respondent sentiment. Set cross_question_context to analyze all answers in one LLM call.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "surveys"
+                ],
+                "summary": "Analyze a survey submission",
+                "parameters": [
+                    {
+                        "description": "Survey submission",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SurveySubmission"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-question and overall sentiment",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SurveySubmissionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid submission",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "429": {
+                        "description": "Budget exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "LLM API failure",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "LLM capacity exhausted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/surveys/summaries": {
+            "post": {
+                "description": "Write an Indonesian executive summary with the main positive and negative points of the answers to one question. Answers come from the request or, when omitted, from stored analyses of the survey question. Large batches are summarized in chunks and merged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "surveys"
+                ],
+                "summary": "Summarize the answers to a question",
+                "parameters": [
+                    {
+                        "description": "Answers or survey question to summarize",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AnswerSummaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Executive summary",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AnswerSummaryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "429": {
+                        "description": "Budget exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "LLM API failure",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Storage disabled or LLM capacity exhausted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/surveys/topics": {
+            "post": {
+                "description": "Group a batch of answers to one question into themes with LLM topic labels, falling back to local keyword clustering. Answers come from the request or, when omitted, from stored analyses of the survey question. Each theme has its size, example answers and sentiment distribution.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "surveys"
+                ],
+                "summary": "Cluster answers into themes",
+                "parameters": [
+                    {
+                        "description": "Answers or survey question to cluster",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TopicRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Themes",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TopicResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "429": {
+                        "description": "Budget exceeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Storage disabled or LLM capacity exhausted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/usage": {
+            "get": {
+                "description": "Report LLM token usage aggregated per client and per day. Usage is stored with the analyses when storage is enabled and kept in memory otherwise. Non-admin callers only see their own usage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "Get token usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to the first day of the current month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID to report (admin only)",
+                        "name": "client_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage report",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UsageReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to read another client's usage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Usage could not be read",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/model.ErrorResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the Sentiment Analysis API is running and healthy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "API is healthy and running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.APIResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {},
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "model.AnalysisMetadata": {
+            "type": "object",
+            "properties": {
+                "analysis_id": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "experiment": {
+                    "type": "string",
+                    "example": "nuance-prompt"
+                },
+                "label_scheme": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 812
+                },
+                "model": {
+                    "type": "string",
+                    "example": "telkom-ai-instruct"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "telkom-ai"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "short-answers"
+                },
+                "usage": {
+                    "$ref": "#/definitions/model.TokenUsage"
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "treatment"
+                }
+            }
+        },
+        "model.AnswerSummaryRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TopicAnswer"
+                    }
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q2"
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "text_pertanyaan": {
+                    "type": "string",
+                    "example": "Apa yang perlu kami tingkatkan?"
+                }
+            }
+        },
+        "model.AnswerSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "chunks": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "model": {
+                    "type": "string",
+                    "example": "telkom-ai-instruct"
+                },
+                "negative_points": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "positive_points": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q2"
+                },
+                "sentiment_breakdown": {
+                    "$ref": "#/definitions/model.SentimentBreakdown"
+                },
+                "summary": {
+                    "type": "string",
+                    "example": "Sebagian besar responden puas dengan kualitas produk, namun banyak yang mengeluhkan lamanya pengiriman."
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "total_answers": {
+                    "type": "integer",
+                    "example": 2000
+                },
+                "usage": {
+                    "$ref": "#/definitions/model.TokenUsage"
+                }
+            }
+        },
+        "model.AspectSentiment": {
+            "type": "object",
+            "properties": {
+                "aspect": {
+                    "type": "string",
+                    "example": "pengiriman"
+                },
+                "evidence": {
+                    "type": "string",
+                    "example": "pengirimannya lambat sekali"
+                },
+                "sentiment": {
+                    "type": "string",
+                    "example": "Negatif"
+                }
+            }
+        },
+        "model.EmotionScore": {
+            "type": "object",
+            "properties": {
+                "emotion": {
+                    "type": "string",
+                    "example": "kecewa"
+                },
+                "intensity": {
+                    "type": "number",
+                    "example": 0.8
+                }
+            }
+        },
+        "model.EnsembleMember": {
+            "type": "object",
+            "properties": {
+                "agrees": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "engine": {
+                    "type": "string",
+                    "example": "llm:telkom-ai-instruct"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 812
+                },
+                "sentiment": {
+                    "type": "string",
+                    "example": "Positif"
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 1
+                }
+            }
+        },
+        "model.EnsembleVerdict": {
+            "type": "object",
+            "properties": {
+                "agreement": {
+                    "type": "number",
+                    "example": 0.67
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EnsembleMember"
+                    }
+                },
+                "needs_review": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "majority"
+                }
+            }
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid request"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "text_pertanyaan and text_jawaban are required"
+                }
+            }
+        },
+        "model.EvidenceSpan": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "type": "number",
+                    "example": 0.9
+                },
+                "end": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "text": {
+                    "type": "string",
+                    "example": "sangat memuaskan"
+                }
+            }
+        },
+        "model.ExperimentSummary": {
+            "type": "object",
+            "properties": {
+                "experiment": {
+                    "type": "string",
+                    "example": "nuance-prompt"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VariantSummary"
+                    }
+                }
+            }
+        },
+        "model.FeedbackRequest": {
+            "type": "object",
+            "required": [
+                "sentiment"
+            ],
+            "properties": {
+                "sentiment": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Positif"
+                }
+            }
+        },
+        "model.PeriodSummary": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2026-10-01"
+                },
+                "surveys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SurveySummary"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-10-31"
+                }
+            }
+        },
+        "model.QuestionSentiment": {
+            "type": "object",
+            "properties": {
+                "question_id": {
+                    "type": "string",
+                    "example": "Q1"
+                },
+                "reasoning": {
+                    "type": "string"
+                },
+                "sentiment": {
+                    "type": "string",
+                    "example": "Positif"
+                }
+            }
+        },
+        "model.QuestionSummary": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "net_sentiment_score": {
+                    "type": "number",
+                    "example": 35.8
+                },
+                "percentages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q1"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "trend": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TrendPoint"
+                    }
+                }
+            }
+        },
+        "model.SentimentBreakdown": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "net_sentiment_score": {
+                    "type": "number",
+                    "example": 35.8
+                },
+                "percentages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "model.SentimentFlags": {
+            "type": "object",
+            "properties": {
+                "mixed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sarcasm": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "model.SentimentLabel": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Jawaban menunjukkan emosi atau pandangan yang baik"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Positif"
+                },
+                "polarity": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "synonyms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SentimentRequest": {
+            "type": "object",
+            "required": [
+                "text_jawaban",
+                "text_pertanyaan"
+            ],
+            "properties": {
+                "allow_mixed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "aspect_taxonomy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "produk",
+                        "harga"
+                    ]
+                },
+                "aspects": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "detect_nuance": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "emotions": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "ensemble": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "evidence": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "include_metadata": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "label_language": {
+                    "type": "string",
+                    "example": "auto"
+                },
+                "label_scheme": {
+                    "type": "string",
+                    "example": "five-point"
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q1"
+                },
+                "reasoning": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "respondent_id": {
+                    "type": "string",
+                    "example": "R-000123"
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "text_jawaban": {
+                    "type": "string",
+                    "example": "Layanan Anda sangat memuaskan dan responsif"
+                },
+                "text_pertanyaan": {
+                    "type": "string",
+                    "example": "Bagaimana pendapat Anda tentang layanan kami?"
+                }
+            }
+        },
+        "model.SentimentResponse": {
+            "type": "object",
+            "properties": {
+                "aspects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AspectSentiment"
+                    }
+                },
+                "detected_language": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "emotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EmotionScore"
+                    }
+                },
+                "ensemble": {
+                    "$ref": "#/definitions/model.EnsembleVerdict"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EvidenceSpan"
+                    }
+                },
+                "flags": {
+                    "$ref": "#/definitions/model.SentimentFlags"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/model.AnalysisMetadata"
+                },
+                "reasoning": {
+                    "type": "string",
+                    "example": "Teks menunjukkan kepuasan pelanggan dengan kata-kata positif seperti 'memuaskan' dan 'responsif'"
+                },
+                "sentiment": {
+                    "type": "string",
+                    "example": "Positif"
+                }
+            }
+        },
+        "model.SentimentTypes": {
+            "type": "object",
+            "properties": {
+                "available_schemes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "default",
+                        "english",
+                        "five-point"
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SentimentLabel"
+                    }
+                },
+                "mixed_label": {
+                    "type": "string",
+                    "example": "Campuran"
+                },
+                "scheme": {
+                    "type": "string",
+                    "example": "default"
+                }
+            }
+        },
+        "model.ShadowDisagreement": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "primary": {
+                    "type": "string",
+                    "example": "Netral"
+                },
+                "shadow": {
+                    "type": "string",
+                    "example": "Positif"
+                }
+            }
+        },
+        "model.ShadowReport": {
+            "type": "object",
+            "properties": {
+                "agreement_rate": {
+                    "type": "number",
+                    "example": 0.92
+                },
+                "agreements": {
+                    "type": "integer",
+                    "example": 221
+                },
+                "candidate": {
+                    "type": "string",
+                    "example": "llm:telkom-ai-instruct-v2/nuance"
+                },
+                "cost_diff": {
+                    "type": "number",
+                    "example": -0.66
+                },
+                "disagreements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ShadowDisagreement"
+                    }
+                },
+                "dropped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "errors": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "latency_diff_ms": {
+                    "type": "number",
+                    "example": -172
+                },
+                "primary_avg_latency_ms": {
+                    "type": "number",
+                    "example": 812
+                },
+                "primary_cost": {
+                    "type": "number",
+                    "example": 1.56
+                },
+                "primary_tokens": {
+                    "type": "integer",
+                    "example": 31200
+                },
+                "sample_rate": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "samples": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "shadow_avg_latency_ms": {
+                    "type": "number",
+                    "example": 640
+                },
+                "shadow_cost": {
+                    "type": "number",
+                    "example": 0.9
+                },
+                "shadow_tokens": {
+                    "type": "integer",
+                    "example": 29850
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SummaryDelta": {
+            "type": "object",
+            "properties": {
+                "net_score_delta": {
+                    "type": "number",
+                    "example": 4.2
+                },
+                "percentage_deltas": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "present_in_baseline": {
+                    "type": "boolean"
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q1"
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "total_delta": {
+                    "type": "integer",
+                    "example": -15
+                }
+            }
+        },
+        "model.SummaryReport": {
+            "type": "object",
+            "properties": {
+                "baseline": {
+                    "$ref": "#/definitions/model.PeriodSummary"
+                },
+                "bucket": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "comparison": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SummaryDelta"
+                    }
+                },
+                "current": {
+                    "$ref": "#/definitions/model.PeriodSummary"
+                }
+            }
+        },
+        "model.SurveyAnswer": {
+            "type": "object",
+            "required": [
+                "question_id",
+                "text_jawaban",
+                "text_pertanyaan"
             ],
             "properties": {
+                "question_id": {
+                    "type": "string",
+                    "example": "Q1"
+                },
                 "text_jawaban": {
                     "type": "string",
                     "example": "Layanan Anda sangat memuaskan dan responsif"
@@ -182,12 +1947,515 @@ const docTemplate = `{
                 }
             }
         },
-        "model.SentimentResponse": {
+        "model.SurveySubmission": {
             "type": "object",
+            "required": [
+                "answers",
+                "respondent_id",
+                "survey_id"
+            ],
             "properties": {
-                "sentiment": {
+                "answers": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.SurveyAnswer"
+                    }
+                },
+                "cross_question_context": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "label_language": {
+                    "type": "string",
+                    "example": "auto"
+                },
+                "label_scheme": {
+                    "type": "string",
+                    "example": "five-point"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "reasoning": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "respondent_id": {
+                    "type": "string",
+                    "example": "R-000123"
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                }
+            }
+        },
+        "model.SurveySubmissionResponse": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "$ref": "#/definitions/model.AnalysisMetadata"
+                },
+                "overall_reasoning": {
+                    "type": "string"
+                },
+                "overall_sentiment": {
                     "type": "string",
                     "example": "Positif"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.QuestionSentiment"
+                    }
+                },
+                "respondent_id": {
+                    "type": "string",
+                    "example": "R-000123"
+                },
+                "respondent_metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                }
+            }
+        },
+        "model.SurveySummary": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "net_sentiment_score": {
+                    "type": "number",
+                    "example": 35.8
+                },
+                "percentages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.QuestionSummary"
+                    }
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "model.TokenUsage": {
+            "type": "object",
+            "properties": {
+                "completion_tokens": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "prompt_tokens": {
+                    "type": "integer",
+                    "example": 182
+                },
+                "total_tokens": {
+                    "type": "integer",
+                    "example": 191
+                }
+            }
+        },
+        "model.Topic": {
+            "type": "object",
+            "properties": {
+                "answer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "examples": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Kecepatan pengiriman"
+                },
+                "sentiment_breakdown": {
+                    "$ref": "#/definitions/model.SentimentBreakdown"
+                },
+                "share": {
+                    "type": "number",
+                    "example": 35
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "model.TopicAnswer": {
+            "type": "object",
+            "required": [
+                "text_jawaban"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "R-000123"
+                },
+                "label_scheme": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "sentiment": {
+                    "type": "string",
+                    "example": "Negatif"
+                },
+                "text_jawaban": {
+                    "type": "string",
+                    "example": "Pengirimannya terlalu lama"
+                }
+            }
+        },
+        "model.TopicRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TopicAnswer"
+                    }
+                },
+                "engine": {
+                    "type": "string",
+                    "example": "llm"
+                },
+                "max_topics": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q2"
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "text_pertanyaan": {
+                    "type": "string",
+                    "example": "Apa yang perlu kami tingkatkan?"
+                }
+            }
+        },
+        "model.TopicResponse": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "type": "string",
+                    "example": "llm"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "telkom-ai-instruct"
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "Q2"
+                },
+                "survey_id": {
+                    "type": "string",
+                    "example": "CSAT-2026-Q3"
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Topic"
+                    }
+                },
+                "total_answers": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "usage": {
+                    "$ref": "#/definitions/model.TokenUsage"
+                }
+            }
+        },
+        "model.TrendPoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "2026-10-18"
+                },
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "net_sentiment_score": {
+                    "type": "number",
+                    "example": 35.8
+                },
+                "percentages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "model.UsageEntry": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string",
+                    "example": "team-cx"
+                },
+                "completion_tokens": {
+                    "type": "integer",
+                    "example": 1080
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-18"
+                },
+                "prompt_tokens": {
+                    "type": "integer",
+                    "example": 21840
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "total_tokens": {
+                    "type": "integer",
+                    "example": 22920
+                }
+            }
+        },
+        "model.UsageReport": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UsageEntry"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-10-01"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-10-18"
+                },
+                "totals": {
+                    "$ref": "#/definitions/model.UsageEntry"
+                }
+            }
+        },
+        "model.VariantSummary": {
+            "type": "object",
+            "properties": {
+                "avg_latency_ms": {
+                    "type": "number",
+                    "example": 812.5
+                },
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "feedback_accuracy": {
+                    "type": "number",
+                    "example": 0.875
+                },
+                "feedback_correct": {
+                    "type": "integer",
+                    "example": 35
+                },
+                "feedback_count": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "net_sentiment_score": {
+                    "type": "number",
+                    "example": 35.8
+                },
+                "parse_failure_rate": {
+                    "type": "number",
+                    "example": 0.012
+                },
+                "parse_failures": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "percentages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "treatment"
+                }
+            }
+        },
+        "provider.Stats": {
+            "type": "object",
+            "properties": {
+                "avg_latency_ms": {
+                    "type": "integer",
+                    "example": 840
+                },
+                "consecutive_failures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "failures": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "healthy": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "telkom-ai"
+                },
+                "served": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "type": {
+                    "type": "string",
+                    "example": "llm"
+                },
+                "unhealthy_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "storage.AnalysisPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.AnalysisRecord"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "storage.AnalysisRecord": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "experiment": {
+                    "type": "string"
+                },
+                "feedback_label": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "label_scheme": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "parse_failed": {
+                    "type": "boolean"
+                },
+                "prompt_version": {
+                    "type": "string"
+                },
+                "question_id": {
+                    "type": "string"
+                },
+                "reasoning": {
+                    "type": "string"
+                },
+                "respondent_id": {
+                    "type": "string"
+                },
+                "sentiment": {
+                    "type": "string"
+                },
+                "survey_id": {
+                    "type": "string"
+                },
+                "text_hash": {
+                    "type": "string"
+                },
+                "text_jawaban": {
+                    "type": "string"
+                },
+                "text_pertanyaan": {
+                    "type": "string"
+                },
+                "variant": {
+                    "type": "string"
                 }
             }
         }
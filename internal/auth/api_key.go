@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"sentiment-api/internal/config"
+)
+
+// APIKey represents a static API key and its limits
+type APIKey struct {
+	ID                 string   `json:"id"`
+	Key                string   `json:"key"`
+	RateLimitPerMinute int      `json:"rate_limit_per_minute"`
+	DailyRequestQuota  int      `json:"daily_request_quota"`
+	DailyTokenQuota    int      `json:"daily_token_quota"`
+	Scopes             []string `json:"scopes"`
+	DailyBudget        float64  `json:"daily_budget"`
+	MonthlyBudget      float64  `json:"monthly_budget"`
+	LabelScheme        string   `json:"label_scheme"`
+}
+
+// KeyStore holds the configured API keys
+type KeyStore struct {
+	keys map[[sha256.Size]byte]*APIKey
+}
+
+// NewKeyStore builds a key store from the auth configuration.
+// Keys are read from AUTH_API_KEYS ("id:key" pairs separated by commas)
+// and from the JSON file referenced by AUTH_API_KEYS_FILE.
+func NewKeyStore(cfg config.AuthConfig) (*KeyStore, error) {
+	store := &KeyStore{
+		keys: make(map[[sha256.Size]byte]*APIKey),
+	}
+
+	for _, entry := range strings.Split(cfg.APIKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected id:key", entry)
+		}
+
+		if err := store.add(&APIKey{ID: parts[0], Key: parts[1]}, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.APIKeysFile != "" {
+		data, err := os.ReadFile(cfg.APIKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+
+		var keys []*APIKey
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return nil, fmt.Errorf("failed to parse API keys file: %w", err)
+		}
+
+		for _, key := range keys {
+			if err := store.add(key, cfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return store, nil
+}
+
+// add registers a key, filling unset limits with the configured defaults
+func (s *KeyStore) add(key *APIKey, cfg config.AuthConfig) error {
+	if key.ID == "" || key.Key == "" {
+		return errors.New("API key entries require both id and key")
+	}
+	if strings.HasPrefix(key.ID, SubjectPrefix) {
+		return fmt.Errorf("API key id %q must not start with %q, which is reserved for bearer tokens", key.ID, SubjectPrefix)
+	}
+
+	if key.RateLimitPerMinute == 0 {
+		key.RateLimitPerMinute = cfg.DefaultRateLimit
+	}
+	if key.DailyRequestQuota == 0 {
+		key.DailyRequestQuota = cfg.DefaultDailyRequests
+	}
+	if key.DailyTokenQuota == 0 {
+		key.DailyTokenQuota = cfg.DefaultDailyTokens
+	}
+	if len(key.Scopes) == 0 {
+		key.Scopes = ParseScopes(cfg.DefaultScopes)
+	}
+
+	hash := sha256.Sum256([]byte(key.Key))
+	if _, exists := s.keys[hash]; exists {
+		return fmt.Errorf("duplicate API key for id %q", key.ID)
+	}
+	s.keys[hash] = key
+
+	return nil
+}
+
+// Lookup returns the API key matching the presented secret
+func (s *KeyStore) Lookup(secret string) (*APIKey, bool) {
+	hash := sha256.Sum256([]byte(secret))
+	key, ok := s.keys[hash]
+	if !ok || subtle.ConstantTimeCompare([]byte(key.Key), []byte(secret)) != 1 {
+		return nil, false
+	}
+	return key, true
+}
+
+// Len returns the number of configured keys
+func (s *KeyStore) Len() int {
+	return len(s.keys)
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sentiment-api/internal/config"
+)
+
+func TestNewKeyStore(t *testing.T) {
+	defaults := config.AuthConfig{
+		DefaultRateLimit:     60,
+		DefaultDailyRequests: 1000,
+		DefaultDailyTokens:   5000,
+		DefaultScopes:        "sentiment:analyze sentiment:batch",
+	}
+
+	tests := []struct {
+		name    string
+		apiKeys string
+		file    string
+		wantLen int
+		wantErr bool
+	}{
+		{name: "empty", apiKeys: "", wantLen: 0},
+		{name: "env pairs", apiKeys: "team:secret, boss:admin ", wantLen: 2},
+		{name: "missing key", apiKeys: "team:", wantErr: true},
+		{name: "missing separator", apiKeys: "secret", wantErr: true},
+		{name: "duplicate secret", apiKeys: "a:same,b:same", wantErr: true},
+		{
+			name:    "file entries",
+			file:    `[{"id":"svc","key":"k1","rate_limit_per_minute":5,"scopes":["admin"]}]`,
+			wantLen: 1,
+		},
+		{name: "file entry without key", file: `[{"id":"svc"}]`, wantErr: true},
+		{name: "file entry with bearer prefix", file: `[{"id":"jwt:svc","key":"k1"}]`, wantErr: true},
+		{name: "invalid file", file: `{`, wantErr: true},
+		{name: "env and file", apiKeys: "team:secret", file: `[{"id":"svc","key":"k1"}]`, wantLen: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaults
+			cfg.APIKeys = tt.apiKeys
+			if tt.file != "" {
+				cfg.APIKeysFile = writeFile(t, "keys.json", tt.file)
+			}
+
+			store, err := NewKeyStore(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewKeyStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && store.Len() != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", store.Len(), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestNewKeyStoreMissingFile(t *testing.T) {
+	_, err := NewKeyStore(config.AuthConfig{APIKeysFile: filepath.Join(t.TempDir(), "missing.json")})
+	if err == nil {
+		t.Fatal("NewKeyStore() with a missing file returned no error")
+	}
+}
+
+func TestKeyStoreDefaults(t *testing.T) {
+	cfg := config.AuthConfig{
+		APIKeys:              "team:secret",
+		DefaultRateLimit:     60,
+		DefaultDailyRequests: 1000,
+		DefaultDailyTokens:   5000,
+		DefaultScopes:        "sentiment:analyze,sentiment:batch",
+		APIKeysFile: writeFile(t, "keys.json",
+			`[{"id":"svc","key":"k1","rate_limit_per_minute":5,"daily_request_quota":10,"scopes":["admin"],"label_scheme":"english"}]`),
+	}
+
+	store, err := NewKeyStore(cfg)
+	if err != nil {
+		t.Fatalf("NewKeyStore() error = %v", err)
+	}
+
+	tests := []struct {
+		secret string
+		want   APIKey
+	}{
+		{
+			secret: "secret",
+			want: APIKey{
+				ID: "team", Key: "secret",
+				RateLimitPerMinute: 60, DailyRequestQuota: 1000, DailyTokenQuota: 5000,
+				Scopes: []string{"sentiment:analyze", "sentiment:batch"},
+			},
+		},
+		{
+			secret: "k1",
+			want: APIKey{
+				ID: "svc", Key: "k1",
+				RateLimitPerMinute: 5, DailyRequestQuota: 10, DailyTokenQuota: 5000,
+				Scopes: []string{"admin"}, LabelScheme: "english",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.ID, func(t *testing.T) {
+			key, ok := store.Lookup(tt.secret)
+			if !ok {
+				t.Fatalf("Lookup(%q) found no key", tt.secret)
+			}
+			if !reflect.DeepEqual(*key, tt.want) {
+				t.Errorf("Lookup(%q) = %+v, want %+v", tt.secret, *key, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyStoreLookup(t *testing.T) {
+	store, err := NewKeyStore(config.AuthConfig{APIKeys: "team:secret"})
+	if err != nil {
+		t.Fatalf("NewKeyStore() error = %v", err)
+	}
+
+	tests := []struct {
+		secret string
+		wantOK bool
+	}{
+		{secret: "secret", wantOK: true},
+		{secret: "Secret", wantOK: false},
+		{secret: "secret ", wantOK: false},
+		{secret: "team", wantOK: false},
+		{secret: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := store.Lookup(tt.secret); ok != tt.wantOK {
+			t.Errorf("Lookup(%q) ok = %v, want %v", tt.secret, ok, tt.wantOK)
+		}
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "", want: []string{}},
+		{value: "admin", want: []string{"admin"}},
+		{value: "sentiment:analyze sentiment:batch", want: []string{"sentiment:analyze", "sentiment:batch"}},
+		{value: " a,b  c ,", want: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseScopes(tt.value)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseScopes(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPrincipalHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{name: "granted", scopes: []string{ScopeAnalyze}, scope: ScopeAnalyze, want: true},
+		{name: "missing", scopes: []string{ScopeAnalyze}, scope: ScopeBatch, want: false},
+		{name: "admin implies all", scopes: []string{ScopeAdmin}, scope: ScopeBatch, want: true},
+		{name: "no scopes", scopes: nil, scope: ScopeAnalyze, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := &Principal{Scopes: tt.scopes}
+			if got := principal.HasScope(tt.scope); got != tt.want {
+				t.Errorf("HasScope(%q) = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeFile writes content to a file in a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"sentiment-api/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SubjectPrefix namespaces the principal IDs of bearer tokens so a sub claim
+// never shares quotas, usage, budgets or experiment buckets with an API key ID
+const SubjectPrefix = "jwt:"
+
+// JWTValidator validates bearer tokens signed with HS256 or RS256
+type JWTValidator struct {
+	secret     []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	issuer     string
+	audience   string
+	roleScopes map[string][]string
+	limits     APIKey
+}
+
+// jwks represents a JSON Web Key Set document
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// NewJWTValidator creates a validator from the auth configuration.
+// It returns nil when neither a shared secret nor a JWKS file is configured.
+func NewJWTValidator(cfg config.AuthConfig) (*JWTValidator, error) {
+	if cfg.JWTSecret == "" && cfg.JWTJWKSFile == "" {
+		return nil, nil
+	}
+
+	validator := &JWTValidator{
+		secret:     []byte(cfg.JWTSecret),
+		rsaKeys:    make(map[string]*rsa.PublicKey),
+		issuer:     cfg.JWTIssuer,
+		audience:   cfg.JWTAudience,
+		roleScopes: parseRoleScopes(cfg.JWTRoleScopes),
+		limits: APIKey{
+			RateLimitPerMinute: firstPositive(cfg.JWTRateLimit, cfg.DefaultRateLimit),
+			DailyRequestQuota:  firstPositive(cfg.JWTDailyRequests, cfg.DefaultDailyRequests),
+			DailyTokenQuota:    firstPositive(cfg.JWTDailyTokens, cfg.DefaultDailyTokens),
+		},
+	}
+
+	if cfg.JWTJWKSFile != "" {
+		if err := validator.loadJWKS(cfg.JWTJWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return validator, nil
+}
+
+// Validate parses and verifies a token, returning the principal it describes
+func (v *JWTValidator) Validate(tokenString string) (*Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256"}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, v.keyFunc, options...); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("invalid token: missing sub claim")
+	}
+
+	labelScheme, _ := claims["label_scheme"].(string)
+
+	return &Principal{
+		ID:          SubjectPrefix + subject,
+		Method:      "jwt",
+		Scopes:      v.scopesFromClaims(claims),
+		LabelScheme: labelScheme,
+	}, nil
+}
+
+// Limits returns the rate limit and daily quotas of a bearer principal, in
+// the form the quota manager expects. Quotas are keyed by the principal ID.
+func (v *JWTValidator) Limits(principalID string) *APIKey {
+	limits := v.limits
+	limits.ID = principalID
+	return &limits
+}
+
+// keyFunc selects the verification key based on the token's algorithm and key ID
+func (v *JWTValidator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case "HS256":
+		if len(v.secret) == 0 {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return v.secret, nil
+	case "RS256":
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(v.rsaKeys) == 1 {
+			for _, key := range v.rsaKeys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// scopesFromClaims collects scopes from the scope/scp claims and mapped roles
+func (v *JWTValidator) scopesFromClaims(claims jwt.MapClaims) []string {
+	var scopes []string
+
+	for _, name := range []string{"scope", "scp"} {
+		switch value := claims[name].(type) {
+		case string:
+			scopes = append(scopes, ParseScopes(value)...)
+		case []interface{}:
+			for _, item := range value {
+				if scope, ok := item.(string); ok {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, item := range roles {
+			if role, ok := item.(string); ok {
+				scopes = append(scopes, v.roleScopes[role]...)
+			}
+		}
+	}
+
+	return scopes
+}
+
+// loadJWKS reads RSA public keys from a local JWKS file
+func (v *JWTValidator) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		modulus, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return fmt.Errorf("invalid modulus for key %q: %w", key.Kid, err)
+		}
+
+		exponent, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return fmt.Errorf("invalid exponent for key %q: %w", key.Kid, err)
+		}
+
+		v.rsaKeys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+	}
+
+	if len(v.rsaKeys) == 0 {
+		return errors.New("JWKS file contains no RSA signing keys")
+	}
+
+	return nil
+}
+
+// parseRoleScopes parses "role=scope scope;role2=scope" mappings
+func parseRoleScopes(value string) map[string][]string {
+	roleScopes := make(map[string][]string)
+
+	for _, entry := range strings.Split(value, ";") {
+		role, scopes, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || role == "" {
+			continue
+		}
+		roleScopes[role] = ParseScopes(scopes)
+	}
+
+	return roleScopes
+}
+
+// firstPositive returns value, or fallback when value is not positive
+func firstPositive(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,334 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+// signHS256 signs claims with the test secret
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+// signRS256 signs claims with an RSA key, setting kid when not empty
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// writeJWKS writes the public parts of the keys as a JWKS file
+func writeJWKS(t *testing.T, keys map[string]*rsa.PrivateKey) string {
+	t.Helper()
+
+	type jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to encode JWKS: %v", err)
+	}
+	return writeFile(t, "jwks.json", string(data))
+}
+
+// generateKey creates a small RSA key for signing test tokens
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestNewJWTValidatorDisabled(t *testing.T) {
+	validator, err := NewJWTValidator(config.AuthConfig{})
+	if err != nil || validator != nil {
+		t.Errorf("NewJWTValidator() = %v, %v, want nil, nil without secret or JWKS", validator, err)
+	}
+}
+
+func TestJWTValidatorHS256(t *testing.T) {
+	validator, err := NewJWTValidator(config.AuthConfig{
+		JWTSecret:   testSecret,
+		JWTIssuer:   "https://issuer.example",
+		JWTAudience: "sentiment-api",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	claims := func(edit func(jwt.MapClaims)) jwt.MapClaims {
+		c := validClaims()
+		c["iss"] = "https://issuer.example"
+		c["aud"] = "sentiment-api"
+		edit(c)
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: signHS256(t, claims(func(jwt.MapClaims) {}))},
+		{name: "expired", token: signHS256(t, claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })), wantErr: true},
+		{name: "no expiry", token: signHS256(t, claims(func(c jwt.MapClaims) { delete(c, "exp") })), wantErr: true},
+		{name: "wrong issuer", token: signHS256(t, claims(func(c jwt.MapClaims) { c["iss"] = "other" })), wantErr: true},
+		{name: "wrong audience", token: signHS256(t, claims(func(c jwt.MapClaims) { c["aud"] = "other" })), wantErr: true},
+		{name: "no subject", token: signHS256(t, claims(func(c jwt.MapClaims) { delete(c, "sub") })), wantErr: true},
+		{name: "malformed", token: "not.a.token", wantErr: true},
+		{
+			name: "wrong secret",
+			token: func() string {
+				signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims(func(jwt.MapClaims) {})).SignedString([]byte("other"))
+				return signed
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "unsigned",
+			token: func() string {
+				signed, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims(func(jwt.MapClaims) {})).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				return signed
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "HS512 not accepted",
+			token: func() string {
+				signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS512, claims(func(jwt.MapClaims) {})).SignedString([]byte(testSecret))
+				return signed
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := validator.Validate(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (principal.ID != "jwt:user-1" || principal.Method != "jwt") {
+				t.Errorf("Validate() principal = %+v", principal)
+			}
+		})
+	}
+}
+
+func TestJWTValidatorRS256(t *testing.T) {
+	first, second, unknown := generateKey(t), generateKey(t), generateKey(t)
+
+	validator, err := NewJWTValidator(config.AuthConfig{
+		JWTJWKSFile: writeJWKS(t, map[string]*rsa.PrivateKey{"first": first, "second": second}),
+	})
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "first key", token: signRS256(t, first, "first", validClaims())},
+		{name: "second key", token: signRS256(t, second, "second", validClaims())},
+		{name: "kid of another key", token: signRS256(t, first, "second", validClaims()), wantErr: true},
+		{name: "unknown kid", token: signRS256(t, first, "third", validClaims()), wantErr: true},
+		{name: "no kid with several keys", token: signRS256(t, first, "", validClaims()), wantErr: true},
+		{name: "unknown key", token: signRS256(t, unknown, "first", validClaims()), wantErr: true},
+		{name: "HS256 without secret", token: signHS256(t, validClaims()), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := validator.Validate(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTValidatorSingleKeyWithoutKid(t *testing.T) {
+	key := generateKey(t)
+	validator, err := NewJWTValidator(config.AuthConfig{
+		JWTJWKSFile: writeJWKS(t, map[string]*rsa.PrivateKey{"only": key}),
+	})
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	if _, err := validator.Validate(signRS256(t, key, "", validClaims())); err != nil {
+		t.Errorf("Validate() without kid and a single key: %v", err)
+	}
+}
+
+func TestNewJWTValidatorJWKSErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		jwks string
+	}{
+		{name: "invalid JSON", jwks: `{`},
+		{name: "no RSA keys", jwks: `{"keys":[{"kty":"EC","kid":"a"}]}`},
+		{name: "encryption key only", jwks: `{"keys":[{"kty":"RSA","kid":"a","use":"enc","n":"AQAB","e":"AQAB"}]}`},
+		{name: "invalid modulus", jwks: `{"keys":[{"kty":"RSA","kid":"a","n":"!!","e":"AQAB"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTValidator(config.AuthConfig{JWTJWKSFile: writeFile(t, "jwks.json", tt.jwks)})
+			if err == nil {
+				t.Error("NewJWTValidator() returned no error")
+			}
+		})
+	}
+}
+
+func TestJWTValidatorScopes(t *testing.T) {
+	validator, err := NewJWTValidator(config.AuthConfig{
+		JWTSecret:     testSecret,
+		JWTRoleScopes: "analyst=sentiment:analyze sentiment:batch; operator=admin",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   []string
+	}{
+		{name: "none", claims: jwt.MapClaims{}, want: nil},
+		{name: "scope string", claims: jwt.MapClaims{"scope": "sentiment:analyze admin"}, want: []string{"sentiment:analyze", "admin"}},
+		{name: "scp list", claims: jwt.MapClaims{"scp": []interface{}{"sentiment:batch"}}, want: []string{"sentiment:batch"}},
+		{name: "mapped role", claims: jwt.MapClaims{"roles": []interface{}{"analyst"}}, want: []string{"sentiment:analyze", "sentiment:batch"}},
+		{name: "unknown role", claims: jwt.MapClaims{"roles": []interface{}{"guest"}}, want: nil},
+		{
+			name:   "scope and role",
+			claims: jwt.MapClaims{"scope": "sentiment:analyze", "roles": []interface{}{"operator"}},
+			want:   []string{"sentiment:analyze", "admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			for name, value := range tt.claims {
+				claims[name] = value
+			}
+
+			principal, err := validator.Validate(signHS256(t, claims))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if !reflect.DeepEqual(principal.Scopes, tt.want) {
+				t.Errorf("Scopes = %v, want %v", principal.Scopes, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWTValidatorLabelScheme(t *testing.T) {
+	validator, err := NewJWTValidator(config.AuthConfig{JWTSecret: testSecret})
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	claims := validClaims()
+	claims["label_scheme"] = "english"
+	principal, err := validator.Validate(signHS256(t, claims))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if principal.LabelScheme != "english" {
+		t.Errorf("LabelScheme = %q, want english", principal.LabelScheme)
+	}
+}
+
+func TestJWTValidatorLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AuthConfig
+		want APIKey
+	}{
+		{
+			name: "defaults",
+			cfg:  config.AuthConfig{DefaultRateLimit: 60, DefaultDailyRequests: 1000, DefaultDailyTokens: 500},
+			want: APIKey{ID: "user-1", RateLimitPerMinute: 60, DailyRequestQuota: 1000, DailyTokenQuota: 500},
+		},
+		{
+			name: "JWT limits override defaults",
+			cfg: config.AuthConfig{
+				DefaultRateLimit: 60, DefaultDailyRequests: 1000,
+				JWTRateLimit: 10, JWTDailyRequests: 20, JWTDailyTokens: 30,
+			},
+			want: APIKey{ID: "user-1", RateLimitPerMinute: 10, DailyRequestQuota: 20, DailyTokenQuota: 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.JWTSecret = testSecret
+			validator, err := NewJWTValidator(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewJWTValidator() error = %v", err)
+			}
+
+			if got := validator.Limits("user-1"); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Limits() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import "context"
+
+type principalKey struct{}
+
+// Principal represents the authenticated caller of a request
+type Principal struct {
+	ID            string
+	Method        string
+	Scopes        []string
+	DailyBudget   float64
+	MonthlyBudget float64
+	LabelScheme   string
+}
+
+// WithPrincipal returns a copy of ctx carrying the given principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// ClientID returns the ID of the principal stored in ctx, or "anonymous"
+func ClientID(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.ID
+	}
+	return "anonymous"
+}
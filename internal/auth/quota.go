@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// QuotaStatus describes the outcome of a quota check
+type QuotaStatus struct {
+	Allowed           bool
+	Reason            string
+	RateLimit         int
+	RateRemaining     int
+	RateReset         time.Time
+	RequestQuota      int
+	RequestsRemaining int
+	TokenQuota        int
+	TokensRemaining   int
+	QuotaReset        time.Time
+}
+
+// clientUsage tracks the rate limiter bucket and daily counters for one key
+type clientUsage struct {
+	tokens     float64
+	lastRefill time.Time
+	day        string
+	requests   int
+	llmTokens  int
+}
+
+// QuotaManager enforces per-key rate limits and daily quotas
+type QuotaManager struct {
+	mu      sync.Mutex
+	clients map[string]*clientUsage
+	now     func() time.Time
+}
+
+// NewQuotaManager creates a new quota manager
+func NewQuotaManager() *QuotaManager {
+	return &QuotaManager{
+		clients: make(map[string]*clientUsage),
+		now:     time.Now,
+	}
+}
+
+// Allow checks and consumes one request for the given key.
+// A zero limit means the corresponding check is disabled.
+func (q *QuotaManager) Allow(key *APIKey) QuotaStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now().UTC()
+	usage := q.usageFor(key, now)
+
+	status := QuotaStatus{
+		Allowed:      true,
+		RateLimit:    key.RateLimitPerMinute,
+		RequestQuota: key.DailyRequestQuota,
+		TokenQuota:   key.DailyTokenQuota,
+		QuotaReset:   nextDay(now),
+	}
+
+	if key.RateLimitPerMinute > 0 {
+		perSecond := float64(key.RateLimitPerMinute) / 60
+		usage.tokens += now.Sub(usage.lastRefill).Seconds() * perSecond
+		if usage.tokens > float64(key.RateLimitPerMinute) {
+			usage.tokens = float64(key.RateLimitPerMinute)
+		}
+		usage.lastRefill = now
+
+		if usage.tokens < 1 {
+			wait := time.Duration((1 - usage.tokens) / perSecond * float64(time.Second))
+			status.Allowed = false
+			status.Reason = "rate limit exceeded"
+			status.RateReset = now.Add(wait)
+		}
+	}
+
+	if status.Allowed && key.DailyRequestQuota > 0 && usage.requests >= key.DailyRequestQuota {
+		status.Allowed = false
+		status.Reason = "daily request quota exceeded"
+	}
+
+	if status.Allowed && key.DailyTokenQuota > 0 && usage.llmTokens >= key.DailyTokenQuota {
+		status.Allowed = false
+		status.Reason = "daily token quota exceeded"
+	}
+
+	if status.Allowed {
+		if key.RateLimitPerMinute > 0 {
+			usage.tokens--
+		}
+		usage.requests++
+	}
+
+	if key.RateLimitPerMinute > 0 {
+		status.RateRemaining = int(usage.tokens)
+		if status.RateReset.IsZero() {
+			missing := float64(key.RateLimitPerMinute) - usage.tokens
+			status.RateReset = now.Add(time.Duration(missing / (float64(key.RateLimitPerMinute) / 60) * float64(time.Second)))
+		}
+	}
+	status.RequestsRemaining = remaining(key.DailyRequestQuota, usage.requests)
+	status.TokensRemaining = remaining(key.DailyTokenQuota, usage.llmTokens)
+
+	return status
+}
+
+// AddTokens records LLM tokens consumed by the given key today
+func (q *QuotaManager) AddTokens(keyID string, tokens int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now().UTC()
+	usage, exists := q.clients[keyID]
+	if !exists {
+		return
+	}
+
+	if day := now.Format("2006-01-02"); usage.day != day {
+		usage.day = day
+		usage.requests = 0
+		usage.llmTokens = 0
+	}
+	usage.llmTokens += tokens
+}
+
+// usageFor returns the usage record for a key, resetting daily counters when the day changes
+func (q *QuotaManager) usageFor(key *APIKey, now time.Time) *clientUsage {
+	usage, exists := q.clients[key.ID]
+	if !exists {
+		usage = &clientUsage{
+			tokens:     float64(key.RateLimitPerMinute),
+			lastRefill: now,
+		}
+		q.clients[key.ID] = usage
+	}
+
+	day := now.Format("2006-01-02")
+	if usage.day != day {
+		usage.day = day
+		usage.requests = 0
+		usage.llmTokens = 0
+	}
+
+	return usage
+}
+
+// remaining returns how much of a limit is left, or -1 when the limit is disabled
+func remaining(limit, used int) int {
+	if limit <= 0 {
+		return -1
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+// nextDay returns the start of the next UTC day
+func nextDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for the quota manager
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestQuotaManager(start time.Time) (*QuotaManager, *fakeClock) {
+	clock := &fakeClock{now: start}
+	manager := NewQuotaManager()
+	manager.now = clock.Now
+	return manager, clock
+}
+
+func TestQuotaManagerRateLimit(t *testing.T) {
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	key := &APIKey{ID: "team", RateLimitPerMinute: 3}
+
+	tests := []struct {
+		name          string
+		advance       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{name: "first request", wantAllowed: true, wantRemaining: 2},
+		{name: "second request", wantAllowed: true, wantRemaining: 1},
+		{name: "third request", wantAllowed: true, wantRemaining: 0},
+		{name: "bucket empty", wantAllowed: false, wantRemaining: 0},
+		{name: "half a token refilled", advance: 10 * time.Second, wantAllowed: false, wantRemaining: 0},
+		{name: "one token refilled", advance: 10 * time.Second, wantAllowed: true, wantRemaining: 0},
+		{name: "refill capped at limit", advance: time.Hour, wantAllowed: true, wantRemaining: 2},
+	}
+
+	manager, clock := newTestQuotaManager(start)
+	for _, tt := range tests {
+		clock.Advance(tt.advance)
+		status := manager.Allow(key)
+
+		if status.Allowed != tt.wantAllowed {
+			t.Fatalf("%s: Allowed = %v, want %v (reason %q)", tt.name, status.Allowed, tt.wantAllowed, status.Reason)
+		}
+		if status.RateRemaining != tt.wantRemaining {
+			t.Errorf("%s: RateRemaining = %d, want %d", tt.name, status.RateRemaining, tt.wantRemaining)
+		}
+		if !tt.wantAllowed {
+			if status.Reason != "rate limit exceeded" {
+				t.Errorf("%s: Reason = %q, want rate limit exceeded", tt.name, status.Reason)
+			}
+			if !status.RateReset.After(clock.now) {
+				t.Errorf("%s: RateReset %v is not in the future", tt.name, status.RateReset)
+			}
+		}
+	}
+}
+
+func TestQuotaManagerDailyRequests(t *testing.T) {
+	start := time.Date(2026, 3, 10, 23, 59, 0, 0, time.UTC)
+	key := &APIKey{ID: "team", DailyRequestQuota: 2}
+
+	manager, clock := newTestQuotaManager(start)
+
+	for i, wantAllowed := range []bool{true, true, false, false} {
+		status := manager.Allow(key)
+		if status.Allowed != wantAllowed {
+			t.Fatalf("request %d: Allowed = %v, want %v", i+1, status.Allowed, wantAllowed)
+		}
+		if !wantAllowed && status.Reason != "daily request quota exceeded" {
+			t.Errorf("request %d: Reason = %q", i+1, status.Reason)
+		}
+		if want := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC); !status.QuotaReset.Equal(want) {
+			t.Errorf("request %d: QuotaReset = %v, want %v", i+1, status.QuotaReset, want)
+		}
+	}
+
+	// Counters reset at the start of the next UTC day
+	clock.Advance(time.Minute)
+	status := manager.Allow(key)
+	if !status.Allowed || status.RequestsRemaining != 1 {
+		t.Errorf("after midnight: Allowed = %v, RequestsRemaining = %d, want true, 1", status.Allowed, status.RequestsRemaining)
+	}
+}
+
+func TestQuotaManagerDailyTokens(t *testing.T) {
+	start := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	key := &APIKey{ID: "team", DailyTokenQuota: 100}
+
+	tests := []struct {
+		name          string
+		addTokens     int
+		advance       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{name: "no tokens used", wantAllowed: true, wantRemaining: 100},
+		{name: "partly used", addTokens: 60, wantAllowed: true, wantRemaining: 40},
+		{name: "quota reached", addTokens: 40, wantAllowed: false, wantRemaining: 0},
+		{name: "next day", advance: 24 * time.Hour, wantAllowed: true, wantRemaining: 100},
+	}
+
+	manager, clock := newTestQuotaManager(start)
+	for _, tt := range tests {
+		clock.Advance(tt.advance)
+		manager.AddTokens(key.ID, tt.addTokens)
+
+		status := manager.Allow(key)
+		if status.Allowed != tt.wantAllowed {
+			t.Fatalf("%s: Allowed = %v, want %v", tt.name, status.Allowed, tt.wantAllowed)
+		}
+		if status.TokensRemaining != tt.wantRemaining {
+			t.Errorf("%s: TokensRemaining = %d, want %d", tt.name, status.TokensRemaining, tt.wantRemaining)
+		}
+		if !tt.wantAllowed && status.Reason != "daily token quota exceeded" {
+			t.Errorf("%s: Reason = %q", tt.name, status.Reason)
+		}
+	}
+}
+
+func TestQuotaManagerDisabledLimits(t *testing.T) {
+	manager, _ := newTestQuotaManager(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+	key := &APIKey{ID: "unlimited"}
+
+	for i := 0; i < 1000; i++ {
+		if status := manager.Allow(key); !status.Allowed {
+			t.Fatalf("request %d rejected: %s", i+1, status.Reason)
+		}
+	}
+
+	status := manager.Allow(key)
+	if status.RequestsRemaining != -1 || status.TokensRemaining != -1 {
+		t.Errorf("remaining = %d/%d, want -1/-1 for disabled limits", status.RequestsRemaining, status.TokensRemaining)
+	}
+}
+
+func TestQuotaManagerKeysAreIndependent(t *testing.T) {
+	manager, _ := newTestQuotaManager(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+	first := &APIKey{ID: "first", DailyRequestQuota: 1}
+	second := &APIKey{ID: "second", DailyRequestQuota: 1}
+
+	if !manager.Allow(first).Allowed {
+		t.Fatal("first key rejected on its first request")
+	}
+	if manager.Allow(first).Allowed {
+		t.Fatal("first key allowed beyond its quota")
+	}
+	if !manager.Allow(second).Allowed {
+		t.Error("second key rejected because of the first key's usage")
+	}
+}
+
+func TestQuotaManagerAddTokensUnknownKey(t *testing.T) {
+	manager, _ := newTestQuotaManager(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+	manager.AddTokens("unknown", 500)
+
+	key := &APIKey{ID: "unknown", DailyTokenQuota: 100}
+	if status := manager.Allow(key); !status.Allowed || status.TokensRemaining != 100 {
+		t.Errorf("tokens recorded before the first request were counted: %+v", status)
+	}
+}
@@ -0,0 +1,28 @@
+package auth
+
+import "strings"
+
+// Supported authorization scopes
+const (
+	ScopeAnalyze = "sentiment:analyze"
+	ScopeBatch   = "sentiment:batch"
+	ScopeAdmin   = "admin"
+)
+
+// HasScope reports whether the principal was granted the given scope.
+// The admin scope implies every other scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScopes splits a space or comma separated scope list
+func ParseScopes(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+}
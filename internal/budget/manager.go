@@ -0,0 +1,320 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/config"
+	"sentiment-api/internal/engine"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrBudgetExceeded is returned when a client has spent its budget and downgrading is disabled
+var ErrBudgetExceeded = errors.New("LLM budget exceeded for this client")
+
+// Action describes what to do with a request given the client's spend
+type Action int
+
+const (
+	// ActionAllow lets the request use the requested engine
+	ActionAllow Action = iota
+	// ActionDowngrade routes the request to the cheaper fallback engine
+	ActionDowngrade
+	// ActionReject refuses the request
+	ActionReject
+)
+
+// Price represents the cost of one thousand tokens for a model
+type Price struct {
+	InputPer1K  float64 `json:"input_per_1k"`
+	OutputPer1K float64 `json:"output_per_1k"`
+}
+
+// Caps represents the spend limits of one client; zero disables a cap
+type Caps struct {
+	Daily   float64
+	Monthly float64
+}
+
+// Decision is the outcome of a budget check
+type Decision struct {
+	Action       Action
+	Reason       string
+	DailySpend   float64
+	MonthlySpend float64
+}
+
+// spend tracks the spend of one client in the current day and month
+type spend struct {
+	day     string
+	daily   float64
+	month   string
+	monthly float64
+	warned  map[string]bool
+}
+
+// Manager converts token usage to cost and enforces per-client caps
+type Manager struct {
+	mu             sync.Mutex
+	prices         map[string]Price
+	defaults       Caps
+	thresholds     []float64
+	downgrade      bool
+	fallbackEngine string
+	currency       string
+	clients        map[string]*spend
+	unpriced       map[string]bool
+	now            func() time.Time
+}
+
+// NewManager creates a budget manager from the budget configuration
+func NewManager(cfg config.BudgetConfig) (*Manager, error) {
+	manager := &Manager{
+		prices:         make(map[string]Price),
+		defaults:       Caps{Daily: cfg.DefaultDailyCap, Monthly: cfg.DefaultMonthlyCap},
+		downgrade:      cfg.ExceededAction == "downgrade",
+		fallbackEngine: cfg.FallbackEngine,
+		currency:       cfg.Currency,
+		clients:        make(map[string]*spend),
+		unpriced:       make(map[string]bool),
+		now:            time.Now,
+	}
+
+	if cfg.ExceededAction != "downgrade" && cfg.ExceededAction != "reject" {
+		return nil, fmt.Errorf("invalid budget exceeded action %q, expected downgrade or reject", cfg.ExceededAction)
+	}
+
+	// Downgraded requests must not spend more, so only the offline engine qualifies
+	if manager.downgrade && cfg.FallbackEngine != engine.LexiconName {
+		return nil, fmt.Errorf("invalid budget fallback engine %q, expected %s", cfg.FallbackEngine, engine.LexiconName)
+	}
+
+	for _, value := range strings.Split(cfg.WarningThresholds, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return nil, fmt.Errorf("invalid budget warning threshold %q", value)
+		}
+		manager.thresholds = append(manager.thresholds, threshold)
+	}
+	sort.Float64s(manager.thresholds)
+
+	if cfg.PricesFile != "" {
+		data, err := os.ReadFile(cfg.PricesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read price table: %w", err)
+		}
+		if err := json.Unmarshal(data, &manager.prices); err != nil {
+			return nil, fmt.Errorf("failed to parse price table: %w", err)
+		}
+	}
+
+	return manager, nil
+}
+
+// Restore rebuilds the current day's and month's spend from stored usage so
+// caps survive restarts. Stored tokens are priced with the current price
+// table. It must run before the manager serves requests.
+func (m *Manager) Restore(ctx context.Context, repository storage.UsageRepository) error {
+	now := m.now().UTC()
+	today := now.Format("2006-01-02")
+
+	records, err := repository.QueryUsage(ctx, now.Format("2006-01")+"-01", today, "")
+	if err != nil {
+		return fmt.Errorf("failed to restore budget spend: %w", err)
+	}
+
+	clients := make(map[string]bool)
+	for _, record := range records {
+		cost := m.Cost(record.Model, model.TokenUsage{
+			PromptTokens:     record.PromptTokens,
+			CompletionTokens: record.CompletionTokens,
+			TotalTokens:      record.TotalTokens,
+		})
+		if cost == 0 {
+			continue
+		}
+
+		m.mu.Lock()
+		current := m.spendFor(record.ClientID)
+		current.monthly += cost
+		if record.Date == today {
+			current.daily += cost
+		}
+		m.mu.Unlock()
+		clients[record.ClientID] = true
+	}
+
+	logger.LogInfo("Restored budget spend from stored usage", logrus.Fields{
+		"clients": len(clients),
+	})
+	return nil
+}
+
+// FallbackEngine returns the engine used when a client is downgraded
+func (m *Manager) FallbackEngine() string {
+	return m.fallbackEngine
+}
+
+// Cost converts token usage of a model into spend. Models missing from the
+// price table cost nothing; a warning is logged once per model.
+func (m *Manager) Cost(modelName string, usage model.TokenUsage) float64 {
+	price, ok := m.prices[modelName]
+	if !ok {
+		if usage.TotalTokens > 0 {
+			m.warnUnpriced(modelName)
+		}
+		return 0
+	}
+	return float64(usage.PromptTokens)/1000*price.InputPer1K + float64(usage.CompletionTokens)/1000*price.OutputPer1K
+}
+
+// warnUnpriced logs the first use of a model without a price entry
+func (m *Manager) warnUnpriced(modelName string) {
+	m.mu.Lock()
+	warned := m.unpriced[modelName]
+	m.unpriced[modelName] = true
+	m.mu.Unlock()
+
+	if !warned {
+		logger.LogWarn("Model has no price entry, its usage is not charged to budgets", logrus.Fields{
+			"model": modelName,
+		})
+	}
+}
+
+// Check decides whether a client may still use the LLM
+func (m *Manager) Check(clientID string, caps Caps) Decision {
+	caps = m.withDefaults(caps)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := m.spendFor(clientID)
+	decision := Decision{
+		Action:       ActionAllow,
+		DailySpend:   current.daily,
+		MonthlySpend: current.monthly,
+	}
+
+	switch {
+	case caps.Monthly > 0 && current.monthly >= caps.Monthly:
+		decision.Reason = "monthly budget exceeded"
+	case caps.Daily > 0 && current.daily >= caps.Daily:
+		decision.Reason = "daily budget exceeded"
+	default:
+		return decision
+	}
+
+	if m.downgrade && m.fallbackEngine != "" {
+		decision.Action = ActionDowngrade
+	} else {
+		decision.Action = ActionReject
+	}
+
+	return decision
+}
+
+// Record adds the cost of a call to the client's spend and emits threshold warnings
+func (m *Manager) Record(clientID, modelName string, usage model.TokenUsage, caps Caps) {
+	cost := m.Cost(modelName, usage)
+	if cost == 0 {
+		return
+	}
+	caps = m.withDefaults(caps)
+
+	m.mu.Lock()
+	current := m.spendFor(clientID)
+	previousDaily, previousMonthly := current.daily, current.monthly
+	current.daily += cost
+	current.monthly += cost
+
+	var warnings []logrus.Fields
+	for _, threshold := range m.thresholds {
+		if caps.Daily > 0 && previousDaily < caps.Daily*threshold && current.daily >= caps.Daily*threshold {
+			warnings = append(warnings, m.warningLocked(current, clientID, "daily", threshold, current.daily, caps.Daily))
+		}
+		if caps.Monthly > 0 && previousMonthly < caps.Monthly*threshold && current.monthly >= caps.Monthly*threshold {
+			warnings = append(warnings, m.warningLocked(current, clientID, "monthly", threshold, current.monthly, caps.Monthly))
+		}
+	}
+	m.mu.Unlock()
+
+	for _, fields := range warnings {
+		if fields != nil {
+			logger.LogWarn("Client budget threshold reached", fields)
+		}
+	}
+}
+
+// warningLocked returns log fields for a threshold warning, once per period
+func (m *Manager) warningLocked(current *spend, clientID, period string, threshold, spent, limit float64) logrus.Fields {
+	key := fmt.Sprintf("%s:%s:%.2f", period, current.day, threshold)
+	if period == "monthly" {
+		key = fmt.Sprintf("%s:%s:%.2f", period, current.month, threshold)
+	}
+	if current.warned[key] {
+		return nil
+	}
+	current.warned[key] = true
+
+	return logrus.Fields{
+		"client_id": clientID,
+		"period":    period,
+		"threshold": threshold,
+		"spent":     spent,
+		"limit":     limit,
+		"currency":  m.currency,
+	}
+}
+
+// spendFor returns the spend record of a client, resetting expired periods
+func (m *Manager) spendFor(clientID string) *spend {
+	now := m.now().UTC()
+	day := now.Format("2006-01-02")
+	month := now.Format("2006-01")
+
+	current, exists := m.clients[clientID]
+	if !exists {
+		current = &spend{warned: make(map[string]bool)}
+		m.clients[clientID] = current
+	}
+
+	if current.day != day {
+		current.day = day
+		current.daily = 0
+	}
+	if current.month != month {
+		current.month = month
+		current.monthly = 0
+		current.warned = make(map[string]bool)
+	}
+
+	return current
+}
+
+// withDefaults fills unset caps with the configured defaults
+func (m *Manager) withDefaults(caps Caps) Caps {
+	if caps.Daily == 0 {
+		caps.Daily = m.defaults.Daily
+	}
+	if caps.Monthly == 0 {
+		caps.Monthly = m.defaults.Monthly
+	}
+	return caps
+}
@@ -0,0 +1,158 @@
+package budget
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/config"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	os.Exit(m.Run())
+}
+
+func TestNewManagerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.BudgetConfig
+		wantErr bool
+	}{
+		{name: "downgrade to lexicon", cfg: config.BudgetConfig{ExceededAction: "downgrade", FallbackEngine: "lexicon"}},
+		{name: "reject ignores fallback", cfg: config.BudgetConfig{ExceededAction: "reject", FallbackEngine: "anything"}},
+		{name: "downgrade to unknown engine", cfg: config.BudgetConfig{ExceededAction: "downgrade", FallbackEngine: "indobert"}, wantErr: true},
+		{name: "downgrade to LLM", cfg: config.BudgetConfig{ExceededAction: "downgrade", FallbackEngine: "llm"}, wantErr: true},
+		{name: "downgrade without fallback", cfg: config.BudgetConfig{ExceededAction: "downgrade"}, wantErr: true},
+		{name: "unknown action", cfg: config.BudgetConfig{ExceededAction: "block", FallbackEngine: "lexicon"}, wantErr: true},
+		{name: "invalid threshold", cfg: config.BudgetConfig{ExceededAction: "reject", WarningThresholds: "0.5,2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewManager(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("NewManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writePrices writes a price table charging 2 per 1K input and 6 per 1K output tokens
+func writePrices(t *testing.T) string {
+	t.Helper()
+
+	prices := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(prices, []byte(`{"telkom-ai-instruct": {"input_per_1k": 2, "output_per_1k": 6}}`), 0o600); err != nil {
+		t.Fatalf("failed to write price table: %v", err)
+	}
+	return prices
+}
+
+func TestManagerCost(t *testing.T) {
+	manager, err := NewManager(config.BudgetConfig{ExceededAction: "reject", PricesFile: writePrices(t)})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		model string
+		usage model.TokenUsage
+		want  float64
+	}{
+		{name: "priced model", model: "telkom-ai-instruct", usage: model.TokenUsage{PromptTokens: 500, CompletionTokens: 250, TotalTokens: 750}, want: 2.5},
+		{name: "unpriced model", model: "other", usage: model.TokenUsage{PromptTokens: 500, TotalTokens: 500}, want: 0},
+		{name: "no usage", model: "other", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.Cost(tt.model, tt.usage); got != tt.want {
+				t.Errorf("Cost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !manager.unpriced["other"] {
+		t.Error("unpriced model was not recorded for a warning")
+	}
+}
+
+func TestManagerRestoreKeepsCapsAcrossRestarts(t *testing.T) {
+	repository, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "budget.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	defer repository.Close()
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	cfg := config.BudgetConfig{ExceededAction: "reject", PricesFile: writePrices(t)}
+	caps := Caps{Daily: 2}
+
+	// Spend 2.5 today through the first manager, storing usage as the tracker does
+	first, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	first.now = func() time.Time { return now }
+	today := model.TokenUsage{PromptTokens: 500, CompletionTokens: 250, TotalTokens: 750}
+	first.Record("team", "telkom-ai-instruct", today, caps)
+
+	records := []storage.UsageRecord{
+		{ClientID: "team", Date: "2026-10-18", Model: "telkom-ai-instruct", Requests: 1, PromptTokens: 500, CompletionTokens: 250, TotalTokens: 750},
+		{ClientID: "team", Date: "2026-10-17", Model: "telkom-ai-instruct", Requests: 1, PromptTokens: 1000, TotalTokens: 1000},
+		{ClientID: "team", Date: "2026-09-30", Model: "telkom-ai-instruct", Requests: 1, PromptTokens: 5000, TotalTokens: 5000},
+		{ClientID: "team", Date: "2026-10-18", Model: "unpriced", Requests: 1, PromptTokens: 5000, TotalTokens: 5000},
+	}
+	for _, record := range records {
+		if err := repository.AddUsage(context.Background(), record); err != nil {
+			t.Fatalf("AddUsage() error = %v", err)
+		}
+	}
+
+	if decision := first.Check("team", caps); decision.Action != ActionReject {
+		t.Fatalf("first manager Check() action = %v, want reject", decision.Action)
+	}
+
+	// After a restart the manager starts empty until it restores stored usage
+	restarted, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	restarted.now = func() time.Time { return now }
+	if err := restarted.Restore(context.Background(), repository); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		clientID   string
+		caps       Caps
+		wantAction Action
+		wantReason string
+	}{
+		{name: "daily cap still applies", clientID: "team", caps: Caps{Daily: 2}, wantAction: ActionReject, wantReason: "daily budget exceeded"},
+		{name: "monthly cap counts earlier days", clientID: "team", caps: Caps{Monthly: 4}, wantAction: ActionReject, wantReason: "monthly budget exceeded"},
+		{name: "within caps", clientID: "team", caps: Caps{Daily: 3, Monthly: 5}, wantAction: ActionAllow},
+		{name: "other client", clientID: "boss", caps: Caps{Daily: 2}, wantAction: ActionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := restarted.Check(tt.clientID, tt.caps)
+			if decision.Action != tt.wantAction || decision.Reason != tt.wantReason {
+				t.Errorf("Check() = %v %q, want %v %q", decision.Action, decision.Reason, tt.wantAction, tt.wantReason)
+			}
+		})
+	}
+
+	// Last month's usage is outside the restored period
+	if decision := restarted.Check("team", Caps{}); decision.DailySpend != 2.5 || decision.MonthlySpend != 4.5 {
+		t.Errorf("spend = %v daily, %v monthly, want 2.5 and 4.5", decision.DailySpend, decision.MonthlySpend)
+	}
+}
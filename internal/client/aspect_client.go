@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AspectPromptVersion identifies the revision of the aspect prompt
+const AspectPromptVersion = "aspect-v1"
+
+// AspectResult represents the outcome of an aspect-based analysis call
+type AspectResult struct {
+	Aspects []model.AspectSentiment
+	Model   string
+	Usage   *model.TokenUsage
+}
+
+// AnalyzeAspects extracts the aspects of the taxonomy mentioned in the answer,
+// with a sentiment from the labels of scheme and an evidence span for each
+func (c *LLMClient) AnalyzeAspects(ctx context.Context, textPertanyaan, textJawaban string, taxonomy []string, scheme *labels.Scheme) (*AspectResult, error) {
+	systemPrompt := fmt.Sprintf(`Anda adalah sistem analisis sentimen berbasis aspek yang sangat akurat. Tugas Anda adalah menemukan aspek yang dibahas dalam jawaban dan menentukan sentimen untuk masing-masing aspek.
+
+Daftar aspek yang boleh digunakan: %s
+
+Untuk setiap aspek yang benar-benar dibahas dalam jawaban, tentukan:
+- aspect: salah satu nama dari daftar aspek di atas
+- sentiment: %s
+- evidence: potongan teks yang disalin persis dari jawaban yang menjadi bukti
+
+Jangan sertakan aspek yang tidak dibahas. Respons Anda harus dalam format JSON yang valid:
+{"aspects": [{"aspect": "produk", "sentiment": "%s", "evidence": "Produknya bagus"}]}
+
+Jika tidak ada aspek yang dibahas, kembalikan {"aspects": []}.`, strings.Join(taxonomy, ", "), labelList(scheme, "atau"), scheme.Labels[0].Name)
+
+	userPrompt := fmt.Sprintf(`Pertanyaan: %s
+
+Jawaban: %s
+
+Identifikasi aspek yang dibahas dalam jawaban beserta sentimen dan buktinya.`, textPertanyaan, textJawaban)
+
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt,
+		},
+	}
+
+	modelName := DefaultModel
+	result, usage, err := c.CallTelkomAI(ctx, messages, modelName, 400, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	aspects, err := c.extractAspectsFromResult(result, textJawaban, taxonomy, scheme)
+	if err != nil {
+		logger.LogError("Failed to extract aspects from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		aspects = []model.AspectSentiment{}
+	}
+
+	return &AspectResult{
+		Aspects: aspects,
+		Model:   modelName,
+		Usage:   usage,
+	}, nil
+}
+
+// extractAspectsFromResult parses aspects, dropping names outside the taxonomy
+// and evidence that does not appear in the answer. Sentiments are normalized
+// to the labels of scheme.
+func (c *LLMClient) extractAspectsFromResult(result interface{}, textJawaban string, taxonomy []string, scheme *labels.Scheme) ([]model.AspectSentiment, error) {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Aspects []model.AspectSentiment `json:"aspects"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: aspect response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+
+	allowed := make(map[string]string, len(taxonomy))
+	for _, aspect := range taxonomy {
+		allowed[strings.ToLower(aspect)] = aspect
+	}
+
+	answer := strings.ToLower(textJawaban)
+	aspects := make([]model.AspectSentiment, 0, len(payload.Aspects))
+	seen := make(map[string]bool)
+
+	for _, aspect := range payload.Aspects {
+		name, ok := allowed[strings.ToLower(strings.TrimSpace(aspect.Aspect))]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		evidence := strings.TrimSpace(aspect.Evidence)
+		if evidence != "" && !strings.Contains(answer, strings.ToLower(evidence)) {
+			evidence = ""
+		}
+
+		aspects = append(aspects, model.AspectSentiment{
+			Aspect:    name,
+			Sentiment: c.normalizeLabel(aspect.Sentiment, scheme),
+			Evidence:  evidence,
+		})
+	}
+
+	return aspects, nil
+}
+
+// rawJSON returns the JSON bytes of a CallTelkomAI result
+func rawJSON(result interface{}) ([]byte, error) {
+	if value, ok := result.(string); ok {
+		return []byte(value), nil
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return encoded, nil
+}
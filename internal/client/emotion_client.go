@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EmotionPromptVersion identifies the revision of the emotion prompt
+const EmotionPromptVersion = "emotion-v1"
+
+// EmotionLabels lists the emotions the classifier may return
+var EmotionLabels = []string{"marah", "kecewa", "senang", "takut", "sedih", "terkejut"}
+
+// EmotionResult represents the outcome of an emotion classification call
+type EmotionResult struct {
+	Emotions []model.EmotionScore
+	Model    string
+	Usage    *model.TokenUsage
+}
+
+// AnalyzeEmotions classifies the emotions expressed in the answer with an
+// intensity between 0 and 1 for each
+func (c *LLMClient) AnalyzeEmotions(ctx context.Context, textPertanyaan, textJawaban string) (*EmotionResult, error) {
+	systemPrompt := fmt.Sprintf(`Anda adalah sistem klasifikasi emosi yang sangat akurat untuk layanan pelanggan. Tugas Anda adalah mengidentifikasi emosi yang diungkapkan dalam jawaban.
+
+Emosi yang boleh digunakan: %s
+
+Petunjuk:
+- marah: kemarahan atau kejengkelan yang kuat
+- kecewa: harapan yang tidak terpenuhi
+- senang: kepuasan atau kegembiraan
+- takut: kekhawatiran atau kecemasan
+- sedih: kesedihan atau penyesalan
+- terkejut: keterkejutan, baik positif maupun negatif
+
+Untuk setiap emosi yang benar-benar diungkapkan, berikan intensity antara 0.0 dan 1.0. Jangan sertakan emosi yang tidak ada.
+Respons Anda harus dalam format JSON yang valid:
+{"emotions": [{"emotion": "kecewa", "intensity": 0.8}]}
+
+Jika tidak ada emosi yang diungkapkan, kembalikan {"emotions": []}.`, strings.Join(EmotionLabels, ", "))
+
+	userPrompt := fmt.Sprintf(`Pertanyaan: %s
+
+Jawaban: %s
+
+Identifikasi emosi dalam jawaban beserta intensitasnya.`, textPertanyaan, textJawaban)
+
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt,
+		},
+	}
+
+	modelName := DefaultModel
+	result, usage, err := c.CallTelkomAI(ctx, messages, modelName, 200, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	emotions, err := c.extractEmotionsFromResult(result)
+	if err != nil {
+		logger.LogError("Failed to extract emotions from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		emotions = []model.EmotionScore{}
+	}
+
+	return &EmotionResult{
+		Emotions: emotions,
+		Model:    modelName,
+		Usage:    usage,
+	}, nil
+}
+
+// extractEmotionsFromResult parses emotions, dropping unknown labels and
+// clamping intensities to [0, 1]
+func (c *LLMClient) extractEmotionsFromResult(result interface{}) ([]model.EmotionScore, error) {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Emotions []model.EmotionScore `json:"emotions"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: emotion response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+
+	emotions := make([]model.EmotionScore, 0, len(payload.Emotions))
+	seen := make(map[string]bool)
+
+	for _, emotion := range payload.Emotions {
+		label := normalizeEmotion(emotion.Emotion)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+
+		intensity := emotion.Intensity
+		if intensity < 0 {
+			intensity = 0
+		} else if intensity > 1 {
+			intensity = 1
+		}
+
+		emotions = append(emotions, model.EmotionScore{
+			Emotion:   label,
+			Intensity: intensity,
+		})
+	}
+
+	return emotions, nil
+}
+
+// emotionSynonyms maps common English labels to the Indonesian emotion set
+var emotionSynonyms = map[string]string{
+	"anger":          "marah",
+	"angry":          "marah",
+	"disappointed":   "kecewa",
+	"disappointment": "kecewa",
+	"happy":          "senang",
+	"joy":            "senang",
+	"fear":           "takut",
+	"sad":            "sedih",
+	"sadness":        "sedih",
+	"surprise":       "terkejut",
+	"surprised":      "terkejut",
+}
+
+// normalizeEmotion maps a model label to one of EmotionLabels, or "" if unknown
+func normalizeEmotion(emotion string) string {
+	emotion = strings.ToLower(strings.TrimSpace(emotion))
+	for _, label := range EmotionLabels {
+		if emotion == label {
+			return label
+		}
+	}
+	return emotionSynonyms[emotion]
+}
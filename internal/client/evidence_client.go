@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"sentiment-api/internal/evidence"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EvidencePromptVersion identifies the revision of the evidence prompt
+const EvidencePromptVersion = "evidence-v1"
+
+// EvidenceResult represents the outcome of an evidence extraction call
+type EvidenceResult struct {
+	Spans []model.EvidenceSpan
+	Model string
+	Usage *model.TokenUsage
+}
+
+// AnalyzeEvidence extracts the key phrases of the answer that drove the given
+// sentiment label, anchored to character offsets in the answer. Phrases the
+// model invented are dropped.
+func (c *LLMClient) AnalyzeEvidence(ctx context.Context, textPertanyaan, textJawaban, sentiment string) (*EvidenceResult, error) {
+	systemPrompt := `Anda adalah sistem analisis sentimen yang menjelaskan keputusannya dengan bukti. Tugas Anda adalah menemukan frasa kunci dalam jawaban yang paling memengaruhi sentimen.
+
+Untuk setiap frasa kunci, tentukan:
+- text: frasa yang disalin persis dari jawaban, tanpa mengubah ejaan
+- contribution: kontribusi polaritas antara -1.0 (sangat negatif) dan 1.0 (sangat positif)
+
+Pilih paling banyak 5 frasa yang pendek (1 sampai 5 kata). Respons Anda harus dalam format JSON yang valid:
+{"evidence": [{"text": "sangat memuaskan", "contribution": 0.9}]}
+
+Jika tidak ada frasa yang bermuatan sentimen, kembalikan {"evidence": []}.`
+
+	userPrompt := fmt.Sprintf(`Pertanyaan: %s
+
+Jawaban: %s
+
+Sentimen jawaban: %s
+
+Sebutkan frasa kunci dari jawaban yang mendukung sentimen tersebut.`, textPertanyaan, textJawaban, sentiment)
+
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt,
+		},
+	}
+
+	modelName := DefaultModel
+	result, usage, err := c.CallTelkomAI(ctx, messages, modelName, 250, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	spans, err := c.extractEvidenceFromResult(result, textJawaban)
+	if err != nil {
+		logger.LogError("Failed to extract evidence from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		spans = []model.EvidenceSpan{}
+	}
+
+	return &EvidenceResult{
+		Spans: spans,
+		Model: modelName,
+		Usage: usage,
+	}, nil
+}
+
+// extractEvidenceFromResult parses evidence phrases and anchors them in the answer
+func (c *LLMClient) extractEvidenceFromResult(result interface{}, textJawaban string) ([]model.EvidenceSpan, error) {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Evidence []model.EvidenceSpan `json:"evidence"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: evidence response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+
+	spans := evidence.Validate(textJawaban, payload.Evidence)
+	if dropped := len(payload.Evidence) - len(spans); dropped > 0 {
+		logger.LogWarn("Dropped evidence phrases not found in the answer", logrus.Fields{
+			"dropped": dropped,
+		})
+	}
+	return spans, nil
+}
@@ -1,13 +1,18 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"sentiment-api/internal/config"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/limiter"
 	"sentiment-api/internal/model"
+	"sentiment-api/internal/provider"
 	"sentiment-api/pkg/logger"
 
 	"github.com/go-resty/resty/v2"
@@ -16,25 +21,105 @@ import (
 
 // LLMClient handles communication with LLM API
 type LLMClient struct {
-	config *config.Config
-	client *resty.Client
+	config    *config.Config
+	client    *resty.Client
+	limiter   *limiter.Limiter
+	providers *provider.Chain
 }
 
-// NewLLMClient creates a new LLM client
-func NewLLMClient(cfg *config.Config) *LLMClient {
+// PromptVersion identifies the revision of the sentiment prompts below.
+// Bump it whenever a prompt changes so stored results stay comparable.
+const PromptVersion = "v1"
+
+// DefaultModel is the model sentiment calls use unless AnalysisOptions chooses another
+const DefaultModel = "telkom-ai-instruct"
+
+// SentimentResult represents the outcome of an LLM sentiment analysis call
+type SentimentResult struct {
+	Sentiment     string
+	Reasoning     *string
+	Model         string
+	PromptVersion string
+	Usage         *model.TokenUsage
+	Flags         *model.SentimentFlags
+	Confidence    *float64
+	// Provider names the provider of the failover chain that served the call
+	Provider string
+	// ParseFailed is set when the response was not the requested JSON and the
+	// label had to be recovered from free text or defaulted
+	ParseFailed bool
+	// Experiment and Variant name the A/B experiment arm that produced the result
+	Experiment string
+	Variant    string
+}
+
+// providerError reports a failed call to one provider. StatusCode is 0 when
+// no HTTP response was received.
+type providerError struct {
+	statusCode int
+	err        error
+}
+
+// Error implements the error interface
+func (e *providerError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *providerError) Unwrap() error {
+	return e.err
+}
+
+// unhealthy reports whether the failure points at the provider rather than
+// the request: transport errors, timeouts, rate limiting and server errors
+func (e *providerError) unhealthy() bool {
+	return e.statusCode == 0 || e.statusCode == http.StatusTooManyRequests || e.statusCode >= http.StatusInternalServerError
+}
+
+// Overload lets the concurrency limiter shrink only on unhealthy failures
+func (e *providerError) Overload() bool {
+	return e.unhealthy()
+}
+
+// NewLLMClient creates a new LLM client.
+// Calls are admitted through the given concurrency limiter when it is not nil
+// and go to the providers of the chain in order until one succeeds.
+func NewLLMClient(cfg *config.Config, concurrencyLimiter *limiter.Limiter, providers *provider.Chain) *LLMClient {
 	client := resty.New()
 	client.SetTimeout(60 * time.Second)
 	client.SetHeader("Content-Type", "application/json")
-	client.SetHeader("x-api-key", cfg.LLM.APIKey)
+	client.SetRetryCount(cfg.LLM.MaxRetries)
+	client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		return err != nil || resp.StatusCode() >= http.StatusInternalServerError
+	})
 
 	return &LLMClient{
-		config: cfg,
-		client: client,
+		config:    cfg,
+		client:    client,
+		limiter:   concurrencyLimiter,
+		providers: providers,
 	}
 }
 
+// Providers returns the client's provider chain
+func (c *LLMClient) Providers() *provider.Chain {
+	return c.providers
+}
+
 // CallTelkomAI makes a call to Telkom AI API
-func (c *LLMClient) CallTelkomAI(messages []model.LLMMessage, modelName string, maxTokens int, temperature float64) (interface{}, error) {
+// The parsed content is returned together with the token usage reported by the API.
+func (c *LLMClient) CallTelkomAI(ctx context.Context, messages []model.LLMMessage, modelName string, maxTokens int, temperature float64) (result interface{}, usage *model.TokenUsage, err error) {
+	result, usage, _, err = c.call(ctx, messages, modelName, AnalysisOptions{}, maxTokens, temperature)
+	return result, usage, err
+}
+
+// call makes an LLM call through the provider chain and also returns the
+// name of the provider that served it. Providers that fail after retries with
+// a transport error, timeout, 429 or 5xx are reported to the chain and the next
+// one is tried; any other failure is the request's fault and returned as is. A non-empty opts.Provider
+// pins the call to that provider without failover, and opts.Shadow keeps the
+// outcome out of the chain's health tracking.
+func (c *LLMClient) call(ctx context.Context, messages []model.LLMMessage, modelName string, opts AnalysisOptions, maxTokens int, temperature float64) (result interface{}, usage *model.TokenUsage, served string, err error) {
 	logger.LogDebug("Making API call to LLM", logrus.Fields{
 		"model":       modelName,
 		"messages":    len(messages),
@@ -42,8 +127,66 @@ func (c *LLMClient) CallTelkomAI(messages []model.LLMMessage, modelName string,
 		"temperature": temperature,
 	})
 
+	if c.limiter != nil {
+		release, acquireErr := c.limiter.Acquire(ctx, limiter.PriorityFromContext(ctx))
+		if acquireErr != nil {
+			logger.LogWarn("LLM call not admitted by concurrency limiter", logrus.Fields{
+				"error": acquireErr.Error(),
+			})
+			return nil, nil, "", acquireErr
+		}
+		defer func() { release(err) }()
+	}
+
+	candidates := c.providers.LLMProviders()
+	if opts.Provider != "" {
+		pinned, ok := c.providers.Get(opts.Provider)
+		if !ok || pinned.Type != provider.TypeLLM {
+			return nil, nil, "", fmt.Errorf("unknown LLM provider %q", opts.Provider)
+		}
+		candidates = []provider.Provider{pinned}
+	}
+
+	var lastErr error
+	for _, p := range candidates {
+		start := time.Now()
+		result, usage, err = c.callProvider(ctx, p, messages, modelName, maxTokens, temperature)
+		if err == nil {
+			if !opts.Shadow {
+				c.providers.ReportSuccess(p.Name, time.Since(start))
+			}
+			return result, usage, p.Name, nil
+		}
+
+		// A canceled request says nothing about the provider's health
+		if ctx.Err() != nil {
+			return nil, nil, "", err
+		}
+
+		// A rejected request would be rejected by the next provider too
+		var callErr *providerError
+		if errors.As(err, &callErr) && !callErr.unhealthy() {
+			return nil, nil, "", err
+		}
+
+		if !opts.Shadow {
+			c.providers.ReportFailure(p.Name, err)
+		}
+		logger.LogWarn("LLM provider failed, trying next provider", logrus.Fields{
+			"provider": p.Name,
+			"error":    err.Error(),
+		})
+		lastErr = err
+	}
+
+	return nil, nil, "", fmt.Errorf("%w: %w", provider.ErrUnavailable, lastErr)
+}
+
+// callProvider makes one call to a single provider. Failures are returned
+// as *providerError.
+func (c *LLMClient) callProvider(ctx context.Context, p provider.Provider, messages []model.LLMMessage, modelName string, maxTokens int, temperature float64) (interface{}, *model.TokenUsage, error) {
 	request := model.LLMRequest{
-		Model:       modelName,
+		Model:       p.ModelFor(modelName),
 		Messages:    messages,
 		Stream:      false,
 		MaxTokens:   maxTokens,
@@ -52,33 +195,37 @@ func (c *LLMClient) CallTelkomAI(messages []model.LLMMessage, modelName string,
 
 	var response model.LLMResponse
 	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("x-api-key", p.APIKey).
 		SetBody(request).
 		SetResult(&response).
-		Post(c.config.LLM.URL)
+		Post(p.URL)
 
 	if err != nil {
 		logger.LogErrorWithContext(err, "HTTP request error in LLM call")
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, nil, &providerError{err: fmt.Errorf("request failed: %w", err)}
 	}
 
 	if resp.StatusCode() != 200 {
 		errorMsg := fmt.Sprintf("response error %d: %s", resp.StatusCode(), resp.String())
 		logger.LogError("HTTP status error", logrus.Fields{
+			"provider":    p.Name,
 			"status_code": resp.StatusCode(),
 			"response":    resp.String(),
 		})
-		return nil, errors.New(errorMsg)
+		return nil, nil, &providerError{statusCode: resp.StatusCode(), err: errors.New(errorMsg)}
 	}
 
 	if len(response.Choices) == 0 {
 		logger.LogError("No choices in LLM response", nil)
-		return nil, errors.New("no choices in response")
+		return nil, response.Usage, &providerError{statusCode: resp.StatusCode(), err: errors.New("no choices in response")}
 	}
 
 	content := response.Choices[0].Message.Content
 
 	logger.LogDebug("LLM API call successful", logrus.Fields{
 		"content_length": len(content),
+		"usage":          response.Usage,
 	})
 
 	// Try to parse JSON response
@@ -88,32 +235,18 @@ func (c *LLMClient) CallTelkomAI(messages []model.LLMMessage, modelName string,
 			"error":   err.Error(),
 			"content": content,
 		})
-		return content, nil
+		return content, response.Usage, nil
 	}
 
 	logger.LogDebug("JSON parsing successful", nil)
-	return parsedContent, nil
+	return parsedContent, response.Usage, nil
 }
 
-// AnalyzeSentiment performs sentiment analysis using LLM
-func (c *LLMClient) AnalyzeSentiment(textPertanyaan, textJawaban string) (string, error) {
-	systemPrompt := `Anda adalah sistem analisis sentimen yang sangat akurat. Tugas Anda adalah menganalisis sentimen dari jawaban terhadap pertanyaan yang diberikan.
-
-Berdasarkan konteks pertanyaan dan jawaban, tentukan sentimen jawaban tersebut:
-- Positif: Jawaban menunjukkan emosi atau pandangan yang baik, puas, senang, atau mendukung
-- Negatif: Jawaban menunjukkan emosi atau pandangan yang buruk, tidak puas, kecewa, atau menolak  
-- Netral: Jawaban objektif, tidak menunjukkan emosi khusus, atau seimbang
-
-Respons Anda harus dalam format JSON yang valid:
-{"sentiment": "Positif"} atau {"sentiment": "Negatif"} atau {"sentiment": "Netral"}
-
-Hanya gunakan kata: Positif, Negatif, atau Netral.`
-
-	userPrompt := fmt.Sprintf(`Pertanyaan: %s
-
-Jawaban: %s
-
-Analisis sentimen jawaban tersebut berdasarkan konteks pertanyaan.`, textPertanyaan, textJawaban)
+// AnalyzeSentiment performs sentiment analysis using LLM.
+// The prompt language and the accepted output labels come from opts.
+func (c *LLMClient) AnalyzeSentiment(ctx context.Context, textPertanyaan, textJawaban string, opts AnalysisOptions) (*SentimentResult, error) {
+	systemPrompt := renderSystemPrompt(opts, false)
+	userPrompt := renderUserPrompt(opts, textPertanyaan, textJawaban, false)
 
 	messages := []model.LLMMessage{
 		{
@@ -126,47 +259,39 @@ Analisis sentimen jawaban tersebut berdasarkan konteks pertanyaan.`, textPertany
 		},
 	}
 
-	result, err := c.CallTelkomAI(messages, "telkom-ai-instruct", 100, 0.0)
+	modelName := opts.modelName()
+	result, usage, served, err := c.call(ctx, messages, modelName, opts, 100, 0.0)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	analysis := &SentimentResult{
+		Model:         modelName,
+		PromptVersion: promptVersion(opts),
+		Usage:         usage,
+		Provider:      served,
+		ParseFailed:   !hasSentimentField(result),
 	}
 
 	// Parse the result to extract sentiment
-	sentiment, err := c.extractSentimentFromResult(result)
+	sentiment, err := c.extractSentimentFromResult(result, opts.Scheme)
 	if err != nil {
 		logger.LogError("Failed to extract sentiment from LLM response", logrus.Fields{
 			"result": result,
 			"error":  err.Error(),
 		})
-		return "Netral", nil // Default to Netral if parsing fails
+		analysis.Sentiment = opts.Scheme.Fallback // Default to the scheme fallback if parsing fails
+		return analysis, nil
 	}
 
-	return sentiment, nil
+	analysis.Sentiment = sentiment
+	return analysis, nil
 }
 
 // AnalyzeSentimentWithReasoning performs sentiment analysis with reasoning explanation using LLM
-func (c *LLMClient) AnalyzeSentimentWithReasoning(textPertanyaan, textJawaban string) (string, *string, error) {
-	systemPrompt := `Anda adalah sistem analisis sentimen yang sangat akurat dan dapat memberikan penjelasan. Tugas Anda adalah menganalisis sentimen dari jawaban terhadap pertanyaan yang diberikan, beserta alasan analisis tersebut.
-
-Berdasarkan konteks pertanyaan dan jawaban, tentukan sentimen jawaban tersebut:
-- Positif: Jawaban menunjukkan emosi atau pandangan yang baik, puas, senang, atau mendukung
-- Negatif: Jawaban menunjukkan emosi atau pandangan yang buruk, tidak puas, kecewa, atau menolak  
-- Netral: Jawaban objektif, tidak menunjukkan emosi khusus, atau seimbang
-
-Respons Anda harus dalam format JSON yang valid dengan penjelasan:
-{
-  "sentiment": "Positif",
-  "reasoning": "Penjelasan mengapa sentimen ini dipilih, kata-kata kunci yang mendukung, dan konteks yang relevan"
-}
-
-Hanya gunakan kata: Positif, Negatif, atau Netral untuk sentiment.
-Berikan penjelasan yang jelas dan informatif dalam bahasa Indonesia untuk reasoning.`
-
-	userPrompt := fmt.Sprintf(`Pertanyaan: %s
-
-Jawaban: %s
-
-Analisis sentimen jawaban tersebut berdasarkan konteks pertanyaan dan berikan penjelasan lengkap.`, textPertanyaan, textJawaban)
+func (c *LLMClient) AnalyzeSentimentWithReasoning(ctx context.Context, textPertanyaan, textJawaban string, opts AnalysisOptions) (*SentimentResult, error) {
+	systemPrompt := renderSystemPrompt(opts, true)
+	userPrompt := renderUserPrompt(opts, textPertanyaan, textJawaban, true)
 
 	messages := []model.LLMMessage{
 		{
@@ -179,31 +304,57 @@ Analisis sentimen jawaban tersebut berdasarkan konteks pertanyaan dan berikan pe
 		},
 	}
 
-	result, err := c.CallTelkomAI(messages, "telkom-ai-instruct", 300, 0.1)
+	modelName := opts.modelName()
+	result, usage, served, err := c.call(ctx, messages, modelName, opts, 300, 0.1)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+
+	analysis := &SentimentResult{
+		Model:         modelName,
+		PromptVersion: promptVersion(opts),
+		Usage:         usage,
+		Provider:      served,
+		ParseFailed:   !hasSentimentField(result),
 	}
 
 	// Parse the result to extract sentiment and reasoning
-	sentiment, reasoning, err := c.extractSentimentAndReasoningFromResult(result)
+	sentiment, reasoning, err := c.extractSentimentAndReasoningFromResult(result, opts.Scheme)
 	if err != nil {
 		logger.LogError("Failed to extract sentiment and reasoning from LLM response", logrus.Fields{
 			"result": result,
 			"error":  err.Error(),
 		})
 		// Return basic sentiment without reasoning on parsing failure
-		basicSentiment, basicErr := c.extractSentimentFromResult(result)
+		basicSentiment, basicErr := c.extractSentimentFromResult(result, opts.Scheme)
 		if basicErr != nil {
-			return "Netral", nil, nil // Default to Netral if everything fails
+			analysis.Sentiment = opts.Scheme.Fallback // Default to the scheme fallback if everything fails
+			return analysis, nil
 		}
-		return basicSentiment, nil, nil
+		analysis.Sentiment = basicSentiment
+		return analysis, nil
+	}
+
+	analysis.Sentiment = sentiment
+	analysis.Reasoning = reasoning
+	return analysis, nil
+}
+
+// hasSentimentField reports whether the LLM returned a JSON object with a sentiment string
+func hasSentimentField(result interface{}) bool {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return false
 	}
 
-	return sentiment, reasoning, nil
+	var payload struct {
+		Sentiment *string `json:"sentiment"`
+	}
+	return json.Unmarshal(raw, &payload) == nil && payload.Sentiment != nil
 }
 
 // extractSentimentAndReasoningFromResult extracts both sentiment and reasoning from LLM result
-func (c *LLMClient) extractSentimentAndReasoningFromResult(result interface{}) (string, *string, error) {
+func (c *LLMClient) extractSentimentAndReasoningFromResult(result interface{}, scheme *labels.Scheme) (string, *string, error) {
 	// If result is a map (parsed JSON)
 	if resultMap, ok := result.(map[string]interface{}); ok {
 		sentiment, sentimentExists := resultMap["sentiment"]
@@ -212,7 +363,7 @@ func (c *LLMClient) extractSentimentAndReasoningFromResult(result interface{}) (
 		if sentimentExists {
 			sentimentStr, sentimentOk := sentiment.(string)
 			if sentimentOk {
-				normalizedSentiment := c.normalizeSentiment(sentimentStr)
+				normalizedSentiment := c.normalizeLabel(sentimentStr, scheme)
 
 				if reasoningExists {
 					if reasoningStr, reasoningOk := reasoning.(string); reasoningOk && reasoningStr != "" {
@@ -233,7 +384,7 @@ func (c *LLMClient) extractSentimentAndReasoningFromResult(result interface{}) (
 
 			if sentimentExists {
 				if sentimentStr, ok := sentiment.(string); ok {
-					normalizedSentiment := c.normalizeSentiment(sentimentStr)
+					normalizedSentiment := c.normalizeLabel(sentimentStr, scheme)
 
 					if reasoningExists {
 						if reasoningStr, ok := reasoning.(string); ok && reasoningStr != "" {
@@ -246,7 +397,7 @@ func (c *LLMClient) extractSentimentAndReasoningFromResult(result interface{}) (
 		}
 
 		// If not proper JSON, try to extract sentiment only
-		extractedSentiment := c.extractSentimentFromString(resultStr)
+		extractedSentiment, _ := scheme.Find(resultStr)
 		return extractedSentiment, nil, nil
 	}
 
@@ -254,12 +405,12 @@ func (c *LLMClient) extractSentimentAndReasoningFromResult(result interface{}) (
 }
 
 // extractSentimentFromResult extracts sentiment from LLM result
-func (c *LLMClient) extractSentimentFromResult(result interface{}) (string, error) {
+func (c *LLMClient) extractSentimentFromResult(result interface{}, scheme *labels.Scheme) (string, error) {
 	// If result is a map (parsed JSON)
 	if resultMap, ok := result.(map[string]interface{}); ok {
 		if sentiment, exists := resultMap["sentiment"]; exists {
 			if sentimentStr, ok := sentiment.(string); ok {
-				return c.normalizeSentiment(sentimentStr), nil
+				return c.normalizeLabel(sentimentStr, scheme), nil
 			}
 		}
 	}
@@ -270,64 +421,29 @@ func (c *LLMClient) extractSentimentFromResult(result interface{}) (string, erro
 		if err := json.Unmarshal([]byte(resultStr), &sentimentResult); err == nil {
 			if sentiment, exists := sentimentResult["sentiment"]; exists {
 				if sentimentStr, ok := sentiment.(string); ok {
-					return c.normalizeSentiment(sentimentStr), nil
+					return c.normalizeLabel(sentimentStr, scheme), nil
 				}
 			}
 		}
 
 		// If not JSON, try to extract sentiment directly from string
-		return c.extractSentimentFromString(resultStr), nil
+		sentiment, _ := scheme.Find(resultStr)
+		return sentiment, nil
 	}
 
 	return "", errors.New("unable to extract sentiment from result")
 }
 
-// extractSentimentFromString extracts sentiment from string response
-func (c *LLMClient) extractSentimentFromString(text string) string {
-	text = fmt.Sprintf("%s", text) // Convert to lowercase for comparison
-
-	if contains(text, "positif") {
-		return "Positif"
-	} else if contains(text, "negatif") {
-		return "Negatif"
-	} else if contains(text, "netral") {
-		return "Netral"
-	}
-
-	// Default to Netral if no clear sentiment found
-	return "Netral"
-}
-
-// normalizeSentiment normalizes sentiment values
-func (c *LLMClient) normalizeSentiment(sentiment string) string {
-	switch sentiment {
-	case "Positif", "positif", "POSITIF", "Positive", "positive", "POSITIVE":
-		return "Positif"
-	case "Negatif", "negatif", "NEGATIF", "Negative", "negative", "NEGATIVE":
-		return "Negatif"
-	case "Netral", "netral", "NETRAL", "Neutral", "neutral", "NEUTRAL":
-		return "Netral"
-	default:
-		return "Netral"
-	}
-}
-
-// contains checks if a string contains a substring (case insensitive)
-func contains(text, substr string) bool {
-	return len(text) >= len(substr) &&
-		(text == substr ||
-			len(text) > len(substr) &&
-				(text[0:len(substr)] == substr ||
-					text[len(text)-len(substr):] == substr ||
-					findSubstring(text, substr)))
-}
-
-// findSubstring finds substring in text
-func findSubstring(text, substr string) bool {
-	for i := 0; i <= len(text)-len(substr); i++ {
-		if text[i:i+len(substr)] == substr {
-			return true
-		}
+// normalizeLabel validates a model label against the scheme, falling back
+// to the scheme's fallback label when the model returned something else
+func (c *LLMClient) normalizeLabel(sentiment string, scheme *labels.Scheme) string {
+	label, ok := scheme.Normalize(sentiment)
+	if !ok {
+		logger.LogWarn("LLM returned a label outside the label scheme", logrus.Fields{
+			"label":    sentiment,
+			"scheme":   scheme.ID,
+			"fallback": label,
+		})
 	}
-	return false
+	return label
 }
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NuancePromptVersion identifies the revision of the sarcasm and mixed-sentiment prompt
+const NuancePromptVersion = "nuance-v1"
+
+// AnalyzeSentimentNuanced performs sentiment analysis with a prompt that also
+// looks for sarcasm or irony and for answers mixing positive and negative views.
+// The label accounts for sarcasm, so ironic praise is labeled by its real meaning.
+func (c *LLMClient) AnalyzeSentimentNuanced(ctx context.Context, textPertanyaan, textJawaban string, opts AnalysisOptions, withReasoning bool) (*SentimentResult, error) {
+	reasoningField := ""
+	reasoningInstruction := ""
+	if withReasoning {
+		reasoningField = `,
+  "reasoning": "Penjelasan singkat mengapa sentimen dan penanda ini dipilih"`
+		reasoningInstruction = "\nBerikan penjelasan yang jelas dalam bahasa Indonesia untuk reasoning."
+	}
+
+	systemPrompt := fmt.Sprintf(`Anda adalah sistem analisis sentimen yang sangat akurat dan peka terhadap sarkasme, ironi, dan sentimen campuran. Tugas Anda adalah menganalisis sentimen dari jawaban terhadap pertanyaan yang diberikan.
+
+Berdasarkan konteks pertanyaan dan jawaban, tentukan sentimen jawaban tersebut:
+%s
+
+Perhatikan hal berikut:
+- Sarkasme atau ironi: pujian yang sebenarnya bermaksud mengeluh, misalnya "Wah hebat sekali, sudah seminggu belum sampai". Tentukan sentimen berdasarkan maksud sebenarnya, bukan kata-katanya.
+- Sentimen campuran: jawaban yang sungguh-sungguh memuat pandangan positif dan negatif sekaligus, misalnya "Produknya bagus tapi pengirimannya lambat". Pilih sentimen yang paling dominan.
+
+Respons Anda harus dalam format JSON yang valid:
+{
+  "sentiment": "%s",
+  "sarcasm": false,
+  "mixed": false%s
+}
+
+Hanya gunakan kata: %s untuk sentiment.%s`, labelDescriptions(opts.Scheme), opts.Scheme.Labels[0].Name, reasoningField, labelList(opts.Scheme, "atau"), reasoningInstruction)
+
+	if note := languageNotes[opts.Language]; note != "" {
+		systemPrompt += "\n\n" + note
+	}
+
+	userPrompt := fmt.Sprintf(`Pertanyaan: %s
+
+Jawaban: %s
+
+Analisis sentimen jawaban tersebut, termasuk apakah ada sarkasme atau sentimen campuran.`, textPertanyaan, textJawaban)
+
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt,
+		},
+	}
+
+	maxTokens := 100
+	if withReasoning {
+		maxTokens = 300
+	}
+
+	modelName := opts.modelName()
+	result, usage, served, err := c.call(ctx, messages, modelName, opts, maxTokens, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &SentimentResult{
+		Model:         modelName,
+		PromptVersion: nuancePromptVersion(opts),
+		Usage:         usage,
+		Provider:      served,
+	}
+
+	if err := c.extractNuanceFromResult(result, opts.Scheme, analysis); err != nil {
+		logger.LogError("Failed to extract nuanced sentiment from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		// Fall back to whatever label can be recovered, without flags
+		analysis.ParseFailed = true
+		sentiment, basicErr := c.extractSentimentFromResult(result, opts.Scheme)
+		if basicErr != nil {
+			sentiment = opts.Scheme.Fallback
+		}
+		analysis.Sentiment = sentiment
+	}
+
+	return analysis, nil
+}
+
+// extractNuanceFromResult fills the label, flags and reasoning of analysis
+func (c *LLMClient) extractNuanceFromResult(result interface{}, scheme *labels.Scheme, analysis *SentimentResult) error {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return err
+	}
+
+	var payload struct {
+		Sentiment string `json:"sentiment"`
+		Sarcasm   bool   `json:"sarcasm"`
+		Mixed     bool   `json:"mixed"`
+		Reasoning string `json:"reasoning"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return fmt.Errorf("%w: nuance response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+	if payload.Sentiment == "" {
+		return fmt.Errorf("%w: nuance response has no sentiment", ErrInvalidResponse)
+	}
+
+	analysis.Sentiment = c.normalizeLabel(payload.Sentiment, scheme)
+	analysis.Flags = &model.SentimentFlags{
+		Sarcasm: payload.Sarcasm,
+		Mixed:   payload.Mixed,
+	}
+	if payload.Reasoning != "" {
+		reasoning := payload.Reasoning
+		analysis.Reasoning = &reasoning
+	}
+	return nil
+}
+
+// nuancePromptVersion tags NuancePromptVersion like promptVersion does
+func nuancePromptVersion(opts AnalysisOptions) string {
+	version := NuancePromptVersion
+	if opts.Scheme.ID != labels.DefaultSchemeID {
+		version += "+" + opts.Scheme.ID
+	}
+	return version
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/language"
+)
+
+// AnalysisOptions selects the label scheme, prompt language, model and provider of a sentiment call
+type AnalysisOptions struct {
+	Scheme   *labels.Scheme
+	Language string
+	// Model overrides DefaultModel when not empty
+	Model string
+	// Provider pins the call to one provider of the chain when not empty
+	Provider string
+	// Shadow marks background comparison calls, whose outcome is not reported
+	// to the provider chain so they cannot change production routing
+	Shadow bool
+}
+
+// modelName returns the model the call should use
+func (o AnalysisOptions) modelName() string {
+	if o.Model == "" {
+		return DefaultModel
+	}
+	return o.Model
+}
+
+// promptTemplate holds the language-specific text of the sentiment prompts.
+// System prompts take the label descriptions, the response example and the label list.
+type promptTemplate struct {
+	system          string
+	systemReasoning string
+	user            string
+	userReasoning   string
+	or              string
+}
+
+// promptTemplates lists the sentiment prompts per prompt language
+var promptTemplates = map[string]promptTemplate{
+	language.Indonesian: {
+		system: `Anda adalah sistem analisis sentimen yang sangat akurat. Tugas Anda adalah menganalisis sentimen dari jawaban terhadap pertanyaan yang diberikan.
+
+Berdasarkan konteks pertanyaan dan jawaban, tentukan sentimen jawaban tersebut:
+%s
+
+Respons Anda harus dalam format JSON yang valid:
+%s
+
+Hanya gunakan kata: %s.`,
+		systemReasoning: `Anda adalah sistem analisis sentimen yang sangat akurat dan dapat memberikan penjelasan. Tugas Anda adalah menganalisis sentimen dari jawaban terhadap pertanyaan yang diberikan, beserta alasan analisis tersebut.
+
+Berdasarkan konteks pertanyaan dan jawaban, tentukan sentimen jawaban tersebut:
+%s
+
+Respons Anda harus dalam format JSON yang valid dengan penjelasan:
+{
+  "sentiment": "%s",
+  "reasoning": "Penjelasan mengapa sentimen ini dipilih, kata-kata kunci yang mendukung, dan konteks yang relevan"
+}
+
+Hanya gunakan kata: %s untuk sentiment.
+Berikan penjelasan yang jelas dan informatif dalam bahasa Indonesia untuk reasoning.`,
+		user: `Pertanyaan: %s
+
+Jawaban: %s
+
+Analisis sentimen jawaban tersebut berdasarkan konteks pertanyaan.`,
+		userReasoning: `Pertanyaan: %s
+
+Jawaban: %s
+
+Analisis sentimen jawaban tersebut berdasarkan konteks pertanyaan dan berikan penjelasan lengkap.`,
+		or: "atau",
+	},
+	language.English: {
+		system: `You are a highly accurate sentiment analysis system. Your task is to analyze the sentiment of an answer to the given question.
+
+Based on the question and the answer, determine the sentiment of the answer:
+%s
+
+Your response must be valid JSON:
+%s
+
+Only use the words: %s.`,
+		systemReasoning: `You are a highly accurate sentiment analysis system that explains its decisions. Your task is to analyze the sentiment of an answer to the given question, together with the reasons for your analysis.
+
+Based on the question and the answer, determine the sentiment of the answer:
+%s
+
+Your response must be valid JSON with an explanation:
+{
+  "sentiment": "%s",
+  "reasoning": "Why this sentiment was chosen, the supporting key words and the relevant context"
+}
+
+Only use the words: %s for sentiment.
+Give a clear and informative explanation in English for reasoning.`,
+		user: `Question: %s
+
+Answer: %s
+
+Analyze the sentiment of the answer in the context of the question.`,
+		userReasoning: `Question: %s
+
+Answer: %s
+
+Analyze the sentiment of the answer in the context of the question and give a complete explanation.`,
+		or: "or",
+	},
+}
+
+// languageNotes are appended to the Indonesian prompt for answers in
+// regional languages or code-switched text
+var languageNotes = map[string]string{
+	language.Javanese:  "Jawaban ditulis dalam bahasa Jawa. Pahami ungkapan dan tingkat tutur bahasa Jawa sebelum menentukan sentimen.",
+	language.Sundanese: "Jawaban ditulis dalam bahasa Sunda. Pahami ungkapan dan undak usuk basa Sunda sebelum menentukan sentimen.",
+	language.Mixed:     "Jawaban mencampur beberapa bahasa (misalnya bahasa Indonesia, Inggris, atau bahasa daerah). Pertimbangkan seluruh bagian jawaban sebelum menentukan sentimen.",
+}
+
+// templateFor returns the prompt template and optional language note for a prompt language
+func templateFor(lang string) (promptTemplate, string) {
+	if template, ok := promptTemplates[lang]; ok {
+		return template, ""
+	}
+	return promptTemplates[language.Indonesian], languageNotes[lang]
+}
+
+// renderSystemPrompt fills a system prompt with the labels of the scheme
+func renderSystemPrompt(opts AnalysisOptions, withReasoning bool) string {
+	template, note := templateFor(opts.Language)
+
+	var prompt string
+	if withReasoning {
+		prompt = fmt.Sprintf(template.systemReasoning, labelDescriptions(opts.Scheme), opts.Scheme.Labels[0].Name, labelList(opts.Scheme, template.or))
+	} else {
+		prompt = fmt.Sprintf(template.system, labelDescriptions(opts.Scheme), labelExamples(opts.Scheme, template.or), labelList(opts.Scheme, template.or))
+	}
+
+	if note != "" {
+		prompt += "\n\n" + note
+	}
+	return prompt
+}
+
+// renderUserPrompt fills the user prompt with the question and answer
+func renderUserPrompt(opts AnalysisOptions, textPertanyaan, textJawaban string, withReasoning bool) string {
+	template, _ := templateFor(opts.Language)
+	if withReasoning {
+		return fmt.Sprintf(template.userReasoning, textPertanyaan, textJawaban)
+	}
+	return fmt.Sprintf(template.user, textPertanyaan, textJawaban)
+}
+
+// labelDescriptions renders the scheme's labels as a prompt bullet list
+func labelDescriptions(scheme *labels.Scheme) string {
+	lines := make([]string, len(scheme.Labels))
+	for i, label := range scheme.Labels {
+		lines[i] = fmt.Sprintf("- %s: %s", label.Name, label.Description)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// labelExamples renders one JSON example per label of the scheme
+func labelExamples(scheme *labels.Scheme, or string) string {
+	examples := make([]string, len(scheme.Labels))
+	for i, label := range scheme.Labels {
+		examples[i] = fmt.Sprintf(`{"sentiment": "%s"}`, label.Name)
+	}
+	return strings.Join(examples, " "+or+" ")
+}
+
+// labelList renders the scheme's label names as "A, B, or C"
+func labelList(scheme *labels.Scheme, or string) string {
+	names := scheme.Names()
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", " + or + " " + names[len(names)-1]
+}
+
+// promptVersion tags PromptVersion with the prompt language and label scheme
+// it was rendered for, so stored results stay comparable
+func promptVersion(opts AnalysisOptions) string {
+	version := PromptVersion
+	if opts.Language != "" && opts.Language != language.Indonesian {
+		version += "-" + opts.Language
+	}
+	if opts.Scheme.ID != labels.DefaultSchemeID {
+		version += "+" + opts.Scheme.ID
+	}
+	return version
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SummaryPromptVersion identifies the revision of the summary prompts
+const SummaryPromptVersion = "summary-v1"
+
+const (
+	// summaryMapMaxTokens bounds the partial summary of one chunk of answers
+	summaryMapMaxTokens = 500
+	// summaryReduceMaxTokens bounds the merged executive summary
+	summaryReduceMaxTokens = 800
+)
+
+// summaryFormat is the JSON object both summary prompts ask for
+const summaryFormat = `{
+  "summary": "Ringkasan eksekutif dalam satu paragraf",
+  "positive_points": ["Poin positif utama"],
+  "negative_points": ["Poin negatif utama"]
+}`
+
+// AnswerSummary represents a summary of a set of answers
+type AnswerSummary struct {
+	Summary        string   `json:"summary"`
+	PositivePoints []string `json:"positive_points"`
+	NegativePoints []string `json:"negative_points"`
+}
+
+// SummaryResult represents the outcome of one summary call
+type SummaryResult struct {
+	AnswerSummary
+	Model string
+	Usage *model.TokenUsage
+}
+
+// SummarizeAnswers summarizes one chunk of answers to a question (the map step)
+func (c *LLMClient) SummarizeAnswers(ctx context.Context, textPertanyaan string, answers []string) (*SummaryResult, error) {
+	systemPrompt := fmt.Sprintf(`Anda adalah analis survei yang menulis untuk manajemen. Tugas Anda adalah merangkum jawaban responden terhadap sebuah pertanyaan dalam bahasa Indonesia.
+
+Tulis ringkasan yang padat dan faktual, sebutkan poin positif dan negatif utama beserta seberapa sering poin tersebut muncul (misalnya "banyak responden", "beberapa responden"). Jangan mengarang hal yang tidak ada dalam jawaban.
+
+Respons Anda harus dalam format JSON yang valid:
+%s`, summaryFormat)
+
+	var userPrompt strings.Builder
+	if textPertanyaan != "" {
+		fmt.Fprintf(&userPrompt, "Pertanyaan: %s\n\n", textPertanyaan)
+	}
+	userPrompt.WriteString("Jawaban responden:\n")
+	for _, answer := range answers {
+		fmt.Fprintf(&userPrompt, "- %s\n", answer)
+	}
+	userPrompt.WriteString("\nRangkum jawaban-jawaban tersebut.")
+
+	return c.callSummary(ctx, systemPrompt, userPrompt.String(), summaryMapMaxTokens)
+}
+
+// MergeSummaries combines partial summaries of the same question into one
+// executive summary (the reduce step)
+func (c *LLMClient) MergeSummaries(ctx context.Context, textPertanyaan string, partials []AnswerSummary) (*SummaryResult, error) {
+	systemPrompt := fmt.Sprintf(`Anda adalah analis survei yang menulis untuk manajemen. Anda menerima beberapa ringkasan parsial dari jawaban responden terhadap pertanyaan yang sama. Setiap ringkasan parsial mewakili kelompok responden yang berbeda.
+
+Gabungkan ringkasan-ringkasan tersebut menjadi satu ringkasan eksekutif dalam bahasa Indonesia. Satukan poin yang sama, urutkan poin dari yang paling sering muncul, dan pertahankan paling banyak 5 poin positif dan 5 poin negatif. Jangan mengarang hal yang tidak ada dalam ringkasan parsial.
+
+Respons Anda harus dalam format JSON yang valid:
+%s`, summaryFormat)
+
+	var userPrompt strings.Builder
+	if textPertanyaan != "" {
+		fmt.Fprintf(&userPrompt, "Pertanyaan: %s\n\n", textPertanyaan)
+	}
+	for i, partial := range partials {
+		fmt.Fprintf(&userPrompt, "Ringkasan parsial %d:\n%s\n", i+1, partial.Summary)
+		for _, point := range partial.PositivePoints {
+			fmt.Fprintf(&userPrompt, "+ %s\n", point)
+		}
+		for _, point := range partial.NegativePoints {
+			fmt.Fprintf(&userPrompt, "- %s\n", point)
+		}
+		userPrompt.WriteString("\n")
+	}
+	userPrompt.WriteString("Gabungkan ringkasan-ringkasan parsial tersebut.")
+
+	return c.callSummary(ctx, systemPrompt, userPrompt.String(), summaryReduceMaxTokens)
+}
+
+// callSummary sends a summary prompt and parses the summary object. When the
+// response cannot be parsed the error comes with a result carrying the usage.
+func (c *LLMClient) callSummary(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (*SummaryResult, error) {
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt,
+		},
+	}
+
+	modelName := DefaultModel
+	result, usage, err := c.CallTelkomAI(ctx, messages, modelName, maxTokens, 0.2)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := extractSummaryFromResult(result)
+	if err != nil {
+		logger.LogError("Failed to extract summary from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		// The tokens were spent even though the summary is unusable
+		return &SummaryResult{Model: modelName, Usage: usage}, err
+	}
+
+	return &SummaryResult{
+		AnswerSummary: *summary,
+		Model:         modelName,
+		Usage:         usage,
+	}, nil
+}
+
+// extractSummaryFromResult parses a summary object. Plain prose is accepted
+// as the summary text so a model that ignores the format still yields a result.
+func extractSummaryFromResult(result interface{}) (*AnswerSummary, error) {
+	if text, ok := result.(string); ok && !strings.HasPrefix(strings.TrimSpace(text), "{") {
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("%w: empty summary", ErrInvalidResponse)
+		}
+		return &AnswerSummary{Summary: strings.TrimSpace(text)}, nil
+	}
+
+	raw, err := rawJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary AnswerSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("%w: summary response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+	if strings.TrimSpace(summary.Summary) == "" {
+		return nil, fmt.Errorf("%w: summary response has no summary", ErrInvalidResponse)
+	}
+	return &summary, nil
+}
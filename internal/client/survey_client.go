@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SurveyPromptVersion identifies the revision of the survey prompt
+const SurveyPromptVersion = "survey-v1"
+
+// ErrInvalidResponse is returned when the LLM response does not match the requested format
+var ErrInvalidResponse = errors.New("invalid LLM response")
+
+// SurveyResult represents the outcome of a cross-question survey analysis
+type SurveyResult struct {
+	Questions        map[string]SentimentResult
+	OverallSentiment string
+	OverallReasoning *string
+	Model            string
+	PromptVersion    string
+	Usage            *model.TokenUsage
+}
+
+// AnalyzeSurvey analyzes every answer of a submission in one LLM call so each
+// answer is interpreted in the context of the respondent's other answers.
+// Answers are labelled with the labels of scheme.
+func (c *LLMClient) AnalyzeSurvey(ctx context.Context, answers []model.SurveyAnswer, withReasoning bool, scheme *labels.Scheme) (*SurveyResult, error) {
+	example := scheme.Labels[0].Name
+	responseFormat := fmt.Sprintf(`{
+  "questions": [{"question_id": "Q1", "sentiment": "%[1]s"}],
+  "overall_sentiment": "%[1]s"
+}`, example)
+	reasoningInstruction := ""
+	if withReasoning {
+		responseFormat = fmt.Sprintf(`{
+  "questions": [{"question_id": "Q1", "sentiment": "%[1]s", "reasoning": "Penjelasan singkat"}],
+  "overall_sentiment": "%[1]s",
+  "overall_reasoning": "Penjelasan singkat tentang sikap responden secara keseluruhan"
+}`, example)
+		reasoningInstruction = "\nBerikan penjelasan singkat dalam bahasa Indonesia untuk setiap reasoning."
+	}
+
+	systemPrompt := fmt.Sprintf(`Anda adalah sistem analisis sentimen yang sangat akurat. Anda menerima seluruh jawaban seorang responden terhadap sebuah kuesioner.
+
+Tentukan sentimen setiap jawaban berdasarkan pertanyaannya, dengan mempertimbangkan jawaban lain dari responden yang sama sebagai konteks (misalnya rujukan seperti "sama seperti sebelumnya"):
+%s
+
+Tentukan juga sentimen keseluruhan responden.
+
+Respons Anda harus dalam format JSON yang valid, dengan satu entri questions untuk setiap question_id:
+%s
+
+Hanya gunakan kata: %s untuk sentiment.%s`, labelDescriptions(scheme), responseFormat, labelList(scheme, "atau"), reasoningInstruction)
+
+	var userPrompt strings.Builder
+	for _, answer := range answers {
+		fmt.Fprintf(&userPrompt, "[%s]\nPertanyaan: %s\nJawaban: %s\n\n", answer.QuestionID, answer.TextPertanyaan, answer.TextJawaban)
+	}
+	userPrompt.WriteString("Analisis sentimen setiap jawaban dan sentimen keseluruhan responden.")
+
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt.String(),
+		},
+	}
+
+	tokensPerQuestion := 30
+	if withReasoning {
+		tokensPerQuestion = 150
+	}
+	maxTokens := tokensPerQuestion*(len(answers)+1) + 50
+	if maxTokens > 4000 {
+		maxTokens = 4000
+	}
+
+	modelName := DefaultModel
+	result, usage, err := c.CallTelkomAI(ctx, messages, modelName, maxTokens, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	survey, err := c.extractSurveyFromResult(result, scheme)
+	if err != nil {
+		logger.LogError("Failed to extract survey sentiment from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+
+	survey.Model = modelName
+	survey.PromptVersion = SurveyPromptVersion
+	if scheme.ID != labels.DefaultSchemeID {
+		survey.PromptVersion += "+" + scheme.ID
+	}
+	survey.Usage = usage
+	return survey, nil
+}
+
+// surveyPayload mirrors the JSON object requested by the survey prompt
+type surveyPayload struct {
+	Questions []struct {
+		QuestionID string `json:"question_id"`
+		Sentiment  string `json:"sentiment"`
+		Reasoning  string `json:"reasoning"`
+	} `json:"questions"`
+	OverallSentiment string `json:"overall_sentiment"`
+	OverallReasoning string `json:"overall_reasoning"`
+}
+
+// extractSurveyFromResult extracts per-question and overall sentiment from LLM result
+func (c *LLMClient) extractSurveyFromResult(result interface{}, scheme *labels.Scheme) (*SurveyResult, error) {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload surveyPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: survey response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+
+	if len(payload.Questions) == 0 {
+		return nil, fmt.Errorf("%w: survey response contains no questions", ErrInvalidResponse)
+	}
+
+	survey := &SurveyResult{
+		Questions:        make(map[string]SentimentResult, len(payload.Questions)),
+		OverallSentiment: c.normalizeLabel(payload.OverallSentiment, scheme),
+	}
+
+	if payload.OverallReasoning != "" {
+		overallReasoning := payload.OverallReasoning
+		survey.OverallReasoning = &overallReasoning
+	}
+
+	for _, question := range payload.Questions {
+		questionResult := SentimentResult{
+			Sentiment: c.normalizeLabel(question.Sentiment, scheme),
+		}
+		if question.Reasoning != "" {
+			reasoning := question.Reasoning
+			questionResult.Reasoning = &reasoning
+		}
+		survey.Questions[question.QuestionID] = questionResult
+	}
+
+	return survey, nil
+}
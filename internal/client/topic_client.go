@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TopicPromptVersion identifies the revision of the topic prompt
+const TopicPromptVersion = "topic-v1"
+
+// maxTopicAnswerLength bounds each answer quoted in the topic prompt
+const maxTopicAnswerLength = 300
+
+// TopicCluster represents one LLM-labeled theme with the indexes of its answers
+type TopicCluster struct {
+	Label   string
+	Members []int
+}
+
+// TopicResult represents the outcome of a topic labeling call
+type TopicResult struct {
+	Topics []TopicCluster
+	Model  string
+	Usage  *model.TokenUsage
+}
+
+// ClusterTopics groups answers into at most maxTopics themes with short
+// Indonesian labels. Members are indexes into answers; answers the model
+// leaves out, assigns twice or references with invalid indexes are ignored.
+func (c *LLMClient) ClusterTopics(ctx context.Context, textPertanyaan string, answers []string, maxTopics int) (*TopicResult, error) {
+	systemPrompt := fmt.Sprintf(`Anda adalah analis survei yang sangat teliti. Tugas Anda adalah mengelompokkan jawaban responden ke dalam tema berdasarkan hal yang dibicarakan, bukan berdasarkan sentimennya.
+
+Aturan:
+- Buat paling banyak %d tema.
+- Beri setiap tema label singkat dalam bahasa Indonesia (2 sampai 4 kata), misalnya "Kecepatan pengiriman".
+- Setiap jawaban hanya boleh masuk ke satu tema. Gunakan nomor jawaban.
+
+Respons Anda harus dalam format JSON yang valid:
+{"topics": [{"label": "Kecepatan pengiriman", "answers": [1, 4, 7]}]}`, maxTopics)
+
+	var userPrompt strings.Builder
+	if textPertanyaan != "" {
+		fmt.Fprintf(&userPrompt, "Pertanyaan: %s\n\n", textPertanyaan)
+	}
+	for i, answer := range answers {
+		if runes := []rune(answer); len(runes) > maxTopicAnswerLength {
+			answer = string(runes[:maxTopicAnswerLength]) + "..."
+		}
+		fmt.Fprintf(&userPrompt, "[%d] %s\n", i+1, answer)
+	}
+	userPrompt.WriteString("\nKelompokkan jawaban-jawaban tersebut ke dalam tema.")
+
+	messages := []model.LLMMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+		{
+			Role:    "user",
+			Content: userPrompt.String(),
+		},
+	}
+
+	maxTokens := 20*maxTopics + 5*len(answers) + 50
+	if maxTokens > 4000 {
+		maxTokens = 4000
+	}
+
+	modelName := DefaultModel
+	result, usage, err := c.CallTelkomAI(ctx, messages, modelName, maxTokens, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := c.extractTopicsFromResult(result, len(answers))
+	if err != nil {
+		logger.LogError("Failed to extract topics from LLM response", logrus.Fields{
+			"result": result,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+
+	return &TopicResult{
+		Topics: topics,
+		Model:  modelName,
+		Usage:  usage,
+	}, nil
+}
+
+// extractTopicsFromResult parses themes and converts 1-based answer numbers to indexes
+func (c *LLMClient) extractTopicsFromResult(result interface{}, answerCount int) ([]TopicCluster, error) {
+	raw, err := rawJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Topics []struct {
+			Label   string `json:"label"`
+			Answers []int  `json:"answers"`
+		} `json:"topics"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: topic response is not valid JSON: %v", ErrInvalidResponse, err)
+	}
+	if len(payload.Topics) == 0 {
+		return nil, fmt.Errorf("%w: topic response contains no topics", ErrInvalidResponse)
+	}
+
+	assigned := make([]bool, answerCount)
+	topics := make([]TopicCluster, 0, len(payload.Topics))
+	for _, topic := range payload.Topics {
+		label := strings.TrimSpace(topic.Label)
+		if label == "" {
+			continue
+		}
+
+		cluster := TopicCluster{Label: label}
+		for _, number := range topic.Answers {
+			index := number - 1
+			if index < 0 || index >= answerCount || assigned[index] {
+				continue
+			}
+			assigned[index] = true
+			cluster.Members = append(cluster.Members, index)
+		}
+		if len(cluster.Members) > 0 {
+			topics = append(topics, cluster)
+		}
+	}
+
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("%w: topic response assigns no answers", ErrInvalidResponse)
+	}
+	return topics, nil
+}
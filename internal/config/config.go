@@ -1,17 +1,28 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server ServerConfig
-	LLM    LLMConfig
-	Log    LogConfig
+	Server   ServerConfig
+	LLM      LLMConfig
+	Log      LogConfig
+	Auth     AuthConfig
+	CORS     CORSConfig
+	Limiter  LimiterConfig
+	Budget   BudgetConfig
+	Storage  StorageConfig
+	Analysis AnalysisConfig
+	Ensemble EnsembleConfig
+	Routing  RoutingConfig
+	Shadow   ShadowConfig
 }
 
 // ServerConfig holds server configuration
@@ -22,8 +33,12 @@ type ServerConfig struct {
 
 // LLMConfig holds LLM API configuration
 type LLMConfig struct {
-	APIKey string
-	URL    string
+	APIKey           string
+	URL              string
+	ProvidersFile    string
+	MaxRetries       int
+	FailureThreshold int
+	CooldownSeconds  int
 }
 
 // LogConfig holds logging configuration
@@ -32,6 +47,113 @@ type LogConfig struct {
 	Format string
 }
 
+// AuthConfig holds API authentication configuration
+type AuthConfig struct {
+	Enabled              bool
+	APIKeys              string
+	APIKeysFile          string
+	DefaultRateLimit     int
+	DefaultDailyRequests int
+	DefaultDailyTokens   int
+	DefaultScopes        string
+	JWTSecret            string
+	JWTJWKSFile          string
+	JWTIssuer            string
+	JWTAudience          string
+	JWTRoleScopes        string
+	// JWT limits apply per token subject; zero uses the AUTH_DEFAULT_* value
+	JWTRateLimit     int
+	JWTDailyRequests int
+	JWTDailyTokens   int
+}
+
+// CORSConfig holds cross-origin resource sharing configuration
+type CORSConfig struct {
+	AllowedOrigins   string
+	AllowedMethods   string
+	AllowedHeaders   string
+	ExposedHeaders   string
+	AllowCredentials bool
+	MaxAgeSeconds    int
+	OverridesFile    string
+}
+
+// LimiterConfig holds LLM concurrency limiting configuration
+type LimiterConfig struct {
+	InitialConcurrency int
+	MinConcurrency     int
+	MaxConcurrency     int
+	QueueSize          int
+	// QueueTimeoutSeconds bounds the wait for a slot; zero or less uses 30 seconds
+	QueueTimeoutSeconds int
+	TargetLatencyMs     int
+}
+
+// BudgetConfig holds LLM spend guardrail configuration
+type BudgetConfig struct {
+	Enabled           bool
+	PricesFile        string
+	Currency          string
+	DefaultDailyCap   float64
+	DefaultMonthlyCap float64
+	WarningThresholds string
+	ExceededAction    string
+	FallbackEngine    string
+}
+
+// StorageConfig holds analysis persistence configuration
+type StorageConfig struct {
+	Enabled       bool
+	Driver        string
+	Path          string
+	StoreText     bool
+	RetentionDays int
+}
+
+// AnalysisConfig holds optional analysis mode configuration
+type AnalysisConfig struct {
+	AspectTaxonomy     string
+	LabelSchemesFile   string
+	DefaultLabelScheme string
+	LabelLanguage      string
+	SummaryChunkChars  int
+	ExperimentsFile    string
+}
+
+// EnsembleConfig holds ensemble engine configuration
+type EnsembleConfig struct {
+	Members         string
+	Strategy        string
+	ReviewThreshold float64
+}
+
+// RoutingConfig holds per-request engine routing configuration
+type RoutingConfig struct {
+	RulesFile     string
+	DefaultEngine string
+}
+
+// ShadowConfig holds shadow evaluation configuration
+type ShadowConfig struct {
+	Enabled     bool
+	Engine      string
+	Provider    string
+	Prompt      string
+	SampleRate  float64
+	MaxInFlight int
+}
+
+// AspectList returns the configured aspect taxonomy as a list
+func (c AnalysisConfig) AspectList() []string {
+	var aspects []string
+	for _, aspect := range strings.Split(c.AspectTaxonomy, ",") {
+		if aspect = strings.TrimSpace(aspect); aspect != "" {
+			aspects = append(aspects, aspect)
+		}
+	}
+	return aspects
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	// Load .env file if exists
@@ -43,13 +165,99 @@ func LoadConfig() (*Config, error) {
 			Port: getEnv("SERVER_PORT", "8080"),
 		},
 		LLM: LLMConfig{
-			APIKey: getEnv("LLM_API_KEY", ""),
-			URL:    getEnv("URL_CHAT_LLM_LLM", ""),
+			APIKey:           getEnv("LLM_API_KEY", ""),
+			URL:              getEnv("URL_CHAT_LLM_LLM", ""),
+			ProvidersFile:    getEnv("LLM_PROVIDERS_FILE", ""),
+			MaxRetries:       getEnvAsInt("LLM_MAX_RETRIES", 1),
+			FailureThreshold: getEnvAsInt("LLM_PROVIDER_FAILURE_THRESHOLD", 3),
+			CooldownSeconds:  getEnvAsInt("LLM_PROVIDER_COOLDOWN_SECONDS", 30),
 		},
 		Log: LogConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Auth: AuthConfig{
+			Enabled:              getEnvAsBool("AUTH_ENABLED", true),
+			APIKeys:              getEnv("AUTH_API_KEYS", ""),
+			APIKeysFile:          getEnv("AUTH_API_KEYS_FILE", ""),
+			DefaultRateLimit:     getEnvAsInt("AUTH_DEFAULT_RATE_LIMIT", 60),
+			DefaultDailyRequests: getEnvAsInt("AUTH_DEFAULT_DAILY_REQUESTS", 10000),
+			DefaultDailyTokens:   getEnvAsInt("AUTH_DEFAULT_DAILY_TOKENS", 0),
+			DefaultScopes:        getEnv("AUTH_DEFAULT_SCOPES", "sentiment:analyze sentiment:batch"),
+			JWTSecret:            getEnv("AUTH_JWT_SECRET", ""),
+			JWTJWKSFile:          getEnv("AUTH_JWT_JWKS_FILE", ""),
+			JWTIssuer:            getEnv("AUTH_JWT_ISSUER", ""),
+			JWTAudience:          getEnv("AUTH_JWT_AUDIENCE", ""),
+			JWTRoleScopes:        getEnv("AUTH_JWT_ROLE_SCOPES", ""),
+			JWTRateLimit:         getEnvAsInt("AUTH_JWT_RATE_LIMIT", 0),
+			JWTDailyRequests:     getEnvAsInt("AUTH_JWT_DAILY_REQUESTS", 0),
+			JWTDailyTokens:       getEnvAsInt("AUTH_JWT_DAILY_TOKENS", 0),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
+			AllowedMethods:   getEnv("CORS_ALLOWED_METHODS", "GET, POST, OPTIONS"),
+			AllowedHeaders:   getEnv("CORS_ALLOWED_HEADERS", "Content-Type, Authorization, X-API-Key, X-Request-ID"),
+			ExposedHeaders:   getEnv("CORS_EXPOSED_HEADERS", "X-Request-ID, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-Quota-Requests-Limit, X-Quota-Requests-Remaining, X-Quota-Tokens-Limit, X-Quota-Tokens-Remaining, X-Quota-Reset"),
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAgeSeconds:    getEnvAsInt("CORS_MAX_AGE_SECONDS", 600),
+			OverridesFile:    getEnv("CORS_OVERRIDES_FILE", ""),
+		},
+		Limiter: LimiterConfig{
+			InitialConcurrency:  getEnvAsInt("LLM_CONCURRENCY_INITIAL", 8),
+			MinConcurrency:      getEnvAsInt("LLM_CONCURRENCY_MIN", 2),
+			MaxConcurrency:      getEnvAsInt("LLM_CONCURRENCY_MAX", 32),
+			QueueSize:           getEnvAsInt("LLM_QUEUE_SIZE", 64),
+			QueueTimeoutSeconds: getEnvAsInt("LLM_QUEUE_TIMEOUT_SECONDS", 30),
+			TargetLatencyMs:     getEnvAsInt("LLM_TARGET_LATENCY_MS", 5000),
+		},
+		Budget: BudgetConfig{
+			Enabled:           getEnvAsBool("BUDGET_ENABLED", false),
+			PricesFile:        getEnv("BUDGET_PRICES_FILE", ""),
+			Currency:          getEnv("BUDGET_CURRENCY", "IDR"),
+			DefaultDailyCap:   getEnvAsFloat("BUDGET_DEFAULT_DAILY_CAP", 0),
+			DefaultMonthlyCap: getEnvAsFloat("BUDGET_DEFAULT_MONTHLY_CAP", 0),
+			WarningThresholds: getEnv("BUDGET_WARNING_THRESHOLDS", "0.5,0.8,0.9"),
+			ExceededAction:    getEnv("BUDGET_EXCEEDED_ACTION", "downgrade"),
+			FallbackEngine:    getEnv("BUDGET_FALLBACK_ENGINE", "lexicon"),
+		},
+		Storage: StorageConfig{
+			Enabled:       getEnvAsBool("STORAGE_ENABLED", true),
+			Driver:        getEnv("STORAGE_DRIVER", "sqlite"),
+			Path:          getEnv("STORAGE_PATH", "data/sentiment.db"),
+			StoreText:     getEnvAsBool("STORAGE_STORE_TEXT", false),
+			RetentionDays: getEnvAsInt("STORAGE_RETENTION_DAYS", 90),
+		},
+		Analysis: AnalysisConfig{
+			AspectTaxonomy:     getEnv("ASPECT_TAXONOMY", "produk,harga,layanan,pengiriman"),
+			LabelSchemesFile:   getEnv("LABEL_SCHEMES_FILE", ""),
+			DefaultLabelScheme: getEnv("DEFAULT_LABEL_SCHEME", "default"),
+			LabelLanguage:      getEnv("OUTPUT_LABEL_LANGUAGE", "id"),
+			SummaryChunkChars:  getEnvAsInt("SUMMARY_CHUNK_CHARS", 6000),
+			ExperimentsFile:    getEnv("EXPERIMENTS_FILE", ""),
+		},
+		Ensemble: EnsembleConfig{
+			Members:         getEnv("ENSEMBLE_MEMBERS", "llm,lexicon"),
+			Strategy:        getEnv("ENSEMBLE_STRATEGY", "majority"),
+			ReviewThreshold: getEnvAsFloat("ENSEMBLE_REVIEW_THRESHOLD", 0.75),
+		},
+		Routing: RoutingConfig{
+			RulesFile:     getEnv("ROUTING_RULES_FILE", ""),
+			DefaultEngine: getEnv("ROUTING_DEFAULT_ENGINE", "llm"),
+		},
+		Shadow: ShadowConfig{
+			Enabled:     getEnvAsBool("SHADOW_ENABLED", false),
+			Engine:      getEnv("SHADOW_ENGINE", "llm"),
+			Provider:    getEnv("SHADOW_PROVIDER", ""),
+			Prompt:      getEnv("SHADOW_PROMPT", "standard"),
+			SampleRate:  getEnvAsFloat("SHADOW_SAMPLE_RATE", 0.05),
+			MaxInFlight: getEnvAsInt("SHADOW_MAX_IN_FLIGHT", 4),
+		},
+	}
+
+	switch config.Analysis.LabelLanguage {
+	case "id", "en", "auto":
+	default:
+		return nil, fmt.Errorf("OUTPUT_LABEL_LANGUAGE must be one of id, en or auto, got %q", config.Analysis.LabelLanguage)
 	}
 
 	return config, nil
@@ -73,6 +281,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat gets environment variable as float with default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvAsBool gets environment variable as boolean with default value
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
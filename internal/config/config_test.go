@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestLoadConfigLabelLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: "id"},
+		{name: "english", value: "en", want: "en"},
+		{name: "auto", value: "auto", want: "auto"},
+		{name: "unknown language", value: "fr", wantErr: true},
+		{name: "wrong case", value: "EN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OUTPUT_LABEL_LANGUAGE", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Analysis.LabelLanguage != tt.want {
+				t.Errorf("LabelLanguage = %q, want %q", cfg.Analysis.LabelLanguage, tt.want)
+			}
+		})
+	}
+}
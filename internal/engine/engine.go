@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/lexicon"
+)
+
+// LexiconName names the offline lexicon engine
+const LexiconName = "lexicon"
+
+// Engine analyzes the sentiment of one answer
+type Engine interface {
+	// Name identifies the engine, e.g. "lexicon" or "llm:telkom-ai-instruct"
+	Name() string
+	Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*client.SentimentResult, error)
+}
+
+// LLMEngine analyzes answers with one LLM model
+type LLMEngine struct {
+	llmClient *client.LLMClient
+	model     string
+}
+
+// NewLLMEngine creates an engine calling the given model, or client.DefaultModel when empty
+func NewLLMEngine(llmClient *client.LLMClient, model string) *LLMEngine {
+	if model == "" {
+		model = client.DefaultModel
+	}
+	return &LLMEngine{
+		llmClient: llmClient,
+		model:     model,
+	}
+}
+
+// Name returns "llm:" followed by the model
+func (e *LLMEngine) Name() string {
+	return "llm:" + e.model
+}
+
+// Model returns the model the engine calls
+func (e *LLMEngine) Model() string {
+	return e.model
+}
+
+// Analyze runs the sentiment prompt against the engine's model
+func (e *LLMEngine) Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*client.SentimentResult, error) {
+	opts.Model = e.model
+	if withReasoning {
+		return e.llmClient.AnalyzeSentimentWithReasoning(ctx, textPertanyaan, textJawaban, opts)
+	}
+	return e.llmClient.AnalyzeSentiment(ctx, textPertanyaan, textJawaban, opts)
+}
+
+// LexiconEngine analyzes answers locally with the Indonesian lexicon
+type LexiconEngine struct {
+	analyzer *lexicon.Analyzer
+}
+
+// NewLexiconEngine creates a lexicon engine
+func NewLexiconEngine(analyzer *lexicon.Analyzer) *LexiconEngine {
+	return &LexiconEngine{
+		analyzer: analyzer,
+	}
+}
+
+// Name returns LexiconName
+func (e *LexiconEngine) Name() string {
+	return LexiconName
+}
+
+// Analyze scores the answer and maps the lexicon's polarity onto the closest
+// label of the scheme. It never fails and reports no token usage.
+func (e *LexiconEngine) Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*client.SentimentResult, error) {
+	lexiconResult := e.analyzer.Analyze(textJawaban)
+
+	scheme := opts.Scheme
+	if scheme == nil {
+		scheme = labels.Builtin()
+	}
+
+	result := &client.SentimentResult{
+		Sentiment:     scheme.ForPolarity(labels.Builtin().Polarity(lexiconResult.Sentiment)),
+		Model:         LexiconName,
+		PromptVersion: "lexicon-v1",
+	}
+
+	if withReasoning {
+		reasoning := lexiconResult.Explain()
+		result.Reasoning = &reasoning
+	}
+
+	return result, nil
+}
+
+// Parse builds an engine from its name: "lexicon", "llm" for the default
+// model or "llm:<model>"
+func Parse(name string, llmClient *client.LLMClient, analyzer *lexicon.Analyzer) (Engine, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == LexiconName:
+		return NewLexiconEngine(analyzer), nil
+	case name == "llm":
+		return NewLLMEngine(llmClient, ""), nil
+	case strings.HasPrefix(name, "llm:") && len(name) > len("llm:"):
+		return NewLLMEngine(llmClient, strings.TrimPrefix(name, "llm:")), nil
+	}
+	return nil, fmt.Errorf("unknown engine %q", name)
+}
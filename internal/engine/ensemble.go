@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/lexicon"
+)
+
+// Voting strategies of an ensemble
+const (
+	StrategyMajority = "majority"
+	StrategyWeighted = "weighted"
+)
+
+// EnsemblePromptVersion tags results produced by an ensemble
+const EnsemblePromptVersion = "ensemble-v1"
+
+// ErrNoVerdict is returned when every ensemble member failed. The error also
+// wraps each member's error, so errors.Is reports provider.ErrUnavailable when
+// the members failed because no LLM provider could serve them.
+var ErrNoVerdict = errors.New("no ensemble member returned a verdict")
+
+// Member is one engine of an ensemble with its vote weight
+type Member struct {
+	Engine Engine
+	Weight float64
+}
+
+// MemberVerdict represents one member's outcome within an ensemble run
+type MemberVerdict struct {
+	Engine  string
+	Weight  float64
+	Result  *client.SentimentResult
+	Latency time.Duration
+	Err     error
+	Agrees  bool
+}
+
+// EnsembleResult represents the combined verdict of an ensemble
+type EnsembleResult struct {
+	Sentiment string
+	Reasoning *string
+	Strategy  string
+	// Agreement is the share of the successful members' vote (weighted for
+	// StrategyWeighted) that went to the winning label, from 0 to 1
+	Agreement float64
+	// NeedsReview is set when Agreement is below the review threshold
+	NeedsReview bool
+	Members     []MemberVerdict
+}
+
+// Ensemble runs several engines on the same answer and combines their labels by vote
+type Ensemble struct {
+	members         []Member
+	strategy        string
+	reviewThreshold float64
+}
+
+// NewEnsemble creates an ensemble. Members with a non-positive weight count as 1.
+// Verdicts with an agreement below reviewThreshold are flagged for human review.
+func NewEnsemble(members []Member, strategy string, reviewThreshold float64) (*Ensemble, error) {
+	if len(members) < 2 {
+		return nil, errors.New("an ensemble needs at least two members")
+	}
+	if strategy != StrategyMajority && strategy != StrategyWeighted {
+		return nil, fmt.Errorf("unknown ensemble strategy %q", strategy)
+	}
+
+	normalized := make([]Member, len(members))
+	for i, member := range members {
+		if member.Weight <= 0 {
+			member.Weight = 1
+		}
+		normalized[i] = member
+	}
+
+	return &Ensemble{
+		members:         normalized,
+		strategy:        strategy,
+		reviewThreshold: reviewThreshold,
+	}, nil
+}
+
+// Analyze runs every member concurrently and votes on their labels. Failed
+// members are reported but do not vote. Ties go to the label first returned
+// in member order, so configuring the most trusted engine first breaks ties.
+func (e *Ensemble) Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*EnsembleResult, error) {
+	verdicts := make([]MemberVerdict, len(e.members))
+
+	var wg sync.WaitGroup
+	for i, member := range e.members {
+		wg.Add(1)
+		go func(i int, member Member) {
+			defer wg.Done()
+			start := time.Now()
+			result, err := member.Engine.Analyze(ctx, textPertanyaan, textJawaban, opts, withReasoning)
+			verdicts[i] = MemberVerdict{
+				Engine:  member.Engine.Name(),
+				Weight:  member.Weight,
+				Result:  result,
+				Latency: time.Since(start),
+				Err:     err,
+			}
+		}(i, member)
+	}
+	wg.Wait()
+
+	votes := make(map[string]float64)
+	var order []string
+	total := 0.0
+	for _, verdict := range verdicts {
+		if verdict.Err != nil {
+			continue
+		}
+		vote := 1.0
+		if e.strategy == StrategyWeighted {
+			vote = verdict.Weight
+		}
+		if _, seen := votes[verdict.Result.Sentiment]; !seen {
+			order = append(order, verdict.Result.Sentiment)
+		}
+		votes[verdict.Result.Sentiment] += vote
+		total += vote
+	}
+
+	if total == 0 {
+		return nil, noVerdictError(verdicts)
+	}
+
+	winner := order[0]
+	for _, label := range order[1:] {
+		if votes[label] > votes[winner] {
+			winner = label
+		}
+	}
+
+	result := &EnsembleResult{
+		Sentiment: winner,
+		Strategy:  e.strategy,
+		Agreement: votes[winner] / total,
+		Members:   verdicts,
+	}
+	result.NeedsReview = result.Agreement < e.reviewThreshold
+
+	for i := range result.Members {
+		verdict := &result.Members[i]
+		verdict.Agrees = verdict.Err == nil && verdict.Result.Sentiment == winner
+		if verdict.Agrees && result.Reasoning == nil {
+			result.Reasoning = verdict.Result.Reasoning
+		}
+	}
+
+	return result, nil
+}
+
+// noVerdictError wraps ErrNoVerdict and every member error
+func noVerdictError(verdicts []MemberVerdict) error {
+	format := "%w"
+	args := []interface{}{ErrNoVerdict}
+	for i, verdict := range verdicts {
+		separator := "; "
+		if i == 0 {
+			separator = ": "
+		}
+		format += separator + "%s: %w"
+		args = append(args, verdict.Engine, verdict.Err)
+	}
+	return fmt.Errorf(format, args...)
+}
+
+// Names returns the member engine names in order
+func (e *Ensemble) Names() []string {
+	names := make([]string, len(e.members))
+	for i, member := range e.members {
+		names[i] = member.Engine.Name()
+	}
+	return names
+}
+
+// ParseMembers parses a member list such as "llm:telkom-ai-instruct=2,lexicon=1".
+// A member without "=weight" has weight 1.
+func ParseMembers(value string, llmClient *client.LLMClient, analyzer *lexicon.Analyzer) ([]Member, error) {
+	var members []Member
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, weight, err := parseWeight(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		memberEngine, err := Parse(name, llmClient, analyzer)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, Member{Engine: memberEngine, Weight: weight})
+	}
+	return members, nil
+}
+
+// parseWeight parses an optional member weight
+func parseWeight(value string) (string, float64, error) {
+	name, weightText, found := strings.Cut(value, "=")
+	if !found {
+		return name, 1, nil
+	}
+	weight, err := strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+	if err != nil || weight <= 0 {
+		return "", 0, fmt.Errorf("invalid weight for ensemble member %q", name)
+	}
+	return name, weight, nil
+}
@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/provider"
+)
+
+// stubEngine returns a fixed label, or err when it is set
+type stubEngine struct {
+	name      string
+	sentiment string
+	reasoning string
+	err       error
+}
+
+func (e *stubEngine) Name() string {
+	return e.name
+}
+
+func (e *stubEngine) Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*client.SentimentResult, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	result := &client.SentimentResult{Sentiment: e.sentiment, Model: e.name}
+	if e.reasoning != "" {
+		reasoning := e.reasoning
+		result.Reasoning = &reasoning
+	}
+	return result, nil
+}
+
+// vote returns a member that labels every answer with sentiment
+func vote(name, sentiment string, weight float64) Member {
+	return Member{Engine: &stubEngine{name: name, sentiment: sentiment, reasoning: name + " reasoning"}, Weight: weight}
+}
+
+// fail returns a member that fails with err
+func fail(name string, err error) Member {
+	return Member{Engine: &stubEngine{name: name, err: err}, Weight: 1}
+}
+
+func TestEnsembleAnalyze(t *testing.T) {
+	tests := []struct {
+		name            string
+		members         []Member
+		strategy        string
+		wantSentiment   string
+		wantAgreement   float64
+		wantNeedsReview bool
+		wantAgrees      []bool
+		wantReasoning   string
+	}{
+		{
+			name:          "unanimous",
+			members:       []Member{vote("a", "Positif", 1), vote("b", "Positif", 1)},
+			strategy:      StrategyMajority,
+			wantSentiment: "Positif",
+			wantAgreement: 1,
+			wantAgrees:    []bool{true, true},
+			wantReasoning: "a reasoning",
+		},
+		{
+			name:            "majority ignores weights",
+			members:         []Member{vote("a", "Positif", 1), vote("b", "Positif", 1), vote("c", "Negatif", 3)},
+			strategy:        StrategyMajority,
+			wantSentiment:   "Positif",
+			wantAgreement:   2.0 / 3,
+			wantNeedsReview: true,
+			wantAgrees:      []bool{true, true, false},
+			wantReasoning:   "a reasoning",
+		},
+		{
+			name:            "weighted vote",
+			members:         []Member{vote("a", "Positif", 1), vote("b", "Positif", 1), vote("c", "Negatif", 3)},
+			strategy:        StrategyWeighted,
+			wantSentiment:   "Negatif",
+			wantAgreement:   0.6,
+			wantNeedsReview: true,
+			wantAgrees:      []bool{false, false, true},
+			wantReasoning:   "c reasoning",
+		},
+		{
+			name:          "weighted vote above the review threshold",
+			members:       []Member{vote("a", "Netral", 4), vote("b", "Positif", 1)},
+			strategy:      StrategyWeighted,
+			wantSentiment: "Netral",
+			wantAgreement: 0.8,
+			wantAgrees:    []bool{true, false},
+			wantReasoning: "a reasoning",
+		},
+		{
+			name:            "tie goes to the first member",
+			members:         []Member{vote("a", "Negatif", 1), vote("b", "Positif", 1)},
+			strategy:        StrategyMajority,
+			wantSentiment:   "Negatif",
+			wantAgreement:   0.5,
+			wantNeedsReview: true,
+			wantAgrees:      []bool{true, false},
+			wantReasoning:   "a reasoning",
+		},
+		{
+			name:            "tie in reversed order",
+			members:         []Member{vote("b", "Positif", 1), vote("a", "Negatif", 1)},
+			strategy:        StrategyMajority,
+			wantSentiment:   "Positif",
+			wantAgreement:   0.5,
+			wantNeedsReview: true,
+			wantAgrees:      []bool{true, false},
+			wantReasoning:   "b reasoning",
+		},
+		{
+			name:            "weighted tie goes to the first label returned",
+			members:         []Member{vote("a", "Negatif", 1), vote("b", "Positif", 2), vote("c", "Negatif", 1)},
+			strategy:        StrategyWeighted,
+			wantSentiment:   "Negatif",
+			wantAgreement:   0.5,
+			wantNeedsReview: true,
+			wantAgrees:      []bool{true, false, true},
+			wantReasoning:   "a reasoning",
+		},
+		{
+			name:          "failed member does not vote",
+			members:       []Member{fail("a", errors.New("timeout")), vote("b", "Positif", 1), vote("c", "Positif", 1)},
+			strategy:      StrategyMajority,
+			wantSentiment: "Positif",
+			wantAgreement: 1,
+			wantAgrees:    []bool{false, true, true},
+			wantReasoning: "b reasoning",
+		},
+		{
+			name:            "non-positive weight counts as one",
+			members:         []Member{vote("a", "Positif", 0), vote("b", "Negatif", -2), vote("c", "Positif", 1)},
+			strategy:        StrategyWeighted,
+			wantSentiment:   "Positif",
+			wantAgreement:   2.0 / 3,
+			wantNeedsReview: true,
+			wantAgrees:      []bool{true, false, true},
+			wantReasoning:   "a reasoning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ensemble, err := NewEnsemble(tt.members, tt.strategy, 0.7)
+			if err != nil {
+				t.Fatalf("NewEnsemble() error = %v", err)
+			}
+
+			got, err := ensemble.Analyze(context.Background(), "", "jawaban", client.AnalysisOptions{}, true)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			if got.Sentiment != tt.wantSentiment {
+				t.Errorf("Sentiment = %q, want %q", got.Sentiment, tt.wantSentiment)
+			}
+			if got.Strategy != tt.strategy {
+				t.Errorf("Strategy = %q, want %q", got.Strategy, tt.strategy)
+			}
+			if diff := got.Agreement - tt.wantAgreement; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Agreement = %v, want %v", got.Agreement, tt.wantAgreement)
+			}
+			if got.NeedsReview != tt.wantNeedsReview {
+				t.Errorf("NeedsReview = %v, want %v", got.NeedsReview, tt.wantNeedsReview)
+			}
+			if got.Reasoning == nil || *got.Reasoning != tt.wantReasoning {
+				t.Errorf("Reasoning = %v, want %q", got.Reasoning, tt.wantReasoning)
+			}
+
+			agrees := make([]bool, len(got.Members))
+			for i, member := range got.Members {
+				agrees[i] = member.Agrees
+				if member.Engine != tt.members[i].Engine.Name() {
+					t.Errorf("Members[%d].Engine = %q, want member order kept", i, member.Engine)
+				}
+			}
+			if !reflect.DeepEqual(agrees, tt.wantAgrees) {
+				t.Errorf("Agrees = %v, want %v", agrees, tt.wantAgrees)
+			}
+		})
+	}
+}
+
+func TestEnsembleAnalyzeNoVerdict(t *testing.T) {
+	unavailable := fmt.Errorf("%w: %w", provider.ErrUnavailable, errors.New("status 503"))
+
+	tests := []struct {
+		name            string
+		members         []Member
+		wantUnavailable bool
+	}{
+		{
+			name:            "every provider unavailable",
+			members:         []Member{fail("llm:a", unavailable), fail("llm:b", unavailable)},
+			wantUnavailable: true,
+		},
+		{
+			name:            "some members unavailable",
+			members:         []Member{fail("llm:a", errors.New("invalid response")), fail("llm:b", unavailable)},
+			wantUnavailable: true,
+		},
+		{
+			name:    "other failures",
+			members: []Member{fail("llm:a", errors.New("invalid response")), fail("llm:b", context.DeadlineExceeded)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ensemble, err := NewEnsemble(tt.members, StrategyMajority, 0.5)
+			if err != nil {
+				t.Fatalf("NewEnsemble() error = %v", err)
+			}
+
+			result, err := ensemble.Analyze(context.Background(), "", "jawaban", client.AnalysisOptions{}, false)
+			if result != nil || !errors.Is(err, ErrNoVerdict) {
+				t.Fatalf("Analyze() = %+v, %v, want ErrNoVerdict", result, err)
+			}
+			if got := errors.Is(err, provider.ErrUnavailable); got != tt.wantUnavailable {
+				t.Errorf("errors.Is(err, provider.ErrUnavailable) = %v, want %v (%v)", got, tt.wantUnavailable, err)
+			}
+			for _, member := range tt.members {
+				if !errors.Is(err, member.Engine.(*stubEngine).err) {
+					t.Errorf("error %q does not wrap the error of %s", err, member.Engine.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestNewEnsemble(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []Member
+		strategy string
+		wantErr  bool
+	}{
+		{name: "majority", members: []Member{vote("a", "Positif", 1), vote("b", "Positif", 1)}, strategy: StrategyMajority},
+		{name: "weighted", members: []Member{vote("a", "Positif", 1), vote("b", "Positif", 1)}, strategy: StrategyWeighted},
+		{name: "single member", members: []Member{vote("a", "Positif", 1)}, strategy: StrategyMajority, wantErr: true},
+		{name: "unknown strategy", members: []Member{vote("a", "Positif", 1), vote("b", "Positif", 1)}, strategy: "average", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEnsemble(tt.members, tt.strategy, 0.5); (err != nil) != tt.wantErr {
+				t.Errorf("NewEnsemble() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"sentiment-api/internal/client"
+)
+
+// Prompts an LLM engine can analyze answers with
+const (
+	PromptStandard = "standard"
+	PromptNuance   = "nuance"
+)
+
+// NuanceEngine analyzes answers with the nuance prompt, which also flags
+// sarcasm and mixed sentiment
+type NuanceEngine struct {
+	llm *LLMEngine
+}
+
+// Name returns the LLM engine's name followed by "/nuance"
+func (e *NuanceEngine) Name() string {
+	return e.llm.Name() + "/" + PromptNuance
+}
+
+// Analyze runs the nuance prompt against the engine's model
+func (e *NuanceEngine) Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*client.SentimentResult, error) {
+	opts.Model = e.llm.model
+	return e.llm.llmClient.AnalyzeSentimentNuanced(ctx, textPertanyaan, textJawaban, opts, withReasoning)
+}
+
+// WithPrompt returns an engine analyzing with the given prompt. Only LLM
+// engines support prompts other than PromptStandard.
+func WithPrompt(e Engine, prompt string) (Engine, error) {
+	switch prompt {
+	case "", PromptStandard:
+		return e, nil
+	case PromptNuance:
+		llm, ok := e.(*LLMEngine)
+		if !ok {
+			return nil, fmt.Errorf("prompt %q needs an LLM engine", prompt)
+		}
+		return &NuanceEngine{llm: llm}, nil
+	}
+	return nil, fmt.Errorf("unknown prompt %q", prompt)
+}
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/lexicon"
+)
+
+// DefaultRouteName names the route taken when no rule matches
+const DefaultRouteName = "default"
+
+// Rule routes matching requests to an engine. Unset conditions match every
+// request; a rule matches when all of its set conditions do.
+type Rule struct {
+	Name string `json:"name"`
+	// MinChars and MaxChars bound the answer length in characters; 0 leaves a bound open
+	MinChars  int      `json:"min_chars,omitempty"`
+	MaxChars  int      `json:"max_chars,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Mixed     *bool    `json:"mixed,omitempty"`
+	Tenants   []string `json:"tenants,omitempty"`
+	Reasoning *bool    `json:"reasoning,omitempty"`
+	// Engine is an engine name as accepted by Parse
+	Engine string `json:"engine"`
+}
+
+// RouteRequest holds the request properties rules are matched against
+type RouteRequest struct {
+	TextJawaban string
+	Language    string
+	Mixed       bool
+	Tenant      string
+	Reasoning   bool
+}
+
+// Route is the engine chosen for a request and the rule that chose it
+type Route struct {
+	Rule   string
+	Engine Engine
+}
+
+// Router picks an engine per request from an ordered list of rules
+type Router struct {
+	rules   []Rule
+	engines []Engine
+	route   Route
+}
+
+// NewRouter creates a router from a JSON array of rules; the first matching
+// rule wins and requests matching no rule go to the default engine.
+// Without a rules file every request goes to the default engine.
+func NewRouter(path, defaultEngine string, llmClient *client.LLMClient, analyzer *lexicon.Analyzer) (*Router, error) {
+	fallback, err := Parse(defaultEngine, llmClient, analyzer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default route: %w", err)
+	}
+
+	router := &Router{
+		route: Route{Rule: DefaultRouteName, Engine: fallback},
+	}
+
+	if path == "" {
+		return router, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing rules file: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse routing rules file: %w", err)
+	}
+
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if rule.MaxChars > 0 && rule.MinChars > rule.MaxChars {
+			return nil, fmt.Errorf("routing rule %q: min_chars exceeds max_chars", rule.Name)
+		}
+
+		routed, err := Parse(rule.Engine, llmClient, analyzer)
+		if err != nil {
+			return nil, fmt.Errorf("routing rule %q: %w", rule.Name, err)
+		}
+
+		router.rules = append(router.rules, rule)
+		router.engines = append(router.engines, routed)
+	}
+
+	return router, nil
+}
+
+// Route returns the engine of the first rule matching the request
+func (r *Router) Route(req RouteRequest) Route {
+	for i, rule := range r.rules {
+		if rule.matches(req) {
+			return Route{Rule: rule.Name, Engine: r.engines[i]}
+		}
+	}
+	return r.route
+}
+
+// Len returns the number of routing rules
+func (r *Router) Len() int {
+	return len(r.rules)
+}
+
+// matches reports whether every condition set on the rule holds for the request
+func (rule Rule) matches(req RouteRequest) bool {
+	chars := utf8.RuneCountInString(strings.TrimSpace(req.TextJawaban))
+	if chars < rule.MinChars {
+		return false
+	}
+	if rule.MaxChars > 0 && chars > rule.MaxChars {
+		return false
+	}
+	if len(rule.Languages) > 0 && !contains(rule.Languages, req.Language) {
+		return false
+	}
+	if rule.Mixed != nil && *rule.Mixed != req.Mixed {
+		return false
+	}
+	if len(rule.Tenants) > 0 && !contains(rule.Tenants, req.Tenant) {
+		return false
+	}
+	if rule.Reasoning != nil && *rule.Reasoning != req.Reasoning {
+		return false
+	}
+	return true
+}
+
+// contains reports whether values holds value, ignoring case
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package evaluation
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sample is one answer of a dataset with its expected label, if labeled
+type Sample struct {
+	TextPertanyaan string `json:"text_pertanyaan"`
+	TextJawaban    string `json:"text_jawaban"`
+	Label          string `json:"label,omitempty"`
+}
+
+// LoadDataset reads samples from a JSONL file (one object per line) or a CSV
+// file with a header row naming the text_pertanyaan, text_jawaban and label
+// columns. The format is chosen by the file extension.
+func LoadDataset(path string) ([]Sample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+
+	var samples []Sample
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		samples, err = readJSONL(file)
+	case ".csv":
+		samples, err = readCSV(file)
+	default:
+		return nil, fmt.Errorf("unsupported dataset format %q, use .jsonl or .csv", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(samples) == 0 {
+		return nil, errors.New("dataset is empty")
+	}
+	return samples, nil
+}
+
+// readJSONL reads one sample per non-empty line
+func readJSONL(r io.Reader) ([]Sample, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var samples []Sample
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var sample Sample
+		if err := json.Unmarshal([]byte(text), &sample); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if strings.TrimSpace(sample.TextJawaban) == "" {
+			return nil, fmt.Errorf("line %d: text_jawaban is empty", line)
+		}
+		samples = append(samples, sample)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return samples, nil
+}
+
+// readCSV reads samples from a CSV file with a header row
+func readCSV(r io.Reader) ([]Sample, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["text_jawaban"]; !ok {
+		return nil, errors.New("CSV header has no text_jawaban column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var samples []Sample
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		sample := Sample{
+			TextPertanyaan: field(record, "text_pertanyaan"),
+			TextJawaban:    field(record, "text_jawaban"),
+			Label:          field(record, "label"),
+		}
+		if sample.TextJawaban == "" {
+			return nil, fmt.Errorf("row %d: text_jawaban is empty", row)
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,117 @@
+package evaluation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadDataset(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []Sample
+		wantErr bool
+	}{
+		{
+			name: "jsonl skips blank lines",
+			file: "data.jsonl",
+			content: `{"text_pertanyaan": "Bagaimana layanan kami?", "text_jawaban": "Sangat memuaskan", "label": "Positif"}
+
+{"text_jawaban": "Lambat"}
+`,
+			want: []Sample{
+				{TextPertanyaan: "Bagaimana layanan kami?", TextJawaban: "Sangat memuaskan", Label: "Positif"},
+				{TextJawaban: "Lambat"},
+			},
+		},
+		{
+			name:    "ndjson extension",
+			file:    "data.NDJSON",
+			content: `{"text_jawaban": "Biasa saja", "label": "Netral"}`,
+			want:    []Sample{{TextJawaban: "Biasa saja", Label: "Netral"}},
+		},
+		{
+			name:    "jsonl invalid line",
+			file:    "data.jsonl",
+			content: "{\"text_jawaban\": \"Bagus\"}\n{not json}\n",
+			wantErr: true,
+		},
+		{
+			name:    "jsonl empty answer",
+			file:    "data.jsonl",
+			content: `{"text_pertanyaan": "Bagaimana layanan kami?", "text_jawaban": "  "}`,
+			wantErr: true,
+		},
+		{
+			name:    "csv with header in any case and order",
+			file:    "data.csv",
+			content: "Label, TEXT_JAWABAN ,text_pertanyaan\nPositif,Sangat memuaskan,Bagaimana layanan kami?\nNegatif,\"Lambat, mahal\"\n",
+			want: []Sample{
+				{TextPertanyaan: "Bagaimana layanan kami?", TextJawaban: "Sangat memuaskan", Label: "Positif"},
+				{TextJawaban: "Lambat, mahal", Label: "Negatif"},
+			},
+		},
+		{
+			name:    "csv without label column",
+			file:    "data.csv",
+			content: "text_jawaban\nBiasa saja\n",
+			want:    []Sample{{TextJawaban: "Biasa saja"}},
+		},
+		{
+			name:    "csv missing answer column",
+			file:    "data.csv",
+			content: "text_pertanyaan,label\nBagaimana layanan kami?,Positif\n",
+			wantErr: true,
+		},
+		{
+			name:    "csv empty answer",
+			file:    "data.csv",
+			content: "text_jawaban,label\n,Positif\n",
+			wantErr: true,
+		},
+		{
+			name:    "csv header only",
+			file:    "data.csv",
+			content: "text_jawaban,label\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty jsonl",
+			file:    "data.jsonl",
+			content: "\n\n",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported extension",
+			file:    "data.txt",
+			content: "Sangat memuaskan\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write dataset: %v", err)
+			}
+
+			got, err := LoadDataset(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDataset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadDataset() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDatasetMissingFile(t *testing.T) {
+	if _, err := LoadDataset(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("LoadDataset() error = nil for a missing file")
+	}
+}
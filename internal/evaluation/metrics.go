@@ -0,0 +1,124 @@
+package evaluation
+
+import "math"
+
+// ClassMetrics represents precision, recall and F1 of one label
+type ClassMetrics struct {
+	Label     string  `json:"label"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+// Metrics represents how well predictions match the expected labels
+type Metrics struct {
+	Samples     int            `json:"samples"`
+	Accuracy    float64        `json:"accuracy"`
+	MacroF1     float64        `json:"macro_f1"`
+	WeightedF1  float64        `json:"weighted_f1"`
+	CohensKappa float64        `json:"cohens_kappa"`
+	PerClass    []ClassMetrics `json:"per_class"`
+	Labels      []string       `json:"labels"`
+	// Confusion[i][j] counts samples expected as Labels[i] and predicted as Labels[j]
+	Confusion [][]int `json:"confusion_matrix"`
+}
+
+// Compute compares predicted with expected labels. The label order of the
+// confusion matrix follows labels; labels seen only in the data are appended.
+// Macro F1 averages over labels that were expected or predicted at least
+// once, so scheme labels absent from the dataset do not drag it down.
+func Compute(expected, predicted []string, labels []string) Metrics {
+	index := make(map[string]int, len(labels))
+	order := make([]string, 0, len(labels))
+	addLabel := func(label string) {
+		if _, ok := index[label]; !ok {
+			index[label] = len(order)
+			order = append(order, label)
+		}
+	}
+	for _, label := range labels {
+		addLabel(label)
+	}
+	for i := range expected {
+		addLabel(expected[i])
+		addLabel(predicted[i])
+	}
+
+	confusion := make([][]int, len(order))
+	for i := range confusion {
+		confusion[i] = make([]int, len(order))
+	}
+
+	correct := 0
+	for i := range expected {
+		confusion[index[expected[i]]][index[predicted[i]]]++
+		if expected[i] == predicted[i] {
+			correct++
+		}
+	}
+
+	metrics := Metrics{
+		Samples:   len(expected),
+		Labels:    order,
+		Confusion: confusion,
+		PerClass:  make([]ClassMetrics, 0, len(order)),
+	}
+	if metrics.Samples == 0 {
+		return metrics
+	}
+
+	n := float64(metrics.Samples)
+	metrics.Accuracy = round(float64(correct) / n)
+
+	var f1Sum, weightedF1Sum, chanceAgreement float64
+	observedClasses := 0
+	for i, label := range order {
+		truePositives := confusion[i][i]
+		support, predictedCount := 0, 0
+		for j := range order {
+			support += confusion[i][j]
+			predictedCount += confusion[j][i]
+		}
+
+		class := ClassMetrics{Label: label, Support: support}
+		if predictedCount > 0 {
+			class.Precision = float64(truePositives) / float64(predictedCount)
+		}
+		if support > 0 {
+			class.Recall = float64(truePositives) / float64(support)
+		}
+		if class.Precision+class.Recall > 0 {
+			class.F1 = 2 * class.Precision * class.Recall / (class.Precision + class.Recall)
+		}
+
+		if support > 0 || predictedCount > 0 {
+			f1Sum += class.F1
+			observedClasses++
+		}
+		weightedF1Sum += class.F1 * float64(support)
+		chanceAgreement += float64(support) / n * float64(predictedCount) / n
+
+		class.Precision = round(class.Precision)
+		class.Recall = round(class.Recall)
+		class.F1 = round(class.F1)
+		metrics.PerClass = append(metrics.PerClass, class)
+	}
+
+	metrics.MacroF1 = round(f1Sum / float64(observedClasses))
+	metrics.WeightedF1 = round(weightedF1Sum / n)
+
+	observed := float64(correct) / n
+	if chanceAgreement < 1 {
+		metrics.CohensKappa = round((observed - chanceAgreement) / (1 - chanceAgreement))
+	} else if observed == 1 {
+		metrics.CohensKappa = 1
+	}
+
+	return metrics
+}
+
+// round rounds a ratio to four decimals
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
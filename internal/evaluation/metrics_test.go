@@ -0,0 +1,151 @@
+package evaluation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	schemeLabels := []string{"Positif", "Negatif", "Netral"}
+
+	tests := []struct {
+		name          string
+		expected      []string
+		predicted     []string
+		labels        []string
+		wantAccuracy  float64
+		wantMacroF1   float64
+		wantWeighted  float64
+		wantKappa     float64
+		wantLabels    []string
+		wantConfusion [][]int
+		wantPerClass  []ClassMetrics
+	}{
+		{
+			name:          "perfect predictions ignore unused labels",
+			expected:      []string{"Positif", "Negatif"},
+			predicted:     []string{"Positif", "Negatif"},
+			labels:        schemeLabels,
+			wantAccuracy:  1,
+			wantMacroF1:   1,
+			wantWeighted:  1,
+			wantKappa:     1,
+			wantLabels:    schemeLabels,
+			wantConfusion: [][]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 0}},
+			wantPerClass: []ClassMetrics{
+				{Label: "Positif", Precision: 1, Recall: 1, F1: 1, Support: 1},
+				{Label: "Negatif", Precision: 1, Recall: 1, F1: 1, Support: 1},
+				{Label: "Netral"},
+			},
+		},
+		{
+			// Positif: P=1/1 R=1/2 F1=2/3; Negatif: P=2/3 R=2/2 F1=4/5
+			// chance agreement = 2/4*1/4 + 2/4*3/4 = 1/2, kappa = (3/4-1/2)/(1/2)
+			name:          "mixed predictions",
+			expected:      []string{"Positif", "Positif", "Negatif", "Negatif"},
+			predicted:     []string{"Positif", "Negatif", "Negatif", "Negatif"},
+			labels:        schemeLabels,
+			wantAccuracy:  0.75,
+			wantMacroF1:   0.7333,
+			wantWeighted:  0.7333,
+			wantKappa:     0.5,
+			wantLabels:    schemeLabels,
+			wantConfusion: [][]int{{1, 1, 0}, {0, 2, 0}, {0, 0, 0}},
+			wantPerClass: []ClassMetrics{
+				{Label: "Positif", Precision: 1, Recall: 0.5, F1: 0.6667, Support: 2},
+				{Label: "Negatif", Precision: 0.6667, Recall: 1, F1: 0.8, Support: 2},
+				{Label: "Netral"},
+			},
+		},
+		{
+			// Netral was predicted but never expected, so its F1 of 0 counts
+			// towards macro F1; Negatif appears nowhere and does not
+			name:          "predicted only label counts in macro F1",
+			expected:      []string{"Positif", "Positif"},
+			predicted:     []string{"Positif", "Netral"},
+			labels:        schemeLabels,
+			wantAccuracy:  0.5,
+			wantMacroF1:   0.3333,
+			wantWeighted:  0.6667,
+			wantKappa:     0,
+			wantLabels:    schemeLabels,
+			wantConfusion: [][]int{{1, 0, 1}, {0, 0, 0}, {0, 0, 0}},
+			wantPerClass: []ClassMetrics{
+				{Label: "Positif", Precision: 1, Recall: 0.5, F1: 0.6667, Support: 2},
+				{Label: "Negatif"},
+				{Label: "Netral"},
+			},
+		},
+		{
+			name:          "labels seen only in the data are appended",
+			expected:      []string{"Campuran", "Positif"},
+			predicted:     []string{"Positif", "Positif"},
+			labels:        []string{"Positif"},
+			wantAccuracy:  0.5,
+			wantMacroF1:   0.3333,
+			wantWeighted:  0.3333,
+			wantKappa:     0,
+			wantLabels:    []string{"Positif", "Campuran"},
+			wantConfusion: [][]int{{1, 0}, {1, 0}},
+			wantPerClass: []ClassMetrics{
+				{Label: "Positif", Precision: 0.5, Recall: 1, F1: 0.6667, Support: 1},
+				{Label: "Campuran", Support: 1},
+			},
+		},
+		{
+			name:          "single class agreement",
+			expected:      []string{"Netral", "Netral"},
+			predicted:     []string{"Netral", "Netral"},
+			labels:        schemeLabels,
+			wantAccuracy:  1,
+			wantMacroF1:   1,
+			wantWeighted:  1,
+			wantKappa:     1,
+			wantLabels:    schemeLabels,
+			wantConfusion: [][]int{{0, 0, 0}, {0, 0, 0}, {0, 0, 2}},
+			wantPerClass: []ClassMetrics{
+				{Label: "Positif"},
+				{Label: "Negatif"},
+				{Label: "Netral", Precision: 1, Recall: 1, F1: 1, Support: 2},
+			},
+		},
+		{
+			name:          "no samples",
+			labels:        schemeLabels,
+			wantLabels:    schemeLabels,
+			wantConfusion: [][]int{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}},
+			wantPerClass:  []ClassMetrics{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.expected, tt.predicted, tt.labels)
+
+			if got.Samples != len(tt.expected) {
+				t.Errorf("Samples = %d, want %d", got.Samples, len(tt.expected))
+			}
+			if got.Accuracy != tt.wantAccuracy {
+				t.Errorf("Accuracy = %v, want %v", got.Accuracy, tt.wantAccuracy)
+			}
+			if got.MacroF1 != tt.wantMacroF1 {
+				t.Errorf("MacroF1 = %v, want %v", got.MacroF1, tt.wantMacroF1)
+			}
+			if got.WeightedF1 != tt.wantWeighted {
+				t.Errorf("WeightedF1 = %v, want %v", got.WeightedF1, tt.wantWeighted)
+			}
+			if got.CohensKappa != tt.wantKappa {
+				t.Errorf("CohensKappa = %v, want %v", got.CohensKappa, tt.wantKappa)
+			}
+			if !reflect.DeepEqual(got.Labels, tt.wantLabels) {
+				t.Errorf("Labels = %v, want %v", got.Labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(got.Confusion, tt.wantConfusion) {
+				t.Errorf("Confusion = %v, want %v", got.Confusion, tt.wantConfusion)
+			}
+			if !reflect.DeepEqual(got.PerClass, tt.wantPerClass) {
+				t.Errorf("PerClass = %+v, want %+v", got.PerClass, tt.wantPerClass)
+			}
+		})
+	}
+}
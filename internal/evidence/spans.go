@@ -0,0 +1,82 @@
+package evidence
+
+import (
+	"strings"
+	"unicode"
+
+	"sentiment-api/internal/model"
+)
+
+// Validate anchors candidate phrases in text and returns them with character
+// (rune) offsets. Matching ignores case; each phrase takes the first occurrence
+// not already claimed by an earlier phrase. Phrases that do not occur in text
+// are dropped, and contributions are clamped to [-1, 1].
+func Validate(text string, candidates []model.EvidenceSpan) []model.EvidenceSpan {
+	runes := []rune(text)
+	folded := fold(runes)
+	claimed := make(map[int]bool)
+
+	spans := make([]model.EvidenceSpan, 0, len(candidates))
+	for _, candidate := range candidates {
+		phrase := fold([]rune(strings.TrimSpace(candidate.Text)))
+		if len(phrase) == 0 {
+			continue
+		}
+
+		start := find(folded, phrase, claimed)
+		if start < 0 {
+			continue
+		}
+		end := start + len(phrase)
+		claimed[start] = true
+
+		spans = append(spans, model.EvidenceSpan{
+			Text:         string(runes[start:end]),
+			Start:        start,
+			End:          end,
+			Contribution: clamp(candidate.Contribution),
+		})
+	}
+
+	return spans
+}
+
+// find returns the first unclaimed rune offset of phrase in text, or -1
+func find(text, phrase []rune, claimed map[int]bool) int {
+	for i := 0; i+len(phrase) <= len(text); i++ {
+		if claimed[i] {
+			continue
+		}
+		match := true
+		for j := range phrase {
+			if text[i+j] != phrase[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// fold lowercases runes one by one so offsets stay aligned with the original
+func fold(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = unicode.ToLower(r)
+	}
+	return folded
+}
+
+// clamp bounds a polarity contribution to [-1, 1]
+func clamp(value float64) float64 {
+	switch {
+	case value < -1:
+		return -1
+	case value > 1:
+		return 1
+	}
+	return value
+}
@@ -0,0 +1,126 @@
+package evidence
+
+import (
+	"reflect"
+	"testing"
+
+	"sentiment-api/internal/model"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		candidates []model.EvidenceSpan
+		want       []model.EvidenceSpan
+	}{
+		{
+			name:       "exact phrase",
+			text:       "Layanan sangat memuaskan",
+			candidates: []model.EvidenceSpan{{Text: "sangat memuaskan", Contribution: 0.9}},
+			want:       []model.EvidenceSpan{{Text: "sangat memuaskan", Start: 8, End: 24, Contribution: 0.9}},
+		},
+		{
+			name:       "case is folded and the answer's casing is returned",
+			text:       "Pengiriman LAMBAT sekali",
+			candidates: []model.EvidenceSpan{{Text: " pengiriman lambat ", Contribution: -0.8}},
+			want:       []model.EvidenceSpan{{Text: "Pengiriman LAMBAT", Start: 0, End: 17, Contribution: -0.8}},
+		},
+		{
+			name: "repeated phrases claim successive occurrences",
+			text: "bagus, Bagus, bagus",
+			candidates: []model.EvidenceSpan{
+				{Text: "bagus", Contribution: 0.5},
+				{Text: "BAGUS", Contribution: 0.6},
+				{Text: "bagus", Contribution: 0.7},
+				{Text: "bagus", Contribution: 0.8},
+			},
+			want: []model.EvidenceSpan{
+				{Text: "bagus", Start: 0, End: 5, Contribution: 0.5},
+				{Text: "Bagus", Start: 7, End: 12, Contribution: 0.6},
+				{Text: "bagus", Start: 14, End: 19, Contribution: 0.7},
+			},
+		},
+		{
+			name: "longer phrase may overlap a claimed start",
+			text: "murah dan murah sekali",
+			candidates: []model.EvidenceSpan{
+				{Text: "murah", Contribution: 0.4},
+				{Text: "murah sekali", Contribution: 0.9},
+			},
+			want: []model.EvidenceSpan{
+				{Text: "murah", Start: 0, End: 5, Contribution: 0.4},
+				{Text: "murah sekali", Start: 10, End: 22, Contribution: 0.9},
+			},
+		},
+		{
+			name: "hallucinated and empty phrases are dropped",
+			text: "Harga terjangkau",
+			candidates: []model.EvidenceSpan{
+				{Text: "kualitas buruk", Contribution: -0.7},
+				{Text: "   ", Contribution: 0.1},
+				{Text: "terjangkau", Contribution: 0.6},
+				{Text: "harga terjangkau sekali", Contribution: 0.8},
+			},
+			want: []model.EvidenceSpan{{Text: "terjangkau", Start: 6, End: 16, Contribution: 0.6}},
+		},
+		{
+			name:       "offsets count runes, not bytes",
+			text:       "Pelayanan café ini ramah",
+			candidates: []model.EvidenceSpan{{Text: "ramah", Contribution: 0.7}},
+			want:       []model.EvidenceSpan{{Text: "ramah", Start: 19, End: 24, Contribution: 0.7}},
+		},
+		{
+			// 😡 is one rune but two UTF-16 units
+			name:       "emoji count as one character",
+			text:       "😡 kecewa berat",
+			candidates: []model.EvidenceSpan{{Text: "Kecewa berat", Contribution: -0.9}},
+			want:       []model.EvidenceSpan{{Text: "kecewa berat", Start: 2, End: 14, Contribution: -0.9}},
+		},
+		{
+			name:       "non-ASCII letters are case folded",
+			text:       "ÜBERRASCHEND gut",
+			candidates: []model.EvidenceSpan{{Text: "überraschend", Contribution: 0.5}},
+			want:       []model.EvidenceSpan{{Text: "ÜBERRASCHEND", Start: 0, End: 12, Contribution: 0.5}},
+		},
+		{
+			name: "contributions are clamped",
+			text: "sangat bagus tapi sangat mahal",
+			candidates: []model.EvidenceSpan{
+				{Text: "sangat bagus", Contribution: 3},
+				{Text: "sangat mahal", Contribution: -1.5},
+			},
+			want: []model.EvidenceSpan{
+				{Text: "sangat bagus", Start: 0, End: 12, Contribution: 1},
+				{Text: "sangat mahal", Start: 18, End: 30, Contribution: -1},
+			},
+		},
+		{
+			name:       "phrase longer than the answer",
+			text:       "oke",
+			candidates: []model.EvidenceSpan{{Text: "oke sekali", Contribution: 0.3}},
+			want:       []model.EvidenceSpan{},
+		},
+		{
+			name: "no candidates",
+			text: "Biasa saja",
+			want: []model.EvidenceSpan{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Validate(tt.text, tt.candidates)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+
+			runes := []rune(tt.text)
+			for _, span := range got {
+				if string(runes[span.Start:span.End]) != span.Text {
+					t.Errorf("span %+v does not match the answer at its offsets", span)
+				}
+			}
+		})
+	}
+}
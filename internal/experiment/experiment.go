@@ -0,0 +1,159 @@
+package experiment
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/engine"
+	"sentiment-api/internal/lexicon"
+)
+
+// Units requests can be assigned to variants by
+const (
+	AssignByAPIKey       = "api_key"
+	AssignByRespondentID = "respondent_id"
+)
+
+// Variant is one arm of an experiment
+type Variant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+	// Engine is an engine name as accepted by engine.Parse
+	Engine string `json:"engine"`
+	// Prompt is engine.PromptStandard or engine.PromptNuance
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// Experiment splits traffic between variants. Only active experiments
+// assign requests; results of finished ones stay reportable.
+type Experiment struct {
+	Name     string    `json:"name"`
+	Active   bool      `json:"active"`
+	AssignBy string    `json:"assign_by"`
+	Variants []Variant `json:"variants"`
+
+	engines     []engine.Engine
+	totalWeight int
+}
+
+// Assignment is the variant a request was assigned to
+type Assignment struct {
+	Experiment string
+	Variant    string
+	Engine     engine.Engine
+}
+
+// Registry holds the configured experiments
+type Registry struct {
+	experiments []*Experiment
+}
+
+// NewRegistry loads experiments from a JSON array. Without a file no request
+// is assigned to an experiment.
+func NewRegistry(path string, llmClient *client.LLMClient, analyzer *lexicon.Analyzer) (*Registry, error) {
+	registry := &Registry{}
+	if path == "" {
+		return registry, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiments file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &registry.experiments); err != nil {
+		return nil, fmt.Errorf("failed to parse experiments file: %w", err)
+	}
+
+	seen := make(map[string]bool, len(registry.experiments))
+	for _, exp := range registry.experiments {
+		if exp.Name == "" {
+			return nil, fmt.Errorf("experiment has no name")
+		}
+		if seen[exp.Name] {
+			return nil, fmt.Errorf("duplicate experiment %q", exp.Name)
+		}
+		seen[exp.Name] = true
+
+		if err := exp.compile(llmClient, analyzer); err != nil {
+			return nil, fmt.Errorf("experiment %q: %w", exp.Name, err)
+		}
+	}
+
+	return registry, nil
+}
+
+// compile validates the experiment and builds the engine of every variant
+func (e *Experiment) compile(llmClient *client.LLMClient, analyzer *lexicon.Analyzer) error {
+	switch e.AssignBy {
+	case "":
+		e.AssignBy = AssignByAPIKey
+	case AssignByAPIKey, AssignByRespondentID:
+	default:
+		return fmt.Errorf("unknown assign_by %q", e.AssignBy)
+	}
+
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("an experiment needs at least two variants")
+	}
+
+	names := make(map[string]bool, len(e.Variants))
+	for _, variant := range e.Variants {
+		if variant.Name == "" || names[variant.Name] {
+			return fmt.Errorf("variant names must be unique and not empty")
+		}
+		names[variant.Name] = true
+
+		if variant.Weight <= 0 {
+			return fmt.Errorf("variant %q needs a positive weight", variant.Name)
+		}
+
+		parsed, err := engine.Parse(variant.Engine, llmClient, analyzer)
+		if err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+		variantEngine, err := engine.WithPrompt(parsed, variant.Prompt)
+		if err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+
+		e.engines = append(e.engines, variantEngine)
+		e.totalWeight += variant.Weight
+	}
+
+	return nil
+}
+
+// Active returns the first active experiment, if any
+func (r *Registry) Active() (*Experiment, bool) {
+	for _, exp := range r.experiments {
+		if exp.Active {
+			return exp, true
+		}
+	}
+	return nil, false
+}
+
+// Assign picks the variant of a unit by a stable hash of the experiment name
+// and the unit, so the same API key or respondent always gets the same variant.
+// SHA-256 spreads every input bit over the point, so a unit's variants in
+// different experiments are independent.
+func (e *Experiment) Assign(unit string) Assignment {
+	sum := sha256.Sum256([]byte(e.Name + ":" + unit))
+	point := int(binary.BigEndian.Uint64(sum[:8]) % uint64(e.totalWeight))
+
+	for i, variant := range e.Variants {
+		if point < variant.Weight {
+			return Assignment{Experiment: e.Name, Variant: variant.Name, Engine: e.engines[i]}
+		}
+		point -= variant.Weight
+	}
+
+	// Unreachable while the weights sum to totalWeight
+	last := len(e.Variants) - 1
+	return Assignment{Experiment: e.Name, Variant: e.Variants[last].Name, Engine: e.engines[last]}
+}
@@ -0,0 +1,178 @@
+package experiment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/lexicon"
+)
+
+// newTestRegistry loads experiments from content written to a temporary file
+func newTestRegistry(t *testing.T, content string) (*Registry, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "experiments.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write experiments file: %v", err)
+	}
+	return NewRegistry(path, &client.LLMClient{}, lexicon.NewAnalyzer())
+}
+
+func TestNewRegistry(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantActive   string
+		wantAssignBy string
+		wantErr      bool
+	}{
+		{
+			name: "first active experiment",
+			content: `[
+				{"name": "finished", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]},
+				{"name": "prompt", "active": true, "assign_by": "respondent_id", "variants": [
+					{"name": "control", "weight": 1, "engine": "llm"},
+					{"name": "nuance", "weight": 1, "engine": "llm", "prompt": "nuance"}
+				]},
+				{"name": "later", "active": true, "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}
+			]`,
+			wantActive:   "prompt",
+			wantAssignBy: AssignByRespondentID,
+		},
+		{
+			name:         "assigned by API key by default",
+			content:      `[{"name": "engine", "active": true, "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 3, "engine": "llm:other"}]}]`,
+			wantActive:   "engine",
+			wantAssignBy: AssignByAPIKey,
+		},
+		{
+			name:    "no active experiment",
+			content: `[{"name": "finished", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}]`,
+		},
+		{name: "invalid json", content: `{"name": "x"}`, wantErr: true},
+		{name: "missing name", content: `[{"variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}]`, wantErr: true},
+		{
+			name: "duplicate experiment",
+			content: `[
+				{"name": "x", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]},
+				{"name": "x", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}
+			]`,
+			wantErr: true,
+		},
+		{name: "unknown assign_by", content: `[{"name": "x", "assign_by": "ip", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}]`, wantErr: true},
+		{name: "single variant", content: `[{"name": "x", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}]}]`, wantErr: true},
+		{name: "duplicate variant", content: `[{"name": "x", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "a", "weight": 1, "engine": "llm"}]}]`, wantErr: true},
+		{name: "zero weight", content: `[{"name": "x", "variants": [{"name": "a", "weight": 0, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}]`, wantErr: true},
+		{name: "unknown engine", content: `[{"name": "x", "variants": [{"name": "a", "weight": 1, "engine": "bert"}, {"name": "b", "weight": 1, "engine": "llm"}]}]`, wantErr: true},
+		{name: "nuance prompt on the lexicon", content: `[{"name": "x", "variants": [{"name": "a", "weight": 1, "engine": "lexicon", "prompt": "nuance"}, {"name": "b", "weight": 1, "engine": "llm"}]}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := newTestRegistry(t, tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			active, ok := registry.Active()
+			if tt.wantActive == "" {
+				if ok {
+					t.Errorf("Active() = %q, want none", active.Name)
+				}
+				return
+			}
+			if !ok || active.Name != tt.wantActive {
+				t.Fatalf("Active() = %v, %v, want %q", active, ok, tt.wantActive)
+			}
+			if active.AssignBy != tt.wantAssignBy {
+				t.Errorf("AssignBy = %q, want %q", active.AssignBy, tt.wantAssignBy)
+			}
+		})
+	}
+}
+
+func TestNewRegistryWithoutFile(t *testing.T) {
+	registry, err := NewRegistry("", nil, nil)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	if _, ok := registry.Active(); ok {
+		t.Error("Active() found an experiment without a file")
+	}
+}
+
+func TestAssign(t *testing.T) {
+	registry, err := newTestRegistry(t, `[{"name": "engine", "active": true, "variants": [
+		{"name": "lexicon", "weight": 1, "engine": "lexicon"},
+		{"name": "llm", "weight": 3, "engine": "llm"},
+		{"name": "nuance", "weight": 1, "engine": "llm", "prompt": "nuance"}
+	]}]`)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	active, _ := registry.Active()
+
+	wantEngines := map[string]string{
+		"lexicon": "lexicon",
+		"llm":     "llm:" + client.DefaultModel,
+		"nuance":  "llm:" + client.DefaultModel + "/nuance",
+	}
+
+	const units = 10000
+	counts := make(map[string]int)
+	for i := 0; i < units; i++ {
+		unit := fmt.Sprintf("respondent-%d", i)
+		assignment := active.Assign(unit)
+
+		if assignment.Experiment != "engine" {
+			t.Fatalf("Experiment = %q, want %q", assignment.Experiment, "engine")
+		}
+		if got := assignment.Engine.Name(); got != wantEngines[assignment.Variant] {
+			t.Fatalf("variant %q has engine %q, want %q", assignment.Variant, got, wantEngines[assignment.Variant])
+		}
+		if again := active.Assign(unit); again.Variant != assignment.Variant {
+			t.Fatalf("Assign(%q) = %q then %q, want a stable variant", unit, assignment.Variant, again.Variant)
+		}
+		counts[assignment.Variant]++
+	}
+
+	// Shares follow the 1:3:1 weights within two percentage points
+	wantShares := map[string]float64{"lexicon": 0.2, "llm": 0.6, "nuance": 0.2}
+	for variant, want := range wantShares {
+		share := float64(counts[variant]) / units
+		if share < want-0.02 || share > want+0.02 {
+			t.Errorf("variant %q got %.3f of the units, want about %.2f", variant, share, want)
+		}
+	}
+}
+
+func TestAssignDependsOnExperiment(t *testing.T) {
+	registry, err := newTestRegistry(t, `[
+		{"name": "first", "active": true, "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]},
+		{"name": "second", "variants": [{"name": "a", "weight": 1, "engine": "lexicon"}, {"name": "b", "weight": 1, "engine": "llm"}]}
+	]`)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	// The experiment name is hashed with the unit, so a unit's variant in one
+	// experiment does not decide its variant in the next one: about half of
+	// the units of two even splits land in different variants
+	const units = 1000
+	differs := 0
+	for i := 0; i < units; i++ {
+		unit := fmt.Sprintf("team-%d", i)
+		if registry.experiments[0].Assign(unit).Variant != registry.experiments[1].Assign(unit).Variant {
+			differs++
+		}
+	}
+	if share := float64(differs) / units; share < 0.45 || share > 0.55 {
+		t.Errorf("%.3f of the units changed variant between experiments, want about 0.5", share)
+	}
+}
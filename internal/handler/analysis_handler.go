@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/service"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AnalysisHandler handles requests for stored analyses
+type AnalysisHandler struct {
+	analysisService *service.AnalysisService
+}
+
+// NewAnalysisHandler creates a new analysis handler
+func NewAnalysisHandler(analysisService *service.AnalysisService) *AnalysisHandler {
+	return &AnalysisHandler{
+		analysisService: analysisService,
+	}
+}
+
+// ListAnalyses godoc
+//
+//	@Summary		List stored analyses
+//	@Description	Search stored analysis results, newest first, with cursor pagination. Non-admin callers only see their own results.
+//	@Tags			analyses
+//	@Produce		json
+//	@Param			label			query		string	false	"Sentiment label"
+//	@Param			from			query		string	false	"Start date (YYYY-MM-DD or RFC3339)"
+//	@Param			to				query		string	false	"End date, inclusive (YYYY-MM-DD or RFC3339)"
+//	@Param			survey_id		query		string	false	"Survey ID"
+//	@Param			question_id		query		string	false	"Question ID"
+//	@Param			model			query		string	false	"Model that produced the result"
+//	@Param			experiment		query		string	false	"A/B experiment the result belongs to"
+//	@Param			min_confidence	query		number	false	"Minimum confidence"
+//	@Param			max_confidence	query		number	false	"Maximum confidence"
+//	@Param			q				query		string	false	"Words that must appear in the answer (requires STORAGE_STORE_TEXT=true)"
+//	@Param			cursor			query		string	false	"Cursor returned by the previous page"
+//	@Param			limit			query		int		false	"Page size (default 50, max 500)"
+//	@Success		200				{object}	model.APIResponse{data=storage.AnalysisPage}	"Page of analyses"
+//	@Failure		400				{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid filter"
+//	@Failure		503				{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled"
+//	@Router			/api/v1/analyses [get]
+func (h *AnalysisHandler) ListAnalyses(c *gin.Context) {
+	filter, err := parseAnalysisFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	page, err := h.analysisService.ListAnalyses(c.Request.Context(), filter)
+	if err != nil {
+		respondStorageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    page,
+	})
+}
+
+// ExportAnalyses godoc
+//
+//	@Summary		Export stored analyses
+//	@Description	Export every stored analysis matching the filters as CSV or JSON. Accepts the same filters as the list endpoint.
+//	@Tags			analyses
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			format	query	string	false	"Export format: csv or json (default csv)"
+//	@Success		200		{file}	file	"Exported analyses"
+//	@Failure		400		{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid filter"
+//	@Failure		503		{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled"
+//	@Router			/api/v1/analyses/export [get]
+func (h *AnalysisHandler) ExportAnalyses(c *gin.Context) {
+	filter, err := parseAnalysisFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+	filter.Cursor = ""
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		respondError(c, http.StatusBadRequest, "Invalid request", "format must be csv or json")
+		return
+	}
+
+	filename := "analyses-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	var emit func(storage.AnalysisRecord) error
+	var finish func() error
+
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(c.Writer)
+		headerWritten := false
+
+		emit = func(record storage.AnalysisRecord) error {
+			if !headerWritten {
+				headerWritten = true
+				if err := writer.Write(csvHeader); err != nil {
+					return err
+				}
+			}
+			return writer.Write(csvRow(record))
+		}
+		finish = func() error {
+			if !headerWritten {
+				if err := writer.Write(csvHeader); err != nil {
+					return err
+				}
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		encoder := json.NewEncoder(c.Writer)
+		count := 0
+
+		emit = func(record storage.AnalysisRecord) error {
+			prefix := ","
+			if count == 0 {
+				prefix = "["
+			}
+			count++
+			if _, err := c.Writer.WriteString(prefix); err != nil {
+				return err
+			}
+			return encoder.Encode(record)
+		}
+		finish = func() error {
+			closing := "]"
+			if count == 0 {
+				closing = "[]"
+			}
+			_, err := c.Writer.WriteString(closing)
+			return err
+		}
+	}
+
+	if err := h.analysisService.ExportAnalyses(c.Request.Context(), filter, emit); err != nil {
+		if !c.Writer.Written() {
+			respondStorageError(c, err)
+			return
+		}
+		logger.LogError("Analysis export aborted", logrus.Fields{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := finish(); err != nil {
+		logger.LogError("Failed to finish analysis export", logrus.Fields{
+			"error": err.Error(),
+		})
+	}
+}
+
+// GetSummary godoc
+//
+//	@Summary		Summarize stored analyses
+//	@Description	Group stored analyses by survey and question and return counts, percentages, net sentiment score and a trend over time buckets. Pass compare_from and compare_to to compare against a baseline period.
+//	@Tags			analyses
+//	@Produce		json
+//	@Param			survey_id		query		string	false	"Survey ID"
+//	@Param			question_id		query		string	false	"Question ID"
+//	@Param			from			query		string	false	"Start date (YYYY-MM-DD or RFC3339)"
+//	@Param			to				query		string	false	"End date, inclusive (YYYY-MM-DD or RFC3339)"
+//	@Param			bucket			query		string	false	"Trend bucket: day, week or month (default day)"
+//	@Param			compare_from	query		string	false	"Baseline period start"
+//	@Param			compare_to		query		string	false	"Baseline period end, inclusive"
+//	@Success		200				{object}	model.APIResponse{data=model.SummaryReport}	"Summary report"
+//	@Failure		400				{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid filter"
+//	@Failure		503				{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled"
+//	@Router			/api/v1/analyses/summary [get]
+func (h *AnalysisHandler) GetSummary(c *gin.Context) {
+	filter, err := parseAnalysisFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+	filter.Cursor = ""
+
+	bucket := c.DefaultQuery("bucket", "day")
+	if bucket != "day" && bucket != "week" && bucket != "month" {
+		respondError(c, http.StatusBadRequest, "Invalid request", "bucket must be day, week or month")
+		return
+	}
+
+	current := service.SummaryPeriod{
+		Filter: filter,
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
+
+	var baseline *service.SummaryPeriod
+	compareFrom, compareTo := c.Query("compare_from"), c.Query("compare_to")
+	if compareFrom != "" || compareTo != "" {
+		if compareFrom == "" || compareTo == "" {
+			respondError(c, http.StatusBadRequest, "Invalid request", "compare_from and compare_to must be provided together")
+			return
+		}
+
+		baselineFilter := filter
+		if baselineFilter.From, err = parseDate(compareFrom, false); err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid request", "compare_from must be YYYY-MM-DD or RFC3339")
+			return
+		}
+		if baselineFilter.To, err = parseDate(compareTo, true); err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid request", "compare_to must be YYYY-MM-DD or RFC3339")
+			return
+		}
+
+		baseline = &service.SummaryPeriod{
+			Filter: baselineFilter,
+			From:   compareFrom,
+			To:     compareTo,
+		}
+	}
+
+	report, err := h.analysisService.Summarize(c.Request.Context(), current, baseline, bucket)
+	if err != nil {
+		respondStorageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// SubmitFeedback godoc
+//
+//	@Summary		Submit feedback on an analysis
+//	@Description	Record the correct label of a stored analysis, e.g. after human review. Feedback drives the accuracy reported per experiment variant. The label must belong to the label scheme of the analysis (names and synonyms are accepted, ignoring case). Non-admin callers can only label their own analyses.
+//	@Tags			analyses
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"Analysis ID from the response metadata"
+//	@Param			request	body		model.FeedbackRequest	true	"Correct label"
+//	@Success		200		{object}	model.APIResponse		"Feedback stored"
+//	@Failure		400		{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid request or label outside the scheme"
+//	@Failure		404		{object}	model.APIResponse{error=model.ErrorResponse}	"Analysis not found"
+//	@Failure		503		{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled"
+//	@Router			/api/v1/analyses/{id}/feedback [post]
+func (h *AnalysisHandler) SubmitFeedback(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondError(c, http.StatusBadRequest, "Invalid request", "id must be a positive integer")
+		return
+	}
+
+	var req model.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	clientID := ""
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok && !principal.HasScope(auth.ScopeAdmin) {
+		clientID = principal.ID
+	}
+
+	if err := h.analysisService.SaveFeedback(c.Request.Context(), id, clientID, req.Sentiment); err != nil {
+		respondStorageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+	})
+}
+
+// GetExperimentSummary godoc
+//
+//	@Summary		Summarize an A/B experiment
+//	@Description	Report label distribution, average latency, parse-failure rate and feedback accuracy per variant of an experiment from its stored results. Admin only.
+//	@Tags			analyses
+//	@Produce		json
+//	@Param			name	path		string	true	"Experiment name"
+//	@Success		200		{object}	model.APIResponse{data=model.ExperimentSummary}	"Per-variant summary"
+//	@Failure		403		{object}	model.APIResponse{error=model.ErrorResponse}	"Admin scope required"
+//	@Failure		503		{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled"
+//	@Router			/api/v1/experiments/{name}/summary [get]
+func (h *AnalysisHandler) GetExperimentSummary(c *gin.Context) {
+	summary, err := h.analysisService.ExperimentSummary(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondStorageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// csvHeader lists the exported CSV columns
+var csvHeader = []string{
+	"id", "client_id", "survey_id", "question_id", "respondent_id", "text_hash", "text_pertanyaan",
+	"text_jawaban", "sentiment", "reasoning", "confidence", "model", "prompt_version", "latency_ms", "created_at",
+	"experiment", "variant", "parse_failed", "feedback_label", "label_scheme",
+}
+
+// csvRow converts a record to CSV columns matching csvHeader
+func csvRow(record storage.AnalysisRecord) []string {
+	confidence := ""
+	if record.Confidence != nil {
+		confidence = strconv.FormatFloat(*record.Confidence, 'f', 4, 64)
+	}
+
+	return []string{
+		strconv.FormatInt(record.ID, 10), record.ClientID, record.SurveyID, record.QuestionID, record.RespondentID,
+		record.TextHash, derefString(record.TextPertanyaan), derefString(record.TextJawaban), record.Sentiment,
+		derefString(record.Reasoning), confidence, record.Model, record.PromptVersion,
+		strconv.FormatInt(record.LatencyMs, 10), record.CreatedAt.UTC().Format(time.RFC3339),
+		record.Experiment, record.Variant, strconv.FormatBool(record.ParseFailed), derefString(record.FeedbackLabel),
+		record.LabelScheme,
+	}
+}
+
+// parseAnalysisFilter reads analysis filters from the query string.
+// Non-admin callers are restricted to their own results.
+func parseAnalysisFilter(c *gin.Context) (storage.AnalysisFilter, error) {
+	filter := storage.AnalysisFilter{
+		Sentiment:  c.Query("label"),
+		SurveyID:   c.Query("survey_id"),
+		QuestionID: c.Query("question_id"),
+		Model:      c.Query("model"),
+		Experiment: c.Query("experiment"),
+		Search:     c.Query("q"),
+		Cursor:     c.Query("cursor"),
+	}
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if filter.From, err = parseDate(value, false); err != nil {
+			return filter, errors.New("from must be YYYY-MM-DD or RFC3339")
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if filter.To, err = parseDate(value, true); err != nil {
+			return filter, errors.New("to must be YYYY-MM-DD or RFC3339")
+		}
+	}
+	if value := c.Query("min_confidence"); value != "" {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return filter, errors.New("min_confidence must be a number")
+		}
+		filter.MinConfidence = &confidence
+	}
+	if value := c.Query("max_confidence"); value != "" {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return filter, errors.New("max_confidence must be a number")
+		}
+		filter.MaxConfidence = &confidence
+	}
+	if value := c.Query("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit <= 0 {
+			return filter, errors.New("limit must be a positive integer")
+		}
+	}
+
+	filter.ClientID = c.Query("client_id")
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok && !principal.HasScope(auth.ScopeAdmin) {
+		filter.ClientID = principal.ID
+	}
+
+	return filter, nil
+}
+
+// parseDate parses YYYY-MM-DD or RFC3339 values. Date-only end bounds are
+// moved to the start of the next day so the whole day is included.
+func parseDate(value string, endOfRange bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfRange {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
+}
+
+// respondStorageError maps storage errors to HTTP responses
+func respondStorageError(c *gin.Context, err error) {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case errors.Is(err, storage.ErrDisabled):
+		respondError(c, http.StatusServiceUnavailable, "Storage disabled", err.Error())
+	case errors.Is(err, storage.ErrInvalidCursor):
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case errors.Is(err, storage.ErrNotFound):
+		respondError(c, http.StatusNotFound, "Not found", err.Error())
+	default:
+		logger.LogError("Storage query failed", logrus.Fields{
+			"error": err.Error(),
+		})
+		respondError(c, http.StatusInternalServerError, "Internal server error", "failed to read stored analyses")
+	}
+}
+
+// derefString returns the pointed-to string or an empty string
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
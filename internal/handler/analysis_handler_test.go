@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/service"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newAnalysisRouter serves the analyses endpoints over a temporary SQLite
+// database holding records. Requests with an X-Client header are made by a
+// non-admin principal with that ID.
+func newAnalysisRouter(t *testing.T, storeText bool, records []storage.AnalysisRecord) *gin.Engine {
+	t.Helper()
+
+	repository, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "analyses.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	t.Cleanup(func() { repository.Close() })
+
+	for i := range records {
+		records[i].TextHash = storage.HashText("", derefString(records[i].TextJawaban))
+		records[i].Model = "gpt-4o-mini"
+		records[i].PromptVersion = "v1"
+		if err := repository.Save(context.Background(), &records[i]); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	handler := NewAnalysisHandler(service.NewAnalysisService(repository, storeText, nil))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if clientID := c.GetHeader("X-Client"); clientID != "" {
+			principal := &auth.Principal{ID: clientID, Scopes: []string{auth.ScopeAnalyze}}
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	})
+	router.GET("/analyses", handler.ListAnalyses)
+	router.GET("/analyses/export", handler.ExportAnalyses)
+	return router
+}
+
+// analysisRecords returns stored answers shared by the handler tests
+func analysisRecords() []storage.AnalysisRecord {
+	day := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	text := func(value string) *string { return &value }
+	confidence := func(value float64) *float64 { return &value }
+
+	return []storage.AnalysisRecord{
+		{ClientID: "team", SurveyID: "s1", Sentiment: "Positif", Confidence: confidence(0.9), TextJawaban: text("Pengiriman cepat sekali"), CreatedAt: day.AddDate(0, 0, -2)},
+		{ClientID: "team", SurveyID: "s1", Sentiment: "Negatif", Confidence: confidence(0.7), TextJawaban: text("pengiriman lambat, kecewa"), CreatedAt: day.AddDate(0, 0, -1)},
+		{ClientID: "boss", SurveyID: "s2", Sentiment: "Positif", Confidence: confidence(0.8), TextJawaban: text(`harga "murah" sekali`), CreatedAt: day},
+		{ClientID: "team", SurveyID: "s2", Sentiment: "Netral", Confidence: confidence(0.5), TextJawaban: text("NOT bad at all"), CreatedAt: day},
+	}
+}
+
+// serve performs a GET request and returns the recorded response
+func serve(router *gin.Engine, path, clientID string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if clientID != "" {
+		request.Header.Set("X-Client", clientID)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// pageResponse decodes the body of a successful list response
+type pageResponse struct {
+	Success bool                 `json:"success"`
+	Data    storage.AnalysisPage `json:"data"`
+}
+
+func TestListAnalyses(t *testing.T) {
+	router := newAnalysisRouter(t, true, analysisRecords())
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		clientID   string
+		wantStatus int
+		wantIDs    []int64
+	}{
+		{name: "no filter", wantStatus: http.StatusOK, wantIDs: []int64{4, 3, 2, 1}},
+		{name: "label and survey", query: url.Values{"label": {"Positif"}, "survey_id": {"s1"}}, wantStatus: http.StatusOK, wantIDs: []int64{1}},
+		{name: "date range with inclusive end day", query: url.Values{"from": {"2026-10-17"}, "to": {"2026-10-17"}}, wantStatus: http.StatusOK, wantIDs: []int64{2}},
+		{name: "confidence range", query: url.Values{"min_confidence": {"0.6"}, "max_confidence": {"0.8"}}, wantStatus: http.StatusOK, wantIDs: []int64{3, 2}},
+		{name: "search with filters", query: url.Values{"q": {"pengiriman"}, "label": {"Negatif"}, "from": {"2026-10-16"}}, wantStatus: http.StatusOK, wantIDs: []int64{2}},
+		{name: "quoted search word", query: url.Values{"q": {`"murah"`}}, wantStatus: http.StatusOK, wantIDs: []int64{3}},
+		{name: "search operator matched literally", query: url.Values{"q": {"NOT"}}, wantStatus: http.StatusOK, wantIDs: []int64{4}},
+		{name: "empty result", query: url.Values{"q": {"pengiriman"}, "survey_id": {"s2"}}, wantStatus: http.StatusOK, wantIDs: []int64{}},
+		{name: "admin filters by client", query: url.Values{"client_id": {"boss"}}, wantStatus: http.StatusOK, wantIDs: []int64{3}},
+		{name: "caller only sees own results", query: url.Values{"client_id": {"boss"}}, clientID: "team", wantStatus: http.StatusOK, wantIDs: []int64{4, 2, 1}},
+		{name: "invalid date", query: url.Values{"from": {"18-10-2026"}}, wantStatus: http.StatusBadRequest},
+		{name: "invalid confidence", query: url.Values{"min_confidence": {"high"}}, wantStatus: http.StatusBadRequest},
+		{name: "invalid limit", query: url.Values{"limit": {"0"}}, wantStatus: http.StatusBadRequest},
+		{name: "invalid cursor", query: url.Values{"cursor": {"!!!"}}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(router, "/analyses?"+tt.query.Encode(), tt.clientID)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response pageResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got := pageIDs(response.Data); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("IDs = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestListAnalysesCursor(t *testing.T) {
+	router := newAnalysisRouter(t, true, analysisRecords())
+
+	query := url.Values{"q": {"sekali"}, "limit": {"1"}}
+	var pages [][]int64
+	for len(pages) < 3 {
+		recorder := serve(router, "/analyses?"+query.Encode(), "")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+		}
+
+		var response pageResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		pages = append(pages, pageIDs(response.Data))
+		if response.Data.NextCursor == "" {
+			break
+		}
+		query.Set("cursor", response.Data.NextCursor)
+	}
+
+	if want := [][]int64{{3}, {1}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestListAnalysesSearchWithoutText(t *testing.T) {
+	router := newAnalysisRouter(t, false, nil)
+
+	if recorder := serve(router, "/analyses?q=cepat", ""); recorder.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d when answers are not stored", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportAnalyses(t *testing.T) {
+	router := newAnalysisRouter(t, true, analysisRecords())
+
+	tests := []struct {
+		name            string
+		query           url.Values
+		clientID        string
+		wantStatus      int
+		wantContentType string
+		wantIDs         []string
+	}{
+		{name: "csv by default", wantStatus: http.StatusOK, wantContentType: "text/csv", wantIDs: []string{"4", "3", "2", "1"}},
+		{name: "csv with search and filters", query: url.Values{"q": {"sekali"}, "label": {"Positif"}, "survey_id": {"s2"}}, wantStatus: http.StatusOK, wantContentType: "text/csv", wantIDs: []string{"3"}},
+		{name: "csv of own results", query: url.Values{"format": {"csv"}}, clientID: "boss", wantStatus: http.StatusOK, wantContentType: "text/csv", wantIDs: []string{"3"}},
+		{name: "empty csv keeps the header", query: url.Values{"q": {"kosong"}}, wantStatus: http.StatusOK, wantContentType: "text/csv", wantIDs: []string{}},
+		{name: "json", query: url.Values{"format": {"json"}, "survey_id": {"s1"}}, wantStatus: http.StatusOK, wantContentType: "application/json", wantIDs: []string{"2", "1"}},
+		{name: "empty json array", query: url.Values{"format": {"json"}, "label": {"Campuran"}}, wantStatus: http.StatusOK, wantContentType: "application/json", wantIDs: []string{}},
+		{name: "cursor is ignored", query: url.Values{"format": {"json"}, "cursor": {"!!!"}, "client_id": {"boss"}}, wantStatus: http.StatusOK, wantContentType: "application/json", wantIDs: []string{"3"}},
+		{name: "unknown format", query: url.Values{"format": {"xml"}}, wantStatus: http.StatusBadRequest},
+		{name: "invalid filter", query: url.Values{"to": {"yesterday"}}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(router, "/analyses/export?"+tt.query.Encode(), tt.clientID)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantContentType) {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := recorder.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="analyses-`) {
+				t.Errorf("Content-Disposition = %q, want an attachment", got)
+			}
+
+			var ids []string
+			if tt.wantContentType == "text/csv" {
+				ids = csvIDs(t, recorder.Body.String())
+			} else {
+				ids = jsonIDs(t, recorder.Body.Bytes())
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("exported IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestExportAnalysesCSVColumns(t *testing.T) {
+	router := newAnalysisRouter(t, true, analysisRecords())
+
+	recorder := serve(router, "/analyses/export?q=murah", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(recorder.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want a header and one record", len(rows))
+	}
+
+	got := make(map[string]string, len(csvHeader))
+	for i, column := range rows[0] {
+		got[column] = rows[1][i]
+	}
+	want := map[string]string{
+		"client_id":    "boss",
+		"text_jawaban": `harga "murah" sekali`,
+		"sentiment":    "Positif",
+		"confidence":   "0.8000",
+		"created_at":   "2026-10-18T09:00:00Z",
+		"parse_failed": "false",
+	}
+	for column, value := range want {
+		if got[column] != value {
+			t.Errorf("%s = %q, want %q", column, got[column], value)
+		}
+	}
+}
+
+// pageIDs returns the IDs of a page in order
+func pageIDs(page storage.AnalysisPage) []int64 {
+	ids := make([]int64, len(page.Items))
+	for i, record := range page.Items {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+// csvIDs checks the CSV header and returns the id column
+func csvIDs(t *testing.T, body string) []string {
+	t.Helper()
+
+	rows, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(rows) == 0 || !reflect.DeepEqual(rows[0], csvHeader) {
+		t.Fatalf("CSV header = %v, want %v", rows, csvHeader)
+	}
+
+	ids := []string{}
+	for _, row := range rows[1:] {
+		ids = append(ids, row[0])
+	}
+	return ids
+}
+
+// jsonIDs decodes a JSON export and returns the record IDs
+func jsonIDs(t *testing.T, body []byte) []string {
+	t.Helper()
+
+	var records []storage.AnalysisRecord
+	if err := json.Unmarshal(body, &records); err != nil {
+		t.Fatalf("failed to decode JSON export %q: %v", body, err)
+	}
+
+	ids := []string{}
+	for _, record := range records {
+		ids = append(ids, strconv.FormatInt(record.ID, 10))
+	}
+	return ids
+}
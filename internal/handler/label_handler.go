@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LabelHandler handles label scheme requests
+type LabelHandler struct {
+	sentimentService *service.SentimentService
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(sentimentService *service.SentimentService) *LabelHandler {
+	return &LabelHandler{
+		sentimentService: sentimentService,
+	}
+}
+
+// GetSentimentTypes godoc
+//
+//	@Summary		Get supported sentiment types
+//	@Description	Describe the labels of a label scheme, with descriptions and synonyms. Without the scheme parameter the caller's default scheme is described.
+//	@Tags			sentiment
+//	@Produce		json
+//	@Param			scheme	query		string	false	"Label scheme ID"
+//	@Success		200		{object}	model.APIResponse{data=model.SentimentTypes}	"Label scheme"
+//	@Failure		400		{object}	model.APIResponse{error=model.ErrorResponse}	"Unknown label scheme"
+//	@Router			/api/v1/sentiment/types [get]
+func (h *LabelHandler) GetSentimentTypes(c *gin.Context) {
+	types, err := h.sentimentService.GetSentimentTypes(c.Request.Context(), c.Query("scheme"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    types,
+	})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/provider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProviderHandler handles LLM provider health requests
+type ProviderHandler struct {
+	chain *provider.Chain
+}
+
+// NewProviderHandler creates a new provider handler
+func NewProviderHandler(chain *provider.Chain) *ProviderHandler {
+	return &ProviderHandler{
+		chain: chain,
+	}
+}
+
+// GetProviders godoc
+//
+//	@Summary		Get LLM provider health
+//	@Description	Report every provider of the failover chain in order with its health, served and failed call counts and average latency. Admin only.
+//	@Tags			providers
+//	@Produce		json
+//	@Success		200	{object}	model.APIResponse{data=[]provider.Stats}	"Provider health"
+//	@Failure		403	{object}	model.APIResponse{error=model.ErrorResponse}	"Admin scope required"
+//	@Router			/api/v1/providers [get]
+func (h *ProviderHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    h.chain.Stats(),
+	})
+}
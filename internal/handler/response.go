@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sentiment-api/internal/budget"
+	"sentiment-api/internal/limiter"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/provider"
+	"sentiment-api/internal/service"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// overloadRetryAfterSeconds is the Retry-After sent when the LLM queue rejects a call mid-request
+const overloadRetryAfterSeconds = 5
+
+// respondError writes the standard error envelope
+func respondError(c *gin.Context, statusCode int, errorTitle, message string) {
+	c.JSON(statusCode, model.APIResponse{
+		Success: false,
+		Error: model.ErrorResponse{
+			Error:   errorTitle,
+			Message: message,
+		},
+	})
+}
+
+// respondServiceError maps service errors to HTTP responses
+func respondServiceError(c *gin.Context, err error) {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case errors.Is(err, limiter.ErrOverloaded):
+		c.Header("Retry-After", strconv.Itoa(overloadRetryAfterSeconds))
+		respondError(c, http.StatusServiceUnavailable, "Service overloaded", err.Error())
+	case errors.Is(err, budget.ErrBudgetExceeded):
+		respondError(c, http.StatusTooManyRequests, "Budget exceeded", err.Error())
+	case errors.Is(err, storage.ErrDisabled):
+		respondError(c, http.StatusServiceUnavailable, "Storage disabled", err.Error())
+	case errors.Is(err, provider.ErrUnavailable):
+		c.Header("Retry-After", strconv.Itoa(overloadRetryAfterSeconds))
+		respondError(c, http.StatusServiceUnavailable, "LLM unavailable", err.Error())
+	default:
+		logger.LogError("Request processing failed", logrus.Fields{
+			"path":  c.Request.URL.Path,
+			"error": err.Error(),
+		})
+		respondError(c, http.StatusInternalServerError, "Internal server error", err.Error())
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/shadow"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShadowHandler handles shadow evaluation report requests
+type ShadowHandler struct {
+	runner *shadow.Runner
+}
+
+// NewShadowHandler creates a new shadow handler; runner is nil when shadow mode is disabled
+func NewShadowHandler(runner *shadow.Runner) *ShadowHandler {
+	return &ShadowHandler{
+		runner: runner,
+	}
+}
+
+// GetReport godoc
+//
+//	@Summary		Get the shadow evaluation report
+//	@Description	Compare the shadow candidate with the served results on the sampled requests since startup: label agreement, the most common disagreements, and latency, token and cost differences. Admin only.
+//	@Tags			shadow
+//	@Produce		json
+//	@Success		200	{object}	model.APIResponse{data=model.ShadowReport}	"Shadow report"
+//	@Failure		403	{object}	model.APIResponse{error=model.ErrorResponse}	"Admin scope required"
+//	@Failure		404	{object}	model.APIResponse{error=model.ErrorResponse}	"Shadow mode disabled"
+//	@Router			/api/v1/shadow/report [get]
+func (h *ShadowHandler) GetReport(c *gin.Context) {
+	if h.runner == nil {
+		respondError(c, http.StatusNotFound, "Not found", "shadow mode is not enabled")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    h.runner.Report(),
+	})
+}
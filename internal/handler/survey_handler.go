@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SurveyHandler handles survey submission requests
+type SurveyHandler struct {
+	sentimentService *service.SentimentService
+}
+
+// NewSurveyHandler creates a new survey handler
+func NewSurveyHandler(sentimentService *service.SentimentService) *SurveyHandler {
+	return &SurveyHandler{
+		sentimentService: sentimentService,
+	}
+}
+
+// AnalyzeSubmission godoc
+//
+//	@Summary		Analyze a survey submission
+//	@Description	Analyze every question and answer pair of one respondent's questionnaire and return per-question and overall respondent sentiment. Set cross_question_context to analyze all answers in one LLM call.
+//	@Tags			surveys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.SurveySubmission	true	"Survey submission"
+//	@Success		200		{object}	model.APIResponse{data=model.SurveySubmissionResponse}	"Per-question and overall sentiment"
+//	@Failure		400		{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid submission"
+//	@Failure		429		{object}	model.APIResponse{error=model.ErrorResponse}	"Budget exceeded"
+//	@Failure		503		{object}	model.APIResponse{error=model.ErrorResponse}	"LLM capacity exhausted"
+//	@Failure		500		{object}	model.APIResponse{error=model.ErrorResponse}	"LLM API failure"
+//	@Router			/api/v1/surveys/submissions [post]
+func (h *SurveyHandler) AnalyzeSubmission(c *gin.Context) {
+	var submission model.SurveySubmission
+	if err := c.ShouldBindJSON(&submission); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.sentimentService.AnalyzeSurvey(c.Request.Context(), &submission)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// AnalyzeTopics godoc
+//
+//	@Summary		Cluster answers into themes
+//	@Description	Group a batch of answers to one question into themes with LLM topic labels, falling back to local keyword clustering. Answers come from the request or, when omitted, from stored analyses of the survey question. Each theme has its size, example answers and sentiment distribution.
+//	@Tags			surveys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.TopicRequest	true	"Answers or survey question to cluster"
+//	@Success		200		{object}	model.APIResponse{data=model.TopicResponse}	"Themes"
+//	@Failure		400		{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid request"
+//	@Failure		429		{object}	model.APIResponse{error=model.ErrorResponse}	"Budget exceeded"
+//	@Failure		503		{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled or LLM capacity exhausted"
+//	@Router			/api/v1/surveys/topics [post]
+func (h *SurveyHandler) AnalyzeTopics(c *gin.Context) {
+	var req model.TopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.sentimentService.AnalyzeTopics(c.Request.Context(), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SummarizeAnswers godoc
+//
+//	@Summary		Summarize the answers to a question
+//	@Description	Write an Indonesian executive summary with the main positive and negative points of the answers to one question. Answers come from the request or, when omitted, from stored analyses of the survey question. Large batches are summarized in chunks and merged.
+//	@Tags			surveys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.AnswerSummaryRequest	true	"Answers or survey question to summarize"
+//	@Success		200		{object}	model.APIResponse{data=model.AnswerSummaryResponse}	"Executive summary"
+//	@Failure		400		{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid request"
+//	@Failure		429		{object}	model.APIResponse{error=model.ErrorResponse}	"Budget exceeded"
+//	@Failure		503		{object}	model.APIResponse{error=model.ErrorResponse}	"Storage disabled or LLM capacity exhausted"
+//	@Failure		500		{object}	model.APIResponse{error=model.ErrorResponse}	"LLM API failure"
+//	@Router			/api/v1/surveys/summaries [post]
+func (h *SurveyHandler) SummarizeAnswers(c *gin.Context) {
+	var req model.AnswerSummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.sentimentService.SummarizeAnswers(c.Request.Context(), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/usage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageHandler handles token usage reporting requests
+type UsageHandler struct {
+	usageTracker *usage.Tracker
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(usageTracker *usage.Tracker) *UsageHandler {
+	return &UsageHandler{
+		usageTracker: usageTracker,
+	}
+}
+
+// GetUsage godoc
+//
+//	@Summary		Get token usage
+//	@Description	Report LLM token usage aggregated per client and per day. Usage is stored with the analyses when storage is enabled and kept in memory otherwise. Non-admin callers only see their own usage.
+//	@Tags			usage
+//	@Produce		json
+//	@Param			from		query		string	false	"Start date (YYYY-MM-DD), defaults to the first day of the current month"
+//	@Param			to			query		string	false	"End date (YYYY-MM-DD), defaults to today"
+//	@Param			client_id	query		string	false	"Client ID to report (admin only)"
+//	@Success		200			{object}	model.APIResponse{data=model.UsageReport}	"Usage report"
+//	@Failure		400			{object}	model.APIResponse{error=model.ErrorResponse}	"Invalid date range"
+//	@Failure		403			{object}	model.APIResponse{error=model.ErrorResponse}	"Not allowed to read another client's usage"
+//	@Failure		500			{object}	model.APIResponse{error=model.ErrorResponse}	"Usage could not be read"
+//	@Router			/api/v1/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(usage.DateFormat, value); err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid request", "from must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(usage.DateFormat, value); err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid request", "to must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+	if to.Before(from) {
+		respondError(c, http.StatusBadRequest, "Invalid request", "to must not be before from")
+		return
+	}
+
+	clientID := c.Query("client_id")
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok && !principal.HasScope(auth.ScopeAdmin) {
+		if clientID != "" && clientID != principal.ID {
+			respondError(c, http.StatusForbidden, "Forbidden", "only admins can read other clients' usage")
+			return
+		}
+		clientID = principal.ID
+	}
+
+	report, err := h.usageTracker.Report(c.Request.Context(), from, to, clientID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
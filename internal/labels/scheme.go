@@ -0,0 +1,290 @@
+package labels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultSchemeID identifies the built-in Positif/Negatif/Netral scheme
+const DefaultSchemeID = "default"
+
+// ErrUnknownScheme is returned when a label scheme ID is not registered
+var ErrUnknownScheme = errors.New("unknown label scheme")
+
+// Label represents one output label of a scheme.
+// Polarity orders labels from negative to positive, with 0 for neutral.
+type Label struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Synonyms    []string `json:"synonyms,omitempty"`
+	Polarity    int      `json:"polarity"`
+}
+
+// Scheme represents a set of labels the model may return
+type Scheme struct {
+	ID          string  `json:"id"`
+	Description string  `json:"description,omitempty"`
+	Language    string  `json:"language,omitempty"`
+	Labels      []Label `json:"labels"`
+	Fallback    string  `json:"fallback"`
+	// Mixed is the opt-in label for answers holding both positive and negative views
+	Mixed string `json:"mixed,omitempty"`
+}
+
+// builtinSchemes lists the schemes available without configuration
+var builtinSchemes = []*Scheme{
+	{
+		ID:          DefaultSchemeID,
+		Description: "Tiga label sentimen dalam bahasa Indonesia",
+		Language:    "id",
+		Labels: []Label{
+			{Name: "Positif", Description: "Jawaban menunjukkan emosi atau pandangan yang baik, puas, senang, atau mendukung", Synonyms: []string{"positive"}, Polarity: 1},
+			{Name: "Negatif", Description: "Jawaban menunjukkan emosi atau pandangan yang buruk, tidak puas, kecewa, atau menolak", Synonyms: []string{"negative"}, Polarity: -1},
+			{Name: "Netral", Description: "Jawaban objektif, tidak menunjukkan emosi khusus, atau seimbang", Synonyms: []string{"neutral"}, Polarity: 0},
+		},
+		Fallback: "Netral",
+		Mixed:    "Campuran",
+	},
+	{
+		ID:          "five-point",
+		Description: "Skala lima tingkat dari Sangat Negatif hingga Sangat Positif",
+		Language:    "id",
+		Labels: []Label{
+			{Name: "Sangat Positif", Description: "Jawaban menunjukkan kepuasan atau dukungan yang sangat kuat", Synonyms: []string{"very positive"}, Polarity: 2},
+			{Name: "Positif", Description: "Jawaban menunjukkan pandangan yang baik atau puas", Synonyms: []string{"positive"}, Polarity: 1},
+			{Name: "Netral", Description: "Jawaban objektif, tidak menunjukkan emosi khusus, atau seimbang", Synonyms: []string{"neutral"}, Polarity: 0},
+			{Name: "Negatif", Description: "Jawaban menunjukkan pandangan yang buruk atau tidak puas", Synonyms: []string{"negative"}, Polarity: -1},
+			{Name: "Sangat Negatif", Description: "Jawaban menunjukkan kekecewaan, kemarahan, atau penolakan yang sangat kuat", Synonyms: []string{"very negative"}, Polarity: -2},
+		},
+		Fallback: "Netral",
+		Mixed:    "Campuran",
+	},
+	{
+		ID:          "english",
+		Description: "Three sentiment labels in English",
+		Language:    "en",
+		Labels: []Label{
+			{Name: "Positive", Description: "The answer expresses a favourable, satisfied or supportive view", Synonyms: []string{"positif"}, Polarity: 1},
+			{Name: "Negative", Description: "The answer expresses an unfavourable, dissatisfied or opposing view", Synonyms: []string{"negatif"}, Polarity: -1},
+			{Name: "Neutral", Description: "The answer is objective, balanced or shows no particular emotion", Synonyms: []string{"netral"}, Polarity: 0},
+		},
+		Fallback: "Neutral",
+		Mixed:    "Mixed",
+	},
+}
+
+// Names returns the label names of the scheme in order
+func (s *Scheme) Names() []string {
+	names := make([]string, len(s.Labels))
+	for i, label := range s.Labels {
+		names[i] = label.Name
+	}
+	return names
+}
+
+// Normalize maps a model label to a scheme label by name or synonym,
+// ignoring case. It reports false and returns the fallback when nothing matches.
+func (s *Scheme) Normalize(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, label := range s.Labels {
+		if strings.ToLower(label.Name) == value {
+			return label.Name, true
+		}
+		for _, synonym := range label.Synonyms {
+			if strings.ToLower(synonym) == value {
+				return label.Name, true
+			}
+		}
+	}
+	return s.Fallback, false
+}
+
+// Find returns the scheme label mentioned in free text, preferring the
+// longest match so "Sangat Positif" wins over "Positif"
+func (s *Scheme) Find(text string) (string, bool) {
+	type candidate struct{ term, name string }
+	var candidates []candidate
+	for _, label := range s.Labels {
+		candidates = append(candidates, candidate{label.Name, label.Name})
+		for _, synonym := range label.Synonyms {
+			candidates = append(candidates, candidate{synonym, label.Name})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].term) > len(candidates[j].term)
+	})
+
+	text = strings.ToLower(text)
+	for _, c := range candidates {
+		if strings.Contains(text, strings.ToLower(c.term)) {
+			return c.name, true
+		}
+	}
+	return s.Fallback, false
+}
+
+// ForPolarity returns the label with the given polarity, or the closest label
+// of the same sign, or the fallback
+func (s *Scheme) ForPolarity(polarity int) string {
+	best, bestDistance := "", 0
+	for _, label := range s.Labels {
+		if label.Polarity == polarity {
+			return label.Name
+		}
+		if (label.Polarity > 0) != (polarity > 0) || (label.Polarity < 0) != (polarity < 0) {
+			continue
+		}
+		distance := label.Polarity - polarity
+		if distance < 0 {
+			distance = -distance
+		}
+		if best == "" || distance < bestDistance {
+			best, bestDistance = label.Name, distance
+		}
+	}
+	if best == "" {
+		return s.Fallback
+	}
+	return best
+}
+
+// Polarity returns the polarity of a scheme label
+func (s *Scheme) Polarity(name string) int {
+	for _, label := range s.Labels {
+		if label.Name == name {
+			return label.Polarity
+		}
+	}
+	return 0
+}
+
+// validate checks that a scheme is usable for prompting and parsing
+func (s *Scheme) validate() error {
+	if strings.TrimSpace(s.ID) == "" {
+		return errors.New("label scheme id cannot be empty")
+	}
+	if len(s.Labels) < 2 {
+		return fmt.Errorf("label scheme %q needs at least two labels", s.ID)
+	}
+
+	seen := make(map[string]bool)
+	for _, label := range s.Labels {
+		if strings.TrimSpace(label.Name) == "" {
+			return fmt.Errorf("label scheme %q has a label without a name", s.ID)
+		}
+		key := strings.ToLower(label.Name)
+		if seen[key] {
+			return fmt.Errorf("label scheme %q has duplicate label %q", s.ID, label.Name)
+		}
+		seen[key] = true
+	}
+
+	if s.Fallback == "" {
+		s.Fallback = s.ForPolarity(0)
+	}
+	if _, ok := s.Normalize(s.Fallback); !ok {
+		return fmt.Errorf("label scheme %q fallback %q is not one of its labels", s.ID, s.Fallback)
+	}
+	return nil
+}
+
+// Registry holds the label schemes available to requests
+type Registry struct {
+	schemes   map[string]*Scheme
+	defaultID string
+}
+
+// NewRegistry creates a registry with the built-in schemes plus any schemes
+// defined in the JSON file at path. Schemes from the file replace built-ins
+// with the same ID.
+func NewRegistry(path, defaultID string) (*Registry, error) {
+	registry := &Registry{
+		schemes:   make(map[string]*Scheme),
+		defaultID: defaultID,
+	}
+	for _, scheme := range builtinSchemes {
+		registry.schemes[scheme.ID] = scheme
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read label schemes file: %w", err)
+		}
+
+		var schemes []*Scheme
+		if err := json.Unmarshal(data, &schemes); err != nil {
+			return nil, fmt.Errorf("failed to parse label schemes file: %w", err)
+		}
+
+		for _, scheme := range schemes {
+			if err := scheme.validate(); err != nil {
+				return nil, err
+			}
+			registry.schemes[scheme.ID] = scheme
+		}
+	}
+
+	if registry.defaultID == "" {
+		registry.defaultID = DefaultSchemeID
+	}
+	if _, ok := registry.schemes[registry.defaultID]; !ok {
+		return nil, fmt.Errorf("%w: default %q", ErrUnknownScheme, registry.defaultID)
+	}
+
+	return registry, nil
+}
+
+// Get returns the scheme with the given ID, or the default scheme when id is empty
+func (r *Registry) Get(id string) (*Scheme, error) {
+	if id == "" {
+		id = r.defaultID
+	}
+	scheme, ok := r.schemes[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, id)
+	}
+	return scheme, nil
+}
+
+// Default returns the default scheme
+func (r *Registry) Default() *Scheme {
+	return r.schemes[r.defaultID]
+}
+
+// ForLanguage returns the default scheme when its labels are in the given
+// language, or else the scheme in that language with the same number of labels
+// as the default (lowest ID first). The default scheme is returned when none matches.
+func (r *Registry) ForLanguage(lang string) *Scheme {
+	defaultScheme := r.Default()
+	if lang == "" || defaultScheme.Language == lang {
+		return defaultScheme
+	}
+
+	for _, id := range r.IDs() {
+		scheme := r.schemes[id]
+		if scheme.Language == lang && len(scheme.Labels) == len(defaultScheme.Labels) {
+			return scheme
+		}
+	}
+	return defaultScheme
+}
+
+// IDs returns the registered scheme IDs in sorted order
+func (r *Registry) IDs() []string {
+	ids := make([]string, 0, len(r.schemes))
+	for id := range r.schemes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Builtin returns the built-in default scheme, for callers without a registry
+func Builtin() *Scheme {
+	return builtinSchemes[0]
+}
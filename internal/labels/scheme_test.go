@@ -0,0 +1,136 @@
+package labels
+
+import "testing"
+
+// builtin returns the built-in scheme with the given ID
+func builtin(t *testing.T, id string) *Scheme {
+	t.Helper()
+
+	registry, err := NewRegistry("", "")
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	scheme, err := registry.Get(id)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", id, err)
+	}
+	return scheme
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		scheme string
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{scheme: DefaultSchemeID, value: "Positif", want: "Positif", wantOK: true},
+		{scheme: DefaultSchemeID, value: "  NEGATIF ", want: "Negatif", wantOK: true},
+		{scheme: DefaultSchemeID, value: "neutral", want: "Netral", wantOK: true},
+		{scheme: DefaultSchemeID, value: "Campuran", want: "Netral"},
+		{scheme: DefaultSchemeID, value: "", want: "Netral"},
+		{scheme: "five-point", value: "sangat positif", want: "Sangat Positif", wantOK: true},
+		{scheme: "five-point", value: "Very Negative", want: "Sangat Negatif", wantOK: true},
+		{scheme: "five-point", value: "positive", want: "Positif", wantOK: true},
+		{scheme: "five-point", value: "agak positif", want: "Netral"},
+		{scheme: "english", value: "positive", want: "Positive", wantOK: true},
+		{scheme: "english", value: "Negatif", want: "Negative", wantOK: true},
+		{scheme: "english", value: "netral", want: "Neutral", wantOK: true},
+		{scheme: "english", value: "Mixed", want: "Neutral"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme+"/"+tt.value, func(t *testing.T) {
+			got, ok := builtin(t, tt.scheme).Normalize(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestForPolarity(t *testing.T) {
+	tests := []struct {
+		scheme   string
+		polarity int
+		want     string
+	}{
+		{scheme: DefaultSchemeID, polarity: 1, want: "Positif"},
+		{scheme: DefaultSchemeID, polarity: 0, want: "Netral"},
+		{scheme: DefaultSchemeID, polarity: -1, want: "Negatif"},
+		{scheme: DefaultSchemeID, polarity: 2, want: "Positif"},
+		{scheme: DefaultSchemeID, polarity: -2, want: "Negatif"},
+		{scheme: "five-point", polarity: 2, want: "Sangat Positif"},
+		{scheme: "five-point", polarity: 1, want: "Positif"},
+		{scheme: "five-point", polarity: 0, want: "Netral"},
+		{scheme: "five-point", polarity: -1, want: "Negatif"},
+		{scheme: "five-point", polarity: -2, want: "Sangat Negatif"},
+		{scheme: "five-point", polarity: 3, want: "Sangat Positif"},
+		{scheme: "five-point", polarity: -5, want: "Sangat Negatif"},
+		{scheme: "english", polarity: 1, want: "Positive"},
+		{scheme: "english", polarity: 0, want: "Neutral"},
+		{scheme: "english", polarity: -1, want: "Negative"},
+		{scheme: "english", polarity: 2, want: "Positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			if got := builtin(t, tt.scheme).ForPolarity(tt.polarity); got != tt.want {
+				t.Errorf("ForPolarity(%d) = %q, want %q", tt.polarity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForPolarityFallback(t *testing.T) {
+	// Without a label of the same sign the fallback is returned
+	scheme := &Scheme{
+		ID:       "binary",
+		Labels:   []Label{{Name: "Puas", Polarity: 1}, {Name: "Tidak Puas", Polarity: -1}},
+		Fallback: "Tidak Puas",
+	}
+	if got := scheme.ForPolarity(0); got != "Tidak Puas" {
+		t.Errorf("ForPolarity(0) = %q, want the fallback %q", got, "Tidak Puas")
+	}
+}
+
+func TestPolarity(t *testing.T) {
+	tests := []struct {
+		scheme string
+		name   string
+		want   int
+	}{
+		{scheme: DefaultSchemeID, name: "Positif", want: 1},
+		{scheme: DefaultSchemeID, name: "Negatif", want: -1},
+		{scheme: DefaultSchemeID, name: "Netral", want: 0},
+		{scheme: DefaultSchemeID, name: "Campuran", want: 0},
+		{scheme: "five-point", name: "Sangat Positif", want: 2},
+		{scheme: "five-point", name: "Positif", want: 1},
+		{scheme: "five-point", name: "Negatif", want: -1},
+		{scheme: "five-point", name: "Sangat Negatif", want: -2},
+		{scheme: "english", name: "Positive", want: 1},
+		{scheme: "english", name: "Negative", want: -1},
+		{scheme: "english", name: "Neutral", want: 0},
+		// Polarity expects a normalized label name
+		{scheme: "english", name: "positive", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme+"/"+tt.name, func(t *testing.T) {
+			if got := builtin(t, tt.scheme).Polarity(tt.name); got != tt.want {
+				t.Errorf("Polarity(%q) = %d, want %d", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolarityRoundTrip(t *testing.T) {
+	for _, id := range []string{DefaultSchemeID, "five-point", "english"} {
+		scheme := builtin(t, id)
+		for _, label := range scheme.Labels {
+			if got := scheme.ForPolarity(scheme.Polarity(label.Name)); got != label.Name {
+				t.Errorf("%s: ForPolarity(Polarity(%q)) = %q", id, label.Name, got)
+			}
+		}
+	}
+}
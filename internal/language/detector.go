@@ -0,0 +1,112 @@
+package language
+
+import (
+	"sentiment-api/internal/lexicon"
+)
+
+// Supported language codes
+const (
+	Indonesian = "id"
+	English    = "en"
+	Javanese   = "jv"
+	Sundanese  = "su"
+	Mixed      = "mixed"
+)
+
+// mixedShare is the share of marker words the second language needs for a
+// text to count as code-switched
+const mixedShare = 0.3
+
+// markers lists frequent words that are distinctive for each language
+var markers = map[string][]string{
+	Indonesian: {
+		"yang", "dan", "tidak", "sangat", "saya", "kami", "ini", "itu", "dengan", "untuk",
+		"sudah", "belum", "bagus", "baik", "buruk", "karena", "tapi", "tetapi", "juga", "akan",
+		"bisa", "lebih", "sekali", "kurang", "pelayanan", "lambat", "mahal", "murah", "puas", "kecewa",
+		"kamu", "nggak", "gak", "enggak", "sih", "dong", "aja", "udah", "barang", "harga",
+	},
+	English: {
+		"the", "and", "is", "are", "was", "not", "very", "we", "it", "this",
+		"that", "with", "for", "but", "good", "bad", "service", "great", "slow", "fast",
+		"my", "to", "of", "you", "they", "have", "has", "really", "too", "price",
+	},
+	Javanese: {
+		"ora", "wis", "durung", "apik", "elek", "mboten", "sanget", "kulo", "kula", "dadi",
+		"karo", "iki", "kuwi", "nek", "lan", "sing", "ning", "tenan", "matur", "nuwun",
+		"suwun", "alon", "cepet", "seneng", "nggih", "inggih", "piye", "mung", "isih", "regane",
+	},
+	Sundanese: {
+		"teu", "abdi", "pisan", "sae", "hade", "geus", "acan", "mah", "nuhun", "hatur",
+		"kumaha", "naon", "urang", "jeung", "ieu", "kacida", "lami", "gancang", "resep", "sanes",
+		"henteu", "atuh", "euy", "alus", "pangaosna", "kirang", "ayeuna", "sumping", "tos", "tacan",
+	},
+}
+
+// markerIndex maps each marker word to its language
+var markerIndex = buildIndex()
+
+// Result represents the detected language of a text
+type Result struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Detect guesses the language of text from distinctive marker words.
+// Texts without markers are reported as Indonesian with zero confidence.
+func Detect(text string) Result {
+	tokens := lexicon.Tokenize(text)
+	hits := make(map[string]int)
+	total := 0
+	for _, token := range tokens {
+		if lang, ok := markerIndex[token]; ok {
+			hits[lang]++
+			total++
+		}
+	}
+
+	if total == 0 {
+		return Result{Language: Indonesian}
+	}
+
+	first, second := "", ""
+	for _, lang := range []string{Indonesian, English, Javanese, Sundanese} {
+		switch {
+		case first == "" || hits[lang] > hits[first]:
+			first, second = lang, first
+		case second == "" || hits[lang] > hits[second]:
+			second = lang
+		}
+	}
+
+	if hits[second] >= 2 && float64(hits[second]) >= mixedShare*float64(total) {
+		return Result{
+			Language:   Mixed,
+			Confidence: float64(hits[first]+hits[second]) / float64(total),
+		}
+	}
+
+	return Result{
+		Language:   first,
+		Confidence: float64(hits[first]) / float64(total),
+	}
+}
+
+// Supported reports whether code is a language code Detect can return
+func Supported(code string) bool {
+	switch code {
+	case Indonesian, English, Javanese, Sundanese, Mixed:
+		return true
+	}
+	return false
+}
+
+// buildIndex inverts markers into a word to language lookup
+func buildIndex() map[string]string {
+	index := make(map[string]string)
+	for lang, words := range markers {
+		for _, word := range words {
+			index[word] = lang
+		}
+	}
+	return index
+}
@@ -0,0 +1,67 @@
+package language
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		wantLanguage   string
+		wantConfidence float64
+	}{
+		{name: "indonesian", text: "Pelayanan sangat bagus dan cepat", wantLanguage: Indonesian, wantConfidence: 1},
+		{name: "english", text: "The service is very slow", wantLanguage: English, wantConfidence: 1},
+		{name: "javanese", text: "Apik tenan, matur nuwun", wantLanguage: Javanese, wantConfidence: 1},
+		{name: "sundanese", text: "Sae pisan, hatur nuhun", wantLanguage: Sundanese, wantConfidence: 1},
+		{name: "code-switched", text: "Pelayanan sangat bagus but the price is too high", wantLanguage: Mixed, wantConfidence: 1},
+		{name: "one foreign word is not mixed", text: "Barangnya bagus sekali, good", wantLanguage: Indonesian, wantConfidence: 2.0 / 3},
+		{
+			name:           "second language below the mixed share",
+			text:           "Saya sangat puas dengan pelayanan dan harga yang murah, good job, great",
+			wantLanguage:   Indonesian,
+			wantConfidence: 9.0 / 11,
+		},
+		{name: "tie goes to indonesian first", text: "bagus good", wantLanguage: Indonesian, wantConfidence: 0.5},
+		{name: "no markers", text: "Mantap jiwa", wantLanguage: Indonesian},
+		{name: "empty", text: "", wantLanguage: Indonesian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.text)
+			if got.Language != tt.wantLanguage {
+				t.Errorf("Language = %q, want %q", got.Language, tt.wantLanguage)
+			}
+			if diff := got.Confidence - tt.wantConfidence; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Confidence = %v, want %v", got.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestMarkersAreDistinct(t *testing.T) {
+	// A word listed for two languages would be counted for whichever one
+	// buildIndex visits last, which changes between runs
+	seen := make(map[string]string)
+	for lang, words := range markers {
+		for _, word := range words {
+			if other, ok := seen[word]; ok {
+				t.Errorf("marker %q is listed for both %s and %s", word, other, lang)
+			}
+			seen[word] = lang
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, code := range []string{Indonesian, English, Javanese, Sundanese, Mixed} {
+		if !Supported(code) {
+			t.Errorf("Supported(%q) = false, want true", code)
+		}
+	}
+	for _, code := range []string{"", "ms", "ID", "fr"} {
+		if Supported(code) {
+			t.Errorf("Supported(%q) = true, want false", code)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package lexicon
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// positiveWords lists Indonesian words that signal positive sentiment
+var positiveWords = map[string]float64{
+	"bagus": 1, "baik": 1, "puas": 1.5, "memuaskan": 1.5, "senang": 1.5, "suka": 1,
+	"mantap": 1.5, "hebat": 1.5, "keren": 1, "cepat": 0.5, "ramah": 1, "responsif": 1,
+	"membantu": 1, "nyaman": 1, "murah": 0.5, "terbaik": 2, "luar biasa": 2, "recommended": 1.5,
+	"rekomendasi": 1, "berkualitas": 1.5, "lancar": 1, "mudah": 0.5, "praktis": 1, "profesional": 1,
+	"sempurna": 2, "terima kasih": 0.5, "senang sekali": 2, "oke": 0.5, "ok": 0.5, "top": 1,
+	"worth": 1, "cocok": 1, "memuaskan sekali": 2, "tepat waktu": 1, "aman": 0.5, "bersih": 0.5,
+}
+
+// negativeWords lists Indonesian words that signal negative sentiment
+var negativeWords = map[string]float64{
+	"buruk": 1.5, "jelek": 1.5, "kecewa": 2, "mengecewakan": 2, "lambat": 1, "lama": 0.5,
+	"mahal": 0.5, "rusak": 1.5, "parah": 1.5, "kesal": 1.5, "marah": 2, "benci": 2,
+	"payah": 1.5, "lelet": 1, "ribet": 1, "susah": 1, "sulit": 1, "gagal": 1.5,
+	"error": 1, "kasar": 1.5, "tidak puas": 2, "penipuan": 2, "tipu": 2, "terburuk": 2,
+	"mengganggu": 1, "bermasalah": 1.5, "telat": 1, "terlambat": 1, "hilang": 1, "cacat": 1.5,
+	"kotor": 1, "menyesal": 1.5, "lemot": 1, "sampah": 2, "zonk": 1.5,
+}
+
+// negations flip the polarity of the following word
+var negations = map[string]bool{
+	"tidak": true, "tak": true, "bukan": true, "kurang": true, "belum": true, "gak": true,
+	"nggak": true, "ga": true, "enggak": true, "tdk": true,
+}
+
+// intensifiers strengthen the following word
+var intensifiers = map[string]float64{
+	"sangat": 1.5, "sekali": 1.5, "amat": 1.5, "banget": 1.5, "paling": 1.5, "terlalu": 1.3,
+	"benar-benar": 1.5, "sungguh": 1.5,
+}
+
+// Result represents the outcome of a lexicon analysis
+type Result struct {
+	Sentiment string
+	Score     float64
+	Positive  []string
+	Negative  []string
+}
+
+// Analyzer scores text using a fixed Indonesian sentiment lexicon.
+// It runs locally and costs nothing, so it serves as an offline engine.
+type Analyzer struct {
+	threshold float64
+}
+
+// NewAnalyzer creates a new lexicon analyzer
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{
+		threshold: 0.5,
+	}
+}
+
+// Analyze scores the answer text and returns Positif, Negatif or Netral
+func (a *Analyzer) Analyze(text string) *Result {
+	tokens := Tokenize(text)
+	result := &Result{}
+
+	for i := 0; i < len(tokens); i++ {
+		word := tokens[i]
+		span := 1
+
+		// Prefer two-word phrases such as "tidak puas" or "luar biasa"
+		if i+1 < len(tokens) {
+			phrase := word + " " + tokens[i+1]
+			if _, ok := positiveWords[phrase]; ok {
+				word, span = phrase, 2
+			} else if _, ok := negativeWords[phrase]; ok {
+				word, span = phrase, 2
+			}
+		}
+
+		weight, positive := positiveWords[word]
+		if !positive {
+			var negative bool
+			if weight, negative = negativeWords[word]; !negative {
+				continue
+			}
+			weight = -weight
+		}
+
+		// Look back for negations and intensifiers
+		for j := i - 1; j >= 0 && j >= i-2; j-- {
+			if negations[tokens[j]] {
+				weight = -weight
+				break
+			}
+			if factor, ok := intensifiers[tokens[j]]; ok {
+				weight *= factor
+			}
+		}
+		if i+span < len(tokens) {
+			if factor, ok := intensifiers[tokens[i+span]]; ok {
+				weight *= factor
+			}
+		}
+
+		if weight > 0 {
+			result.Positive = append(result.Positive, word)
+		} else {
+			result.Negative = append(result.Negative, word)
+		}
+		result.Score += weight
+		i += span - 1
+	}
+
+	switch {
+	case result.Score >= a.threshold:
+		result.Sentiment = "Positif"
+	case result.Score <= -a.threshold:
+		result.Sentiment = "Negatif"
+	default:
+		result.Sentiment = "Netral"
+	}
+
+	return result
+}
+
+// Explain returns a short Indonesian explanation of the lexicon verdict
+func (r *Result) Explain() string {
+	if len(r.Positive) == 0 && len(r.Negative) == 0 {
+		return "Tidak ditemukan kata bermuatan sentimen, sehingga jawaban dinilai netral."
+	}
+	return fmt.Sprintf("Analisis leksikon: kata positif [%s], kata negatif [%s], skor %.2f.",
+		strings.Join(r.Positive, ", "), strings.Join(r.Negative, ", "), r.Score)
+}
+
+// Tokenize lowercases text and splits it into words
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
@@ -0,0 +1,288 @@
+package limiter
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/config"
+)
+
+// Priority orders queued requests; lower values are served first
+type Priority int
+
+const (
+	// PriorityInteractive is used for single requests with a waiting user
+	PriorityInteractive Priority = iota
+	// PriorityBatch is used for bulk work that can tolerate delay
+	PriorityBatch
+)
+
+// ErrOverloaded is returned when the wait queue is full or the wait timed out
+var ErrOverloaded = errors.New("LLM capacity exhausted, try again later")
+
+// defaultQueueTimeout is used when the configured queue timeout is not positive
+const defaultQueueTimeout = 30 * time.Second
+
+// overloadSignal is implemented by call errors that know whether they point at
+// an overloaded backend (rate limiting, server errors, timeouts) rather than
+// at a bad request
+type overloadSignal interface {
+	Overload() bool
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx carrying the given priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority stored in ctx, defaulting to interactive
+func PriorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityInteractive
+}
+
+// Stats is a snapshot of the limiter state
+type Stats struct {
+	Limit       int   `json:"limit"`
+	InFlight    int   `json:"in_flight"`
+	Queued      int   `json:"queued"`
+	AvgLatency  int64 `json:"avg_latency_ms"`
+	Rejected    int64 `json:"rejected"`
+	QueueLength int   `json:"queue_length"`
+}
+
+// waiter is a request parked in the wait queue
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// Limiter bounds concurrent LLM calls with an adaptive (AIMD) limit.
+// The limit grows slowly while calls complete within the target latency and
+// shrinks multiplicatively on overload signals or slow responses. Requests beyond the
+// limit wait in a bounded queue where interactive work is served before batch work.
+type Limiter struct {
+	mu            sync.Mutex
+	limit         float64
+	minLimit      float64
+	maxLimit      float64
+	inFlight      int
+	queues        [2]*list.List
+	queueSize     int
+	queueTimeout  time.Duration
+	targetLatency time.Duration
+	avgLatency    time.Duration
+	rejected      int64
+}
+
+// New creates a limiter from the limiter configuration
+func New(cfg config.LimiterConfig) *Limiter {
+	minLimit := math.Max(1, float64(cfg.MinConcurrency))
+	maxLimit := math.Max(minLimit, float64(cfg.MaxConcurrency))
+	initial := math.Min(maxLimit, math.Max(minLimit, float64(cfg.InitialConcurrency)))
+
+	queueTimeout := time.Duration(cfg.QueueTimeoutSeconds) * time.Second
+	if queueTimeout <= 0 {
+		queueTimeout = defaultQueueTimeout
+	}
+
+	return &Limiter{
+		limit:         initial,
+		minLimit:      minLimit,
+		maxLimit:      maxLimit,
+		queues:        [2]*list.List{list.New(), list.New()},
+		queueSize:     cfg.QueueSize,
+		queueTimeout:  queueTimeout,
+		targetLatency: time.Duration(cfg.TargetLatencyMs) * time.Millisecond,
+	}
+}
+
+// Acquire waits for a free slot. The returned release function must be called
+// with the outcome of the LLM call once it completes; only errors that are
+// overload signals shrink the limit.
+func (l *Limiter) Acquire(ctx context.Context, priority Priority) (func(error), error) {
+	l.mu.Lock()
+
+	if l.inFlight < int(l.limit) && l.queuedLocked() == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaseFunc(time.Now()), nil
+	}
+
+	if !l.hasRoomLocked(priority) {
+		l.rejected++
+		l.mu.Unlock()
+		return nil, ErrOverloaded
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	element := l.queues[priority].PushBack(w)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	var waitErr error
+	select {
+	case <-w.ready:
+		return l.releaseFunc(time.Now()), nil
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	case <-timer.C:
+		waitErr = ErrOverloaded
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// The slot may have been granted while we were giving up
+	if w.granted {
+		return l.releaseFunc(time.Now()), nil
+	}
+
+	l.queues[priority].Remove(element)
+	if errors.Is(waitErr, ErrOverloaded) {
+		l.rejected++
+	}
+
+	return nil, waitErr
+}
+
+// Saturated reports whether a new request of the given priority would be rejected
+func (l *Limiter) Saturated(priority Priority) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight < int(l.limit) && l.queuedLocked() == 0 {
+		return false
+	}
+	return !l.hasRoomLocked(priority)
+}
+
+// RetryAfter estimates how long a rejected client should wait before retrying
+func (l *Limiter) RetryAfter() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	latency := l.avgLatency
+	if latency == 0 {
+		latency = time.Second
+	}
+
+	rounds := float64(l.queuedLocked())/math.Max(1, l.limit) + 1
+	wait := time.Duration(rounds * float64(latency))
+
+	if wait < time.Second {
+		return time.Second
+	}
+	if wait > time.Minute {
+		return time.Minute
+	}
+	return wait
+}
+
+// Stats returns a snapshot of the limiter state
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return Stats{
+		Limit:       int(l.limit),
+		InFlight:    l.inFlight,
+		Queued:      l.queuedLocked(),
+		AvgLatency:  l.avgLatency.Milliseconds(),
+		Rejected:    l.rejected,
+		QueueLength: l.queueSize,
+	}
+}
+
+// releaseFunc returns the function that frees a slot and adapts the limit
+func (l *Limiter) releaseFunc(start time.Time) func(error) {
+	var once sync.Once
+
+	return func(callErr error) {
+		once.Do(func() {
+			latency := time.Since(start)
+
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.inFlight--
+
+			switch {
+			case callErr != nil && !isOverload(callErr):
+				// Canceled calls and rejected requests say nothing about capacity
+			case callErr != nil || (l.targetLatency > 0 && latency > l.targetLatency):
+				l.limit = math.Max(l.minLimit, l.limit*0.9)
+			default:
+				l.limit = math.Min(l.maxLimit, l.limit+1/l.limit)
+			}
+
+			if callErr == nil {
+				if l.avgLatency == 0 {
+					l.avgLatency = latency
+				} else {
+					l.avgLatency = (l.avgLatency*4 + latency) / 5
+				}
+			}
+
+			l.dispatchLocked()
+		})
+	}
+}
+
+// isOverload reports whether a failed call signals an overloaded backend:
+// a deadline was exceeded or the error reports itself as an overload
+func isOverload(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var signal overloadSignal
+	return errors.As(err, &signal) && signal.Overload()
+}
+
+// dispatchLocked hands free slots to queued waiters, interactive first
+func (l *Limiter) dispatchLocked() {
+	for l.inFlight < int(l.limit) {
+		var element *list.Element
+		var queue *list.List
+		for _, q := range l.queues {
+			if element = q.Front(); element != nil {
+				queue = q
+				break
+			}
+		}
+
+		if element == nil {
+			return
+		}
+
+		w := queue.Remove(element).(*waiter)
+		w.granted = true
+		l.inFlight++
+		close(w.ready)
+	}
+}
+
+// hasRoomLocked reports whether the queue can accept another waiter.
+// Batch work may only fill half of the queue so interactive requests keep headroom.
+func (l *Limiter) hasRoomLocked(priority Priority) bool {
+	queued := l.queuedLocked()
+	if priority == PriorityBatch {
+		return queued < l.queueSize/2
+	}
+	return queued < l.queueSize
+}
+
+// queuedLocked returns the number of waiting requests
+func (l *Limiter) queuedLocked() int {
+	return l.queues[PriorityInteractive].Len() + l.queues[PriorityBatch].Len()
+}
@@ -0,0 +1,335 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/config"
+)
+
+func testConfig() config.LimiterConfig {
+	return config.LimiterConfig{
+		InitialConcurrency:  4,
+		MinConcurrency:      2,
+		MaxConcurrency:      8,
+		QueueSize:           4,
+		QueueTimeoutSeconds: 5,
+		TargetLatencyMs:     0,
+	}
+}
+
+// overloadError is a call error reporting whether it signals an overloaded backend
+type overloadError bool
+
+func (e overloadError) Error() string  { return "provider error" }
+func (e overloadError) Overload() bool { return bool(e) }
+
+func TestNewClampsLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.LimiterConfig
+		wantLimit int
+	}{
+		{name: "initial within bounds", cfg: config.LimiterConfig{InitialConcurrency: 4, MinConcurrency: 2, MaxConcurrency: 8}, wantLimit: 4},
+		{name: "initial below minimum", cfg: config.LimiterConfig{InitialConcurrency: 1, MinConcurrency: 2, MaxConcurrency: 8}, wantLimit: 2},
+		{name: "initial above maximum", cfg: config.LimiterConfig{InitialConcurrency: 20, MinConcurrency: 2, MaxConcurrency: 8}, wantLimit: 8},
+		{name: "minimum at least one", cfg: config.LimiterConfig{InitialConcurrency: 0, MinConcurrency: 0, MaxConcurrency: 0}, wantLimit: 1},
+		{name: "maximum below minimum", cfg: config.LimiterConfig{InitialConcurrency: 3, MinConcurrency: 5, MaxConcurrency: 2}, wantLimit: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.cfg).Stats().Limit; got != tt.wantLimit {
+				t.Errorf("Limit = %d, want %d", got, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestReleaseAdaptsLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		targetLatency time.Duration
+		outcomes      []error
+		wantLimit     float64
+	}{
+		{name: "success grows additively", outcomes: []error{nil}, wantLimit: 4.25},
+		{name: "overload shrinks multiplicatively", outcomes: []error{overloadError(true)}, wantLimit: 3.6},
+		{name: "wrapped overload shrinks", outcomes: []error{fmt.Errorf("all providers failed: %w", overloadError(true))}, wantLimit: 3.6},
+		{name: "timeout shrinks", outcomes: []error{context.DeadlineExceeded}, wantLimit: 3.6},
+		{name: "slow call shrinks", targetLatency: time.Nanosecond, outcomes: []error{nil}, wantLimit: 3.6},
+		{name: "rejected request keeps limit", outcomes: []error{overloadError(false)}, wantLimit: 4},
+		{name: "plain error keeps limit", outcomes: []error{errors.New("boom")}, wantLimit: 4},
+		{name: "canceled call keeps limit", outcomes: []error{context.Canceled}, wantLimit: 4},
+		{
+			name:      "overloads stop at minimum",
+			outcomes:  []error{overloadError(true), overloadError(true), overloadError(true), overloadError(true), overloadError(true), overloadError(true), overloadError(true), overloadError(true)},
+			wantLimit: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(testConfig())
+			l.targetLatency = tt.targetLatency
+
+			for _, outcome := range tt.outcomes {
+				release, err := l.Acquire(context.Background(), PriorityInteractive)
+				if err != nil {
+					t.Fatalf("Acquire() error = %v", err)
+				}
+				time.Sleep(time.Microsecond)
+				release(outcome)
+			}
+
+			if diff := l.limit - tt.wantLimit; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("limit = %v, want %v", l.limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestLimitGrowsToMaximum(t *testing.T) {
+	l := New(testConfig())
+
+	for i := 0; i < 200; i++ {
+		release, err := l.Acquire(context.Background(), PriorityInteractive)
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		release(nil)
+	}
+
+	if l.limit != 8 {
+		t.Errorf("limit = %v, want the maximum 8", l.limit)
+	}
+}
+
+func TestReleaseIsIdempotent(t *testing.T) {
+	l := New(testConfig())
+
+	release, err := l.Acquire(context.Background(), PriorityInteractive)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release(nil)
+	release(nil)
+
+	if stats := l.Stats(); stats.InFlight != 0 {
+		t.Errorf("InFlight = %d after double release, want 0", stats.InFlight)
+	}
+}
+
+// fill acquires every slot of the limiter and returns their release functions
+func fill(t *testing.T, l *Limiter) []func(error) {
+	t.Helper()
+
+	var releases []func(error)
+	for i := 0; i < l.Stats().Limit; i++ {
+		release, err := l.Acquire(context.Background(), PriorityInteractive)
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		releases = append(releases, release)
+	}
+	return releases
+}
+
+// enqueue starts an Acquire that waits in the queue and reports its outcome
+func enqueue(t *testing.T, l *Limiter, ctx context.Context, priority Priority) <-chan error {
+	t.Helper()
+
+	queued := l.Stats().Queued
+	done := make(chan error, 1)
+	go func() {
+		release, err := l.Acquire(ctx, priority)
+		if err == nil {
+			release(nil)
+		}
+		done <- err
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for l.Stats().Queued == queued {
+		if time.Now().After(deadline) {
+			t.Fatal("request was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return done
+}
+
+func TestQueueServesInteractiveFirst(t *testing.T) {
+	cfg := testConfig()
+	cfg.InitialConcurrency, cfg.MinConcurrency, cfg.MaxConcurrency = 1, 1, 1
+	l := New(cfg)
+
+	releases := fill(t, l)
+	batch := enqueue(t, l, context.Background(), PriorityBatch)
+	interactive := enqueue(t, l, context.Background(), PriorityInteractive)
+
+	// Keep the limit at one so only one waiter is admitted per release
+	releases[0](context.Canceled)
+
+	select {
+	case err := <-interactive:
+		if err != nil {
+			t.Fatalf("interactive Acquire() error = %v", err)
+		}
+	case <-batch:
+		t.Fatal("batch request was served before the interactive one")
+	case <-time.After(time.Second):
+		t.Fatal("no queued request was served")
+	}
+
+	if err := <-batch; err != nil {
+		t.Errorf("batch Acquire() error = %v", err)
+	}
+}
+
+func TestQueueRejectsWhenFull(t *testing.T) {
+	tests := []struct {
+		name     string
+		queued   []Priority
+		priority Priority
+		wantErr  error
+	}{
+		{name: "interactive with room", queued: []Priority{PriorityInteractive}, priority: PriorityInteractive},
+		{name: "batch within half", queued: []Priority{PriorityInteractive}, priority: PriorityBatch},
+		{name: "batch beyond half", queued: []Priority{PriorityInteractive, PriorityInteractive}, priority: PriorityBatch, wantErr: ErrOverloaded},
+		{name: "interactive uses headroom", queued: []Priority{PriorityInteractive, PriorityBatch, PriorityInteractive}, priority: PriorityInteractive},
+		{name: "queue full", queued: []Priority{PriorityInteractive, PriorityInteractive, PriorityInteractive, PriorityInteractive}, priority: PriorityInteractive, wantErr: ErrOverloaded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(testConfig())
+			releases := fill(t, l)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			for _, priority := range tt.queued {
+				enqueue(t, l, ctx, priority)
+			}
+
+			if saturated := l.Saturated(tt.priority); saturated != (tt.wantErr != nil) {
+				t.Errorf("Saturated() = %v, want %v", saturated, tt.wantErr != nil)
+			}
+
+			if tt.wantErr != nil {
+				_, err := l.Acquire(ctx, tt.priority)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Acquire() error = %v, want %v", err, tt.wantErr)
+				}
+				if stats := l.Stats(); stats.Rejected != 1 {
+					t.Errorf("Rejected = %d, want 1", stats.Rejected)
+				}
+			}
+
+			cancel()
+			for _, release := range releases {
+				release(nil)
+			}
+		})
+	}
+}
+
+func TestQueueTimeout(t *testing.T) {
+	l := New(testConfig())
+	l.queueTimeout = 20 * time.Millisecond
+	fill(t, l)
+
+	start := time.Now()
+	_, err := l.Acquire(context.Background(), PriorityInteractive)
+	if !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("Acquire() error = %v, want ErrOverloaded", err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("Acquire() gave up after %v, before the queue timeout", waited)
+	}
+
+	stats := l.Stats()
+	if stats.Queued != 0 || stats.Rejected != 1 {
+		t.Errorf("Queued = %d, Rejected = %d, want 0, 1", stats.Queued, stats.Rejected)
+	}
+}
+
+func TestQueueTimeoutDefault(t *testing.T) {
+	cfg := testConfig()
+	cfg.QueueTimeoutSeconds = 0
+
+	if l := New(cfg); l.queueTimeout != defaultQueueTimeout {
+		t.Errorf("queueTimeout = %v, want %v", l.queueTimeout, defaultQueueTimeout)
+	}
+}
+
+func TestQueueContextCanceled(t *testing.T) {
+	l := New(testConfig())
+	fill(t, l)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := enqueue(t, l, ctx, PriorityInteractive)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire() error = %v, want context.Canceled", err)
+	}
+
+	stats := l.Stats()
+	if stats.Queued != 0 || stats.Rejected != 0 {
+		t.Errorf("Queued = %d, Rejected = %d, want 0, 0; cancellations are not rejections", stats.Queued, stats.Rejected)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		avgLatency time.Duration
+		limit      float64
+		queued     int
+		want       time.Duration
+	}{
+		{name: "no latency yet", want: time.Second},
+		{name: "fast calls", avgLatency: 100 * time.Millisecond, limit: 4, want: time.Second},
+		{name: "queued rounds", avgLatency: 2 * time.Second, limit: 2, queued: 4, want: 6 * time.Second},
+		{name: "capped at a minute", avgLatency: 30 * time.Second, limit: 1, queued: 4, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(testConfig())
+			l.avgLatency = tt.avgLatency
+			if tt.limit > 0 {
+				l.limit = tt.limit
+			}
+			for i := 0; i < tt.queued; i++ {
+				l.queues[PriorityInteractive].PushBack(&waiter{ready: make(chan struct{})})
+			}
+
+			if got := l.RetryAfter(); got != tt.want {
+				t.Errorf("RetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Priority
+	}{
+		{name: "default", ctx: context.Background(), want: PriorityInteractive},
+		{name: "batch", ctx: WithPriority(context.Background(), PriorityBatch), want: PriorityBatch},
+		{name: "interactive", ctx: WithPriority(context.Background(), PriorityInteractive), want: PriorityInteractive},
+	}
+
+	for _, tt := range tests {
+		if got := PriorityFromContext(tt.ctx); got != tt.want {
+			t.Errorf("%s: PriorityFromContext() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// PrincipalContextKey is the gin context key holding the authenticated principal
+const PrincipalContextKey = "principal"
+
+// Authenticate authenticates requests using either a bearer JWT or a static API key.
+// Bearer tokens are checked when a JWT validator is configured; otherwise the key is
+// read from the X-API-Key header or an "ApiKey" Authorization header. Rate limits
+// and daily quotas are enforced per key, or per token subject for bearer tokens,
+// whose principal IDs carry the auth.SubjectPrefix namespace.
+func Authenticate(keys *auth.KeyStore, quotas *auth.QuotaManager, jwtValidator *auth.JWTValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := extractBearerToken(c); token != "" && jwtValidator != nil {
+			principal, err := jwtValidator.Validate(token)
+			if err != nil {
+				logger.LogWarn("Rejected request with invalid bearer token", logrus.Fields{
+					"path":      c.Request.URL.Path,
+					"client_ip": c.ClientIP(),
+					"error":     err.Error(),
+				})
+				abortWithError(c, http.StatusUnauthorized, "Unauthorized", "invalid bearer token")
+				return
+			}
+
+			if !enforceQuota(c, quotas, jwtValidator.Limits(principal.ID)) {
+				return
+			}
+
+			setPrincipal(c, principal)
+			c.Next()
+			return
+		}
+
+		if keys == nil || keys.Len() == 0 {
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized", "bearer token is required")
+			return
+		}
+
+		secret := extractAPIKey(c)
+		if secret == "" {
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized", "API key or bearer token is required")
+			return
+		}
+
+		key, ok := keys.Lookup(secret)
+		if !ok {
+			logger.LogWarn("Rejected request with invalid API key", logrus.Fields{
+				"path":      c.Request.URL.Path,
+				"client_ip": c.ClientIP(),
+			})
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized", "invalid API key")
+			return
+		}
+
+		if !enforceQuota(c, quotas, key) {
+			return
+		}
+
+		setPrincipal(c, &auth.Principal{
+			ID:            key.ID,
+			Method:        "api_key",
+			Scopes:        key.Scopes,
+			DailyBudget:   key.DailyBudget,
+			MonthlyBudget: key.MonthlyBudget,
+			LabelScheme:   key.LabelScheme,
+		})
+
+		c.Next()
+	}
+}
+
+// enforceQuota consumes one request of the caller's rate limit and daily
+// quotas, sets the quota headers and aborts the request when a limit is reached
+func enforceQuota(c *gin.Context, quotas *auth.QuotaManager, key *auth.APIKey) bool {
+	status := quotas.Allow(key)
+	setQuotaHeaders(c, status)
+
+	if !status.Allowed {
+		retryAfter := status.QuotaReset
+		if status.Reason == "rate limit exceeded" {
+			retryAfter = status.RateReset
+		}
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(retryAfter).Seconds()))))
+
+		logger.LogWarn("Request rejected by quota", logrus.Fields{
+			"client_id": key.ID,
+			"reason":    status.Reason,
+		})
+		abortWithError(c, http.StatusTooManyRequests, "Too many requests", status.Reason)
+		return false
+	}
+
+	return true
+}
+
+// RequireScope rejects requests whose principal was not granted the given scope
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized", "authentication is required")
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			logger.LogWarn("Request rejected by missing scope", logrus.Fields{
+				"client_id": principal.ID,
+				"scope":     scope,
+				"path":      c.Request.URL.Path,
+			})
+			abortWithError(c, http.StatusForbidden, "Forbidden", "missing required scope "+scope)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setPrincipal stores the authenticated principal on both the gin and request contexts
+func setPrincipal(c *gin.Context, principal *auth.Principal) {
+	c.Set(PrincipalContextKey, principal)
+	c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+}
+
+// extractBearerToken reads a bearer token from the Authorization header
+func extractBearerToken(c *gin.Context) string {
+	scheme, value, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+// extractAPIKey reads the API key from the request headers
+func extractAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key
+	}
+
+	authorization := c.GetHeader("Authorization")
+	if scheme, value, found := strings.Cut(authorization, " "); found && strings.EqualFold(scheme, "ApiKey") {
+		return strings.TrimSpace(value)
+	}
+
+	return ""
+}
+
+// setQuotaHeaders exposes the caller's remaining rate limit and quota
+func setQuotaHeaders(c *gin.Context, status auth.QuotaStatus) {
+	if status.RateLimit > 0 {
+		c.Header("X-RateLimit-Limit", strconv.Itoa(status.RateLimit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(status.RateRemaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(status.RateReset.Unix(), 10))
+	}
+
+	if status.RequestQuota > 0 {
+		c.Header("X-Quota-Requests-Limit", strconv.Itoa(status.RequestQuota))
+		c.Header("X-Quota-Requests-Remaining", strconv.Itoa(status.RequestsRemaining))
+	}
+
+	if status.TokenQuota > 0 {
+		c.Header("X-Quota-Tokens-Limit", strconv.Itoa(status.TokenQuota))
+		c.Header("X-Quota-Tokens-Remaining", strconv.Itoa(status.TokensRemaining))
+	}
+
+	if status.RequestQuota > 0 || status.TokenQuota > 0 {
+		c.Header("X-Quota-Reset", strconv.FormatInt(status.QuotaReset.Unix(), 10))
+	}
+}
+
+// abortWithError stops the request with the standard error envelope
+func abortWithError(c *gin.Context, statusCode int, errorTitle, message string) {
+	c.AbortWithStatusJSON(statusCode, model.APIResponse{
+		Success: false,
+		Error: model.ErrorResponse{
+			Error:   errorTitle,
+			Message: message,
+		},
+	})
+}
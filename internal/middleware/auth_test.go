@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/config"
+	"sentiment-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	os.Exit(m.Run())
+}
+
+// newAuthRouter serves GET /ok behind the authentication middleware
+func newAuthRouter(t *testing.T, cfg config.AuthConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	keys, err := auth.NewKeyStore(cfg)
+	if err != nil {
+		t.Fatalf("NewKeyStore() error = %v", err)
+	}
+	validator, err := auth.NewJWTValidator(cfg)
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	router := gin.New()
+	router.Use(Authenticate(keys, auth.NewQuotaManager(), validator))
+	router.GET("/ok", func(c *gin.Context) {
+		principal, _ := auth.PrincipalFromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.ID)
+	})
+	return router
+}
+
+func bearer(t *testing.T, secret, subject string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": subject,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestAuthenticateQuotas(t *testing.T) {
+	cfg := config.AuthConfig{
+		APIKeys:              "team:secret",
+		DefaultRateLimit:     100,
+		DefaultDailyRequests: 2,
+		JWTSecret:            "jwt-secret",
+	}
+
+	tests := []struct {
+		name    string
+		headers []map[string]string
+		// wantStatus is the status of the last request
+		wantStatus int
+	}{
+		{
+			name:       "API key within quota",
+			headers:    []map[string]string{{"X-API-Key": "secret"}, {"X-API-Key": "secret"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "API key over quota",
+			headers:    []map[string]string{{"X-API-Key": "secret"}, {"X-API-Key": "secret"}, {"X-API-Key": "secret"}},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "bearer token over quota",
+			headers: []map[string]string{
+				{"Authorization": bearer(t, "jwt-secret", "user-1")},
+				{"Authorization": bearer(t, "jwt-secret", "user-1")},
+				{"Authorization": bearer(t, "jwt-secret", "user-1")},
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "bearer quota is per subject",
+			headers: []map[string]string{
+				{"Authorization": bearer(t, "jwt-secret", "user-1")},
+				{"Authorization": bearer(t, "jwt-secret", "user-1")},
+				{"Authorization": bearer(t, "jwt-secret", "user-2")},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid bearer token",
+			headers:    []map[string]string{{"Authorization": bearer(t, "other", "user-1")}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no credentials",
+			headers:    []map[string]string{{}},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthRouter(t, cfg)
+
+			var recorder *httptest.ResponseRecorder
+			for _, headers := range tt.headers {
+				req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+				for name, value := range headers {
+					req.Header.Set(name, value)
+				}
+				recorder = httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+			}
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") == "" {
+				t.Error("rejected request has no Retry-After header")
+			}
+		})
+	}
+}
+
+func TestAuthenticateNamespacesBearerSubjects(t *testing.T) {
+	router := newAuthRouter(t, config.AuthConfig{
+		APIKeys:              "team:secret",
+		DefaultRateLimit:     100,
+		DefaultDailyRequests: 1,
+		JWTSecret:            "jwt-secret",
+	})
+
+	// A token whose sub equals an API key ID gets its own principal and quota
+	requests := []struct {
+		header, value, wantID string
+	}{
+		{header: "X-API-Key", value: "secret", wantID: "team"},
+		{header: "Authorization", value: bearer(t, "jwt-secret", "team"), wantID: "jwt:team"},
+	}
+
+	for _, request := range requests {
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.Header.Set(request.header, request.value)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200 (%s)", request.wantID, recorder.Code, recorder.Body.String())
+		}
+		if got := recorder.Body.String(); got != request.wantID {
+			t.Errorf("principal ID = %q, want %q", got, request.wantID)
+		}
+	}
+}
+
+func TestAuthenticateBearerQuotaHeaders(t *testing.T) {
+	router := newAuthRouter(t, config.AuthConfig{
+		DefaultRateLimit:     60,
+		DefaultDailyRequests: 1000,
+		JWTSecret:            "jwt-secret",
+		JWTDailyRequests:     5,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	req.Header.Set("Authorization", bearer(t, "jwt-secret", "user-1"))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+
+	want := map[string]string{
+		"X-RateLimit-Limit":          "60",
+		"X-Quota-Requests-Limit":     "5",
+		"X-Quota-Requests-Remaining": "4",
+	}
+	for name, value := range want {
+		if got := recorder.Header().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/budget"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BudgetGuard rejects requests from clients whose budget is spent when the
+// budget policy rejects instead of downgrading
+func BudgetGuard(budgetManager *budget.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		decision := budgetManager.Check(principal.ID, budget.Caps{
+			Daily:   principal.DailyBudget,
+			Monthly: principal.MonthlyBudget,
+		})
+		if decision.Action == budget.ActionReject {
+			abortWithError(c, http.StatusTooManyRequests, "Budget exceeded", decision.Reason)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sentiment-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSPolicy describes which cross-origin requests are allowed
+type CORSPolicy struct {
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowedMethods   []string `json:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers"`
+	AllowCredentials *bool    `json:"allow_credentials"`
+	MaxAgeSeconds    *int     `json:"max_age_seconds"`
+}
+
+// corsRoute binds a policy to a path prefix
+type corsRoute struct {
+	prefix string
+	policy CORSPolicy
+}
+
+// CORS applies the configured CORS policy, using the most specific route
+// override whose path prefix matches the request
+func CORS(cfg config.CORSConfig) (gin.HandlerFunc, error) {
+	credentials := cfg.AllowCredentials
+	maxAge := cfg.MaxAgeSeconds
+
+	defaultPolicy := CORSPolicy{
+		AllowedOrigins:   splitList(cfg.AllowedOrigins),
+		AllowedMethods:   splitList(cfg.AllowedMethods),
+		AllowedHeaders:   splitList(cfg.AllowedHeaders),
+		ExposedHeaders:   splitList(cfg.ExposedHeaders),
+		AllowCredentials: &credentials,
+		MaxAgeSeconds:    &maxAge,
+	}
+	if err := defaultPolicy.validate(); err != nil {
+		return nil, err
+	}
+
+	routes, err := loadCORSOverrides(cfg.OverridesFile, defaultPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		policy := defaultPolicy
+		for _, route := range routes {
+			if strings.HasPrefix(c.Request.URL.Path, route.prefix) {
+				policy = route.policy
+				break
+			}
+		}
+
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if !policy.allowsOrigin(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if policy.allowsAnyOrigin() {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+
+		if *policy.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+			c.Header("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+			c.Header("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+			if *policy.MaxAgeSeconds > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(*policy.MaxAgeSeconds))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if len(policy.ExposedHeaders) > 0 {
+			c.Header("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+		}
+
+		c.Next()
+	}, nil
+}
+
+// allowsOrigin reports whether the origin matches the allowed list.
+// Entries may be "*", an exact origin, or a wildcard subdomain such as
+// "https://*.example.com" or "*.example.com".
+func (p CORSPolicy) allowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range p.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		index := strings.Index(allowed, "*.")
+		if index < 0 {
+			continue
+		}
+
+		scheme := allowed[:index]
+		suffix := allowed[index+1:]
+		host := origin
+		if scheme != "" {
+			if !strings.HasPrefix(origin, scheme) {
+				continue
+			}
+			host = origin[len(scheme):]
+		} else if _, rest, found := strings.Cut(origin, "://"); found {
+			host = rest
+		}
+
+		if strings.HasSuffix(host, suffix) && len(host) > len(suffix) && !strings.Contains(host[:len(host)-len(suffix)], "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsAnyOrigin reports whether the policy contains the "*" origin
+func (p CORSPolicy) allowsAnyOrigin() bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// validate rejects policies that would let any site make credentialed
+// requests: browsers refuse "*" with credentials, so the origin would have to
+// be echoed back, which trusts every origin
+func (p CORSPolicy) validate() error {
+	if p.allowsAnyOrigin() && *p.AllowCredentials {
+		return errors.New(`CORS origin "*" cannot be combined with allowed credentials`)
+	}
+	return nil
+}
+
+// loadCORSOverrides reads per-route policies keyed by path prefix.
+// Fields left unset in an override are inherited from the default policy.
+func loadCORSOverrides(path string, defaultPolicy CORSPolicy) ([]corsRoute, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CORS overrides file: %w", err)
+	}
+
+	var overrides map[string]CORSPolicy
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse CORS overrides file: %w", err)
+	}
+
+	routes := make([]corsRoute, 0, len(overrides))
+	for prefix, policy := range overrides {
+		if policy.AllowedOrigins == nil {
+			policy.AllowedOrigins = defaultPolicy.AllowedOrigins
+		}
+		if policy.AllowedMethods == nil {
+			policy.AllowedMethods = defaultPolicy.AllowedMethods
+		}
+		if policy.AllowedHeaders == nil {
+			policy.AllowedHeaders = defaultPolicy.AllowedHeaders
+		}
+		if policy.ExposedHeaders == nil {
+			policy.ExposedHeaders = defaultPolicy.ExposedHeaders
+		}
+		if policy.AllowCredentials == nil {
+			policy.AllowCredentials = defaultPolicy.AllowCredentials
+		}
+		if policy.MaxAgeSeconds == nil {
+			policy.MaxAgeSeconds = defaultPolicy.MaxAgeSeconds
+		}
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("CORS override %q: %w", prefix, err)
+		}
+		routes = append(routes, corsRoute{prefix: prefix, policy: policy})
+	}
+
+	// Longest prefix first so the most specific override wins
+	sort.Slice(routes, func(i, j int) bool {
+		return len(routes[i].prefix) > len(routes[j].prefix)
+	})
+
+	return routes, nil
+}
+
+// splitList splits a comma separated configuration value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sentiment-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSRejectsWildcardWithCredentials(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.CORSConfig
+		overrides string
+		wantErr   bool
+	}{
+		{name: "explicit origins with credentials", cfg: config.CORSConfig{AllowedOrigins: "https://app.example.com", AllowCredentials: true}},
+		{name: "wildcard without credentials", cfg: config.CORSConfig{AllowedOrigins: "*"}},
+		{name: "wildcard with credentials", cfg: config.CORSConfig{AllowedOrigins: "https://a.example.com,*", AllowCredentials: true}, wantErr: true},
+		{
+			name:      "override adds wildcard to credentialed default",
+			cfg:       config.CORSConfig{AllowedOrigins: "https://app.example.com", AllowCredentials: true},
+			overrides: `{"/api/v1/public": {"allowed_origins": ["*"]}}`,
+			wantErr:   true,
+		},
+		{
+			name:      "override enables credentials on wildcard default",
+			cfg:       config.CORSConfig{AllowedOrigins: "*"},
+			overrides: `{"/api/v1/analyses": {"allow_credentials": true}}`,
+			wantErr:   true,
+		},
+		{
+			name:      "override restricts origins for credentials",
+			cfg:       config.CORSConfig{AllowedOrigins: "*"},
+			overrides: `{"/api/v1/analyses": {"allowed_origins": ["https://app.example.com"], "allow_credentials": true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if tt.overrides != "" {
+				cfg.OverridesFile = filepath.Join(t.TempDir(), "cors.json")
+				if err := os.WriteFile(cfg.OverridesFile, []byte(tt.overrides), 0o600); err != nil {
+					t.Fatalf("failed to write overrides: %v", err)
+				}
+			}
+
+			_, err := CORS(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CORS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCORSAllowOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		cfg             config.CORSConfig
+		origin          string
+		wantAllowOrigin string
+		wantCredentials string
+	}{
+		{
+			name:            "wildcard answers with star",
+			cfg:             config.CORSConfig{AllowedOrigins: "*"},
+			origin:          "https://evil.example",
+			wantAllowOrigin: "*",
+		},
+		{
+			name:            "listed origin is echoed with credentials",
+			cfg:             config.CORSConfig{AllowedOrigins: "https://app.example.com", AllowCredentials: true},
+			origin:          "https://app.example.com",
+			wantAllowOrigin: "https://app.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:   "unlisted origin gets no CORS headers",
+			cfg:    config.CORSConfig{AllowedOrigins: "https://app.example.com", AllowCredentials: true},
+			origin: "https://evil.example",
+		},
+		{
+			name:            "wildcard subdomain",
+			cfg:             config.CORSConfig{AllowedOrigins: "https://*.example.com"},
+			origin:          "https://app.example.com",
+			wantAllowOrigin: "https://app.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := CORS(tt.cfg)
+			if err != nil {
+				t.Fatalf("CORS() error = %v", err)
+			}
+
+			router := gin.New()
+			router.Use(handler)
+			router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			req.Header.Set("Origin", tt.origin)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowOrigin)
+			}
+			if got := recorder.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"sentiment-api/internal/limiter"
+	"sentiment-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// LoadShedding tags requests with an LLM priority and rejects them up front
+// with 503 and Retry-After when the limiter queue has no room for that priority
+func LoadShedding(concurrencyLimiter *limiter.Limiter, priority limiter.Priority) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if concurrencyLimiter.Saturated(priority) {
+			retryAfter := concurrencyLimiter.RetryAfter()
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+			logger.LogWarn("Request shed due to LLM overload", logrus.Fields{
+				"path":        c.Request.URL.Path,
+				"priority":    priority,
+				"retry_after": retryAfter.String(),
+			})
+			abortWithError(c, http.StatusServiceUnavailable, "Service overloaded", limiter.ErrOverloaded.Error())
+			return
+		}
+
+		c.Request = c.Request.WithContext(limiter.WithPriority(c.Request.Context(), priority))
+		c.Next()
+	}
+}
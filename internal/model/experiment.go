@@ -0,0 +1,24 @@
+package model
+
+// ExperimentSummary compares the variants of an A/B experiment
+type ExperimentSummary struct {
+	Experiment string           `json:"experiment" example:"nuance-prompt"`
+	Variants   []VariantSummary `json:"variants"`
+}
+
+// VariantSummary represents the stored results of one experiment variant
+type VariantSummary struct {
+	Variant string `json:"variant" example:"treatment"`
+	SentimentBreakdown
+	AvgLatencyMs     float64  `json:"avg_latency_ms" example:"812.5"`
+	ParseFailures    int      `json:"parse_failures" example:"3"`
+	ParseFailureRate float64  `json:"parse_failure_rate" example:"0.012"`
+	FeedbackCount    int      `json:"feedback_count" example:"40"`
+	FeedbackCorrect  int      `json:"feedback_correct" example:"35"`
+	FeedbackAccuracy *float64 `json:"feedback_accuracy,omitempty" example:"0.875"`
+}
+
+// FeedbackRequest represents a reviewer's correct label for a stored analysis
+type FeedbackRequest struct {
+	Sentiment string `json:"sentiment" binding:"required,max=50" example:"Positif"`
+}
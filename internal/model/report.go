@@ -0,0 +1,54 @@
+package model
+
+// SentimentBreakdown represents label counts and derived scores for a group of answers
+type SentimentBreakdown struct {
+	Total             int                `json:"total" example:"120"`
+	Counts            map[string]int     `json:"counts"`
+	Percentages       map[string]float64 `json:"percentages"`
+	NetSentimentScore float64            `json:"net_sentiment_score" example:"35.8" description:"Percentage of positive labels minus percentage of negative labels by label polarity, from -100 to 100"`
+}
+
+// TrendPoint represents the breakdown of one time bucket
+type TrendPoint struct {
+	Bucket string `json:"bucket" example:"2026-10-18"`
+	SentimentBreakdown
+}
+
+// QuestionSummary represents the sentiment breakdown of one survey question
+type QuestionSummary struct {
+	QuestionID string `json:"question_id" example:"Q1"`
+	SentimentBreakdown
+	Trend []TrendPoint `json:"trend"`
+}
+
+// SurveySummary represents the sentiment breakdown of one survey
+type SurveySummary struct {
+	SurveyID string `json:"survey_id" example:"CSAT-2026-Q3"`
+	SentimentBreakdown
+	Questions []QuestionSummary `json:"questions"`
+}
+
+// PeriodSummary represents survey summaries over one period
+type PeriodSummary struct {
+	From    string          `json:"from,omitempty" example:"2026-10-01"`
+	To      string          `json:"to,omitempty" example:"2026-10-31"`
+	Surveys []SurveySummary `json:"surveys"`
+}
+
+// SummaryDelta represents the change of a survey or question between two periods
+type SummaryDelta struct {
+	SurveyID          string             `json:"survey_id" example:"CSAT-2026-Q3"`
+	QuestionID        string             `json:"question_id,omitempty" example:"Q1"`
+	NetScoreDelta     float64            `json:"net_score_delta" example:"4.2"`
+	PercentageDeltas  map[string]float64 `json:"percentage_deltas"`
+	TotalDelta        int                `json:"total_delta" example:"-15"`
+	PresentInBaseline bool               `json:"present_in_baseline"`
+}
+
+// SummaryReport represents sentiment summaries with optional period comparison
+type SummaryReport struct {
+	Bucket     string         `json:"bucket" example:"day"`
+	Current    PeriodSummary  `json:"current"`
+	Baseline   *PeriodSummary `json:"baseline,omitempty"`
+	Comparison []SummaryDelta `json:"comparison,omitempty"`
+}
@@ -2,15 +2,120 @@ package model
 
 // SentimentRequest represents the input for sentiment analysis
 type SentimentRequest struct {
-	TextPertanyaan string `json:"text_pertanyaan" binding:"required" example:"Bagaimana pendapat Anda tentang layanan kami?" description:"The question or prompt text"`
-	TextJawaban    string `json:"text_jawaban" binding:"required" example:"Layanan Anda sangat memuaskan dan responsif" description:"The answer or response text to be analyzed"`
-	Reasoning      *bool  `json:"reasoning,omitempty" example:"true" description:"Optional: Request reasoning explanation from LLM (default: false)"`
+	TextPertanyaan  string   `json:"text_pertanyaan" binding:"required" example:"Bagaimana pendapat Anda tentang layanan kami?" description:"The question or prompt text"`
+	TextJawaban     string   `json:"text_jawaban" binding:"required" example:"Layanan Anda sangat memuaskan dan responsif" description:"The answer or response text to be analyzed"`
+	Reasoning       *bool    `json:"reasoning,omitempty" example:"true" description:"Optional: Request reasoning explanation from LLM (default: false)"`
+	IncludeMetadata *bool    `json:"include_metadata,omitempty" example:"false" description:"Optional: Include model, latency and token usage metadata in the response (default: false)"`
+	SurveyID        string   `json:"survey_id,omitempty" example:"CSAT-2026-Q3" description:"Optional: Survey the answer belongs to, stored with the result"`
+	QuestionID      string   `json:"question_id,omitempty" example:"Q1" description:"Optional: Question the answer belongs to, stored with the result"`
+	RespondentID    string   `json:"respondent_id,omitempty" example:"R-000123" description:"Optional: Respondent who gave the answer, stored with the result"`
+	Aspects         *bool    `json:"aspects,omitempty" example:"false" description:"Optional: Extract per-aspect sentiment with evidence (default: false)"`
+	AspectTaxonomy  []string `json:"aspect_taxonomy,omitempty" example:"produk,harga" description:"Optional: Aspects to look for, overriding the configured taxonomy"`
+	Emotions        *bool    `json:"emotions,omitempty" example:"false" description:"Optional: Classify emotions with intensities (default: false)"`
+	LabelScheme     string   `json:"label_scheme,omitempty" example:"five-point" description:"Optional: Label scheme to answer with, overriding the tenant default"`
+	Evidence        *bool    `json:"evidence,omitempty" example:"false" description:"Optional: Return key phrases with character offsets into text_jawaban (default: false)"`
+	Ensemble        *bool    `json:"ensemble,omitempty" example:"false" description:"Optional: Analyze with every configured ensemble engine and vote (default: false)"`
+	DetectNuance    *bool    `json:"detect_nuance,omitempty" example:"false" description:"Optional: Detect sarcasm and mixed sentiment and return flags (default: false)"`
+	AllowMixed      *bool    `json:"allow_mixed,omitempty" example:"false" description:"Optional: Return the mixed label (Campuran) for mixed answers; implies detect_nuance (default: false)"`
+	LabelLanguage   string   `json:"label_language,omitempty" example:"auto" enum:"id,en,auto" description:"Optional: Language of the output labels when no label scheme is chosen; auto follows the detected language"`
 }
 
 // SentimentResponse represents the output of sentiment analysis
 type SentimentResponse struct {
-	Sentiment string  `json:"sentiment" example:"Positif" enum:"Positif,Negatif,Netral" description:"The analyzed sentiment: Positif (positive), Negatif (negative), or Netral (neutral)"`
-	Reasoning *string `json:"reasoning,omitempty" example:"Teks menunjukkan kepuasan pelanggan dengan kata-kata positif seperti 'memuaskan' dan 'responsif'" description:"Optional: LLM reasoning explanation for the sentiment analysis"`
+	Sentiment        string            `json:"sentiment" example:"Positif" enum:"Positif,Negatif,Netral" description:"The analyzed sentiment: Positif (positive), Negatif (negative), or Netral (neutral)"`
+	DetectedLanguage string            `json:"detected_language" example:"id" enum:"id,en,jv,su,mixed" description:"Language detected in text_jawaban"`
+	Reasoning        *string           `json:"reasoning,omitempty" example:"Teks menunjukkan kepuasan pelanggan dengan kata-kata positif seperti 'memuaskan' dan 'responsif'" description:"Optional: LLM reasoning explanation for the sentiment analysis"`
+	Metadata         *AnalysisMetadata `json:"metadata,omitempty" description:"Optional: Analysis metadata, returned when include_metadata is true"`
+	Aspects          []AspectSentiment `json:"aspects,omitempty" description:"Optional: Per-aspect sentiment, returned when aspects is true"`
+	Emotions         []EmotionScore    `json:"emotions,omitempty" description:"Optional: Emotions with intensities, returned when emotions is true"`
+	Flags            *SentimentFlags   `json:"flags,omitempty" description:"Optional: Sarcasm and mixed-sentiment flags, returned when detect_nuance or allow_mixed is true"`
+	Evidence         []EvidenceSpan    `json:"evidence,omitempty" description:"Optional: Key phrases that drove the label, returned when evidence is true"`
+	Ensemble         *EnsembleVerdict  `json:"ensemble,omitempty" description:"Optional: Member verdicts and agreement, returned when ensemble is true"`
+}
+
+// EnsembleVerdict describes how an ensemble reached its label
+type EnsembleVerdict struct {
+	Strategy    string           `json:"strategy" example:"majority" enum:"majority,weighted"`
+	Agreement   float64          `json:"agreement" example:"0.67" description:"Share of the vote that went to the final label, from 0 to 1"`
+	NeedsReview bool             `json:"needs_review" example:"true" description:"Agreement is below the review threshold; route to a human"`
+	Members     []EnsembleMember `json:"members"`
+}
+
+// EnsembleMember represents one engine's verdict within an ensemble
+type EnsembleMember struct {
+	Engine    string  `json:"engine" example:"llm:telkom-ai-instruct"`
+	Sentiment string  `json:"sentiment,omitempty" example:"Positif"`
+	Weight    float64 `json:"weight" example:"1"`
+	Agrees    bool    `json:"agrees" example:"true"`
+	LatencyMs int64   `json:"latency_ms" example:"812"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// EvidenceSpan represents a key phrase of the answer and its polarity contribution.
+// Start and End are character (rune) offsets into text_jawaban, End exclusive.
+// They count Unicode code points, not bytes or the UTF-16 units that JavaScript
+// strings index by, so answers with emoji need Array.from(text) on the client.
+type EvidenceSpan struct {
+	Text         string  `json:"text" example:"sangat memuaskan"`
+	Start        int     `json:"start" example:"8" description:"Offset of the first character in text_jawaban, in Unicode code points (not UTF-16 units)"`
+	End          int     `json:"end" example:"24" description:"Offset after the last character in text_jawaban, in Unicode code points (not UTF-16 units)"`
+	Contribution float64 `json:"contribution" example:"0.9" description:"Polarity contribution from -1 to 1"`
+}
+
+// SentimentFlags reports nuances that a single label does not capture
+type SentimentFlags struct {
+	Sarcasm bool `json:"sarcasm" example:"false" description:"The answer uses sarcasm or irony"`
+	Mixed   bool `json:"mixed" example:"true" description:"The answer holds both positive and negative views"`
+}
+
+// EmotionScore represents one emotion expressed in the answer
+type EmotionScore struct {
+	Emotion   string  `json:"emotion" example:"kecewa" enum:"marah,kecewa,senang,takut,sedih,terkejut"`
+	Intensity float64 `json:"intensity" example:"0.8" description:"Intensity between 0 and 1"`
+}
+
+// AspectSentiment represents the sentiment expressed about one aspect of the answer
+type AspectSentiment struct {
+	Aspect    string `json:"aspect" example:"pengiriman"`
+	Sentiment string `json:"sentiment" example:"Negatif"`
+	Evidence  string `json:"evidence,omitempty" example:"pengirimannya lambat sekali"`
+}
+
+// AnalysisMetadata represents details about how an analysis was produced
+type AnalysisMetadata struct {
+	Model       string      `json:"model" example:"telkom-ai-instruct"`
+	LatencyMs   int64       `json:"latency_ms" example:"812"`
+	Usage       *TokenUsage `json:"usage,omitempty"`
+	LabelScheme string      `json:"label_scheme" example:"default"`
+	Route       string      `json:"route,omitempty" example:"short-answers"`
+	Provider    string      `json:"provider,omitempty" example:"telkom-ai"`
+	Experiment  string      `json:"experiment,omitempty" example:"nuance-prompt"`
+	Variant     string      `json:"variant,omitempty" example:"treatment"`
+	AnalysisID  int64       `json:"analysis_id,omitempty" example:"1042"`
+}
+
+// SentimentTypes describes the labels of one label scheme
+type SentimentTypes struct {
+	Scheme      string           `json:"scheme" example:"default"`
+	Description string           `json:"description,omitempty"`
+	Labels      []SentimentLabel `json:"labels"`
+	MixedLabel  string           `json:"mixed_label,omitempty" example:"Campuran"`
+	Schemes     []string         `json:"available_schemes" example:"default,english,five-point"`
+}
+
+// SentimentLabel describes one label a scheme may return
+type SentimentLabel struct {
+	Name        string   `json:"name" example:"Positif"`
+	Description string   `json:"description" example:"Jawaban menunjukkan emosi atau pandangan yang baik"`
+	Synonyms    []string `json:"synonyms,omitempty"`
+	Polarity    int      `json:"polarity" example:"1"`
+}
+
+// TokenUsage represents token consumption reported by the LLM API
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens" example:"182"`
+	CompletionTokens int `json:"completion_tokens" example:"9"`
+	TotalTokens      int `json:"total_tokens" example:"191"`
 }
 
 // ErrorResponse represents error response
@@ -51,5 +156,5 @@ type LLMChoice struct {
 type LLMResponse struct {
 	Choices []LLMChoice `json:"choices"`
 	Model   string      `json:"model"`
-	Usage   interface{} `json:"usage"`
+	Usage   *TokenUsage `json:"usage"`
 }
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// ShadowReport compares a candidate engine with the served results on sampled live traffic
+type ShadowReport struct {
+	Candidate           string               `json:"candidate" example:"llm:telkom-ai-instruct-v2/nuance"`
+	SampleRate          float64              `json:"sample_rate" example:"0.05"`
+	Since               time.Time            `json:"since"`
+	Samples             int64                `json:"samples" example:"240"`
+	Errors              int64                `json:"errors" example:"2"`
+	Dropped             int64                `json:"dropped" example:"0"`
+	Agreements          int64                `json:"agreements" example:"221"`
+	AgreementRate       float64              `json:"agreement_rate" example:"0.92"`
+	PrimaryAvgLatencyMs float64              `json:"primary_avg_latency_ms" example:"812"`
+	ShadowAvgLatencyMs  float64              `json:"shadow_avg_latency_ms" example:"640"`
+	LatencyDiffMs       float64              `json:"latency_diff_ms" example:"-172"`
+	PrimaryTokens       int64                `json:"primary_tokens" example:"31200"`
+	ShadowTokens        int64                `json:"shadow_tokens" example:"29850"`
+	PrimaryCost         float64              `json:"primary_cost" example:"1.56"`
+	ShadowCost          float64              `json:"shadow_cost" example:"0.9"`
+	CostDiff            float64              `json:"cost_diff" example:"-0.66"`
+	Disagreements       []ShadowDisagreement `json:"disagreements"`
+}
+
+// ShadowDisagreement counts how often the candidate changed one served label into another
+type ShadowDisagreement struct {
+	Primary string `json:"primary" example:"Netral"`
+	Shadow  string `json:"shadow" example:"Positif"`
+	Count   int64  `json:"count" example:"11"`
+}
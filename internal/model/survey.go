@@ -0,0 +1,38 @@
+package model
+
+// SurveyAnswer represents one question and answer pair of a submission
+type SurveyAnswer struct {
+	QuestionID     string `json:"question_id" binding:"required" example:"Q1"`
+	TextPertanyaan string `json:"text_pertanyaan" binding:"required" example:"Bagaimana pendapat Anda tentang layanan kami?"`
+	TextJawaban    string `json:"text_jawaban" binding:"required" example:"Layanan Anda sangat memuaskan dan responsif"`
+}
+
+// SurveySubmission represents a respondent's answers to a whole questionnaire
+type SurveySubmission struct {
+	SurveyID             string            `json:"survey_id" binding:"required" example:"CSAT-2026-Q3"`
+	RespondentID         string            `json:"respondent_id" binding:"required" example:"R-000123"`
+	Metadata             map[string]string `json:"metadata,omitempty" description:"Optional: Free-form respondent metadata such as channel or region, echoed in the response"`
+	Answers              []SurveyAnswer    `json:"answers" binding:"required,min=1,dive"`
+	CrossQuestionContext *bool             `json:"cross_question_context,omitempty" example:"true" description:"Optional: Analyze all answers in one LLM call so each answer is read in the context of the others (default: false)"`
+	Reasoning            *bool             `json:"reasoning,omitempty" example:"false" description:"Optional: Request reasoning explanation from LLM (default: false)"`
+	LabelScheme          string            `json:"label_scheme,omitempty" example:"five-point" description:"Optional: Label scheme to answer with, overriding the tenant default"`
+	LabelLanguage        string            `json:"label_language,omitempty" example:"auto" enum:"id,en,auto" description:"Optional: Language of the output labels when no label scheme is chosen; auto follows the language of the answers"`
+}
+
+// QuestionSentiment represents the sentiment of one answer in a submission
+type QuestionSentiment struct {
+	QuestionID string  `json:"question_id" example:"Q1"`
+	Sentiment  string  `json:"sentiment" example:"Positif"`
+	Reasoning  *string `json:"reasoning,omitempty"`
+}
+
+// SurveySubmissionResponse represents per-question and overall respondent sentiment
+type SurveySubmissionResponse struct {
+	SurveyID           string              `json:"survey_id" example:"CSAT-2026-Q3"`
+	RespondentID       string              `json:"respondent_id" example:"R-000123"`
+	RespondentMetadata map[string]string   `json:"respondent_metadata,omitempty" description:"The submission's metadata, unchanged"`
+	Questions          []QuestionSentiment `json:"questions"`
+	OverallSentiment   string              `json:"overall_sentiment" example:"Positif"`
+	OverallReasoning   *string             `json:"overall_reasoning,omitempty"`
+	Metadata           *AnalysisMetadata   `json:"metadata,omitempty"`
+}
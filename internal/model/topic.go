@@ -0,0 +1,66 @@
+package model
+
+// TopicRequest asks for the themes in a batch of answers to one question.
+// Answers are taken from the request or, when empty, from stored analyses of
+// the survey question (which requires stored answer text).
+type TopicRequest struct {
+	TextPertanyaan string        `json:"text_pertanyaan,omitempty" example:"Apa yang perlu kami tingkatkan?" description:"Optional: The question the answers respond to"`
+	SurveyID       string        `json:"survey_id,omitempty" example:"CSAT-2026-Q3" description:"Optional: Survey whose stored answers are clustered when answers is empty"`
+	QuestionID     string        `json:"question_id,omitempty" example:"Q2" description:"Optional: Question whose stored answers are clustered when answers is empty"`
+	Answers        []TopicAnswer `json:"answers,omitempty" binding:"omitempty,dive"`
+	MaxTopics      int           `json:"max_topics,omitempty" example:"8" description:"Optional: Maximum number of themes, excluding Lainnya (default: 8, max: 20)"`
+	Engine         string        `json:"engine,omitempty" example:"llm" enum:"llm,local" description:"Optional: llm for LLM topic labels or local for keyword clustering (default: llm)"`
+}
+
+// TopicAnswer represents one answer to cluster
+type TopicAnswer struct {
+	ID          string `json:"id,omitempty" example:"R-000123"`
+	TextJawaban string `json:"text_jawaban" binding:"required" example:"Pengirimannya terlalu lama"`
+	Sentiment   string `json:"sentiment,omitempty" example:"Negatif" description:"Optional: Known sentiment; the local lexicon labels answers without one"`
+	LabelScheme string `json:"label_scheme,omitempty" example:"default" description:"Optional: Label scheme of sentiment (default: the default scheme)"`
+}
+
+// Topic represents one theme found in the answers
+type Topic struct {
+	Label              string             `json:"label" example:"Kecepatan pengiriman"`
+	Keywords           []string           `json:"keywords,omitempty"`
+	Size               int                `json:"size" example:"42"`
+	Share              float64            `json:"share" example:"35" description:"Percentage of answers in the theme"`
+	Examples           []string           `json:"examples"`
+	AnswerIDs          []string           `json:"answer_ids,omitempty"`
+	SentimentBreakdown SentimentBreakdown `json:"sentiment_breakdown"`
+}
+
+// TopicResponse represents the themes of a batch of answers
+type TopicResponse struct {
+	SurveyID     string      `json:"survey_id,omitempty" example:"CSAT-2026-Q3"`
+	QuestionID   string      `json:"question_id,omitempty" example:"Q2"`
+	Engine       string      `json:"engine" example:"llm"`
+	TotalAnswers int         `json:"total_answers" example:"120"`
+	Topics       []Topic     `json:"topics"`
+	Model        string      `json:"model,omitempty" example:"telkom-ai-instruct"`
+	Usage        *TokenUsage `json:"usage,omitempty"`
+}
+
+// AnswerSummaryRequest asks for an executive summary of the answers to one
+// question, taken from the request or from stored analyses like TopicRequest
+type AnswerSummaryRequest struct {
+	TextPertanyaan string        `json:"text_pertanyaan,omitempty" example:"Apa yang perlu kami tingkatkan?" description:"Optional: The question the answers respond to"`
+	SurveyID       string        `json:"survey_id,omitempty" example:"CSAT-2026-Q3" description:"Optional: Survey whose stored answers are summarized when answers is empty"`
+	QuestionID     string        `json:"question_id,omitempty" example:"Q2" description:"Optional: Question whose stored answers are summarized when answers is empty"`
+	Answers        []TopicAnswer `json:"answers,omitempty" binding:"omitempty,dive"`
+}
+
+// AnswerSummaryResponse represents an executive summary of the answers to one question
+type AnswerSummaryResponse struct {
+	SurveyID           string             `json:"survey_id,omitempty" example:"CSAT-2026-Q3"`
+	QuestionID         string             `json:"question_id,omitempty" example:"Q2"`
+	TotalAnswers       int                `json:"total_answers" example:"2000"`
+	Summary            string             `json:"summary" example:"Sebagian besar responden puas dengan kualitas produk, namun banyak yang mengeluhkan lamanya pengiriman."`
+	PositivePoints     []string           `json:"positive_points"`
+	NegativePoints     []string           `json:"negative_points"`
+	SentimentBreakdown SentimentBreakdown `json:"sentiment_breakdown"`
+	Chunks             int                `json:"chunks" example:"12" description:"Number of answer chunks summarized before merging"`
+	Model              string             `json:"model" example:"telkom-ai-instruct"`
+	Usage              *TokenUsage        `json:"usage,omitempty"`
+}
@@ -0,0 +1,19 @@
+package model
+
+// UsageEntry represents aggregated token usage for one client on one day
+type UsageEntry struct {
+	ClientID         string `json:"client_id" example:"team-cx"`
+	Date             string `json:"date" example:"2026-10-18"`
+	Requests         int    `json:"requests" example:"120"`
+	PromptTokens     int    `json:"prompt_tokens" example:"21840"`
+	CompletionTokens int    `json:"completion_tokens" example:"1080"`
+	TotalTokens      int    `json:"total_tokens" example:"22920"`
+}
+
+// UsageReport represents token usage over a date range
+type UsageReport struct {
+	From    string       `json:"from" example:"2026-10-01"`
+	To      string       `json:"to" example:"2026-10-18"`
+	Entries []UsageEntry `json:"entries"`
+	Totals  UsageEntry   `json:"totals"`
+}
@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/config"
+)
+
+const (
+	// TypeLLM is a provider serving the chat completion API
+	TypeLLM = "llm"
+	// TypeLexicon is the offline lexicon engine; it can only end the chain
+	TypeLexicon = "lexicon"
+
+	// PrimaryName names the provider built from LLM_API_KEY and URL_CHAT_LLM_LLM
+	PrimaryName = "telkom-ai"
+)
+
+// ErrUnavailable is returned when every LLM provider of the chain failed
+var ErrUnavailable = errors.New("no LLM provider could serve the request")
+
+// Provider is one entry of the failover chain
+type Provider struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
+	// APIKey is used as is; APIKeyEnv names an environment variable holding the key
+	APIKey    string `json:"api_key,omitempty"`
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+	// Model replaces the requested model name when calling this provider.
+	// Usage is still recorded under the requested model.
+	Model string `json:"model,omitempty"`
+}
+
+// ModelFor returns the model name to send to this provider
+func (p Provider) ModelFor(requested string) string {
+	if p.Model != "" {
+		return p.Model
+	}
+	return requested
+}
+
+// Stats is a snapshot of one provider's health and traffic
+type Stats struct {
+	Name                string     `json:"name" example:"telkom-ai"`
+	Type                string     `json:"type" example:"llm"`
+	Healthy             bool       `json:"healthy" example:"true"`
+	Served              int64      `json:"served" example:"1520"`
+	Failures            int64      `json:"failures" example:"3"`
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"`
+	AvgLatency          int64      `json:"avg_latency_ms" example:"840"`
+	UnhealthyUntil      *time.Time `json:"unhealthy_until,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// state tracks the health of one provider
+type state struct {
+	provider       Provider
+	served         int64
+	failures       int64
+	consecutive    int
+	avgLatency     time.Duration
+	unhealthyUntil time.Time
+	lastError      string
+}
+
+// Chain is an ordered list of providers with health tracking. A provider
+// that fails FailureThreshold times in a row is skipped for the cooldown; a
+// single failure after the cooldown trips it again until a call succeeds.
+type Chain struct {
+	mu               sync.Mutex
+	states           []*state
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+}
+
+// NewChain creates the provider chain from the LLM configuration. Without a
+// providers file the chain holds only the primary endpoint.
+func NewChain(cfg config.LLMConfig) (*Chain, error) {
+	providers := []Provider{{
+		Name:   PrimaryName,
+		Type:   TypeLLM,
+		URL:    cfg.URL,
+		APIKey: cfg.APIKey,
+	}}
+
+	if cfg.ProvidersFile != "" {
+		data, err := os.ReadFile(cfg.ProvidersFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read providers file: %w", err)
+		}
+
+		providers = nil
+		if err := json.Unmarshal(data, &providers); err != nil {
+			return nil, fmt.Errorf("failed to parse providers file: %w", err)
+		}
+	}
+
+	if err := validate(providers); err != nil {
+		return nil, err
+	}
+
+	chain := &Chain{
+		failureThreshold: cfg.FailureThreshold,
+		cooldown:         time.Duration(cfg.CooldownSeconds) * time.Second,
+		now:              time.Now,
+	}
+	if chain.failureThreshold < 1 {
+		chain.failureThreshold = 1
+	}
+
+	for _, p := range providers {
+		if p.Type == "" {
+			p.Type = TypeLLM
+		}
+		if p.APIKeyEnv != "" {
+			p.APIKey = os.Getenv(p.APIKeyEnv)
+		}
+		chain.states = append(chain.states, &state{provider: p})
+	}
+
+	return chain, nil
+}
+
+// validate checks names, types, API key variables and the position of the
+// offline entry. The chain needs at least one LLM provider.
+func validate(providers []Provider) error {
+	if len(providers) == 0 {
+		return errors.New("provider chain is empty")
+	}
+
+	llmProviders := 0
+	seen := make(map[string]bool, len(providers))
+	for i, p := range providers {
+		if p.Name == "" {
+			return fmt.Errorf("provider %d has no name", i+1)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate provider %q", p.Name)
+		}
+		seen[p.Name] = true
+
+		switch p.Type {
+		case "", TypeLLM:
+			if p.URL == "" {
+				return fmt.Errorf("provider %q has no url", p.Name)
+			}
+			if p.APIKeyEnv != "" && os.Getenv(p.APIKeyEnv) == "" {
+				return fmt.Errorf("provider %q reads its API key from %s, which is not set", p.Name, p.APIKeyEnv)
+			}
+			llmProviders++
+		case TypeLexicon:
+			if i != len(providers)-1 {
+				return fmt.Errorf("offline provider %q must be the last in the chain", p.Name)
+			}
+		default:
+			return fmt.Errorf("provider %q has unknown type %q", p.Name, p.Type)
+		}
+	}
+
+	if llmProviders == 0 {
+		return errors.New("provider chain has no LLM provider")
+	}
+
+	return nil
+}
+
+// LLMProviders returns the LLM providers in the order they should be tried:
+// healthy providers in chain order, then the ones cooling down
+func (c *Chain) LLMProviders() []Provider {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	var healthy, cooling []Provider
+	for _, s := range c.states {
+		if s.provider.Type != TypeLLM {
+			continue
+		}
+		if now.Before(s.unhealthyUntil) {
+			cooling = append(cooling, s.provider)
+		} else {
+			healthy = append(healthy, s.provider)
+		}
+	}
+
+	return append(healthy, cooling...)
+}
+
+// Get returns the named provider
+func (c *Chain) Get(name string) (Provider, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s := c.stateLocked(name); s != nil {
+		return s.provider, true
+	}
+	return Provider{}, false
+}
+
+// Offline returns the offline provider ending the chain, if any
+func (c *Chain) Offline() (Provider, bool) {
+	last := c.states[len(c.states)-1].provider
+	return last, last.Type == TypeLexicon
+}
+
+// ReportSuccess records a served request and resets the provider's failure streak
+func (c *Chain) ReportSuccess(name string, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stateLocked(name)
+	if s == nil {
+		return
+	}
+
+	s.served++
+	s.consecutive = 0
+	s.unhealthyUntil = time.Time{}
+	if s.avgLatency == 0 {
+		s.avgLatency = latency
+	} else {
+		s.avgLatency = (s.avgLatency*4 + latency) / 5
+	}
+}
+
+// ReportFailure records a failed call and marks the provider unhealthy once
+// its failure streak reaches the threshold
+func (c *Chain) ReportFailure(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stateLocked(name)
+	if s == nil {
+		return
+	}
+
+	s.failures++
+	s.consecutive++
+	s.lastError = err.Error()
+	if s.consecutive >= c.failureThreshold {
+		s.unhealthyUntil = c.now().Add(c.cooldown)
+	}
+}
+
+// Stats returns a snapshot of every provider in chain order
+func (c *Chain) Stats() []Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	stats := make([]Stats, 0, len(c.states))
+	for _, s := range c.states {
+		entry := Stats{
+			Name:                s.provider.Name,
+			Type:                s.provider.Type,
+			Healthy:             !now.Before(s.unhealthyUntil),
+			Served:              s.served,
+			Failures:            s.failures,
+			ConsecutiveFailures: s.consecutive,
+			AvgLatency:          s.avgLatency.Milliseconds(),
+			LastError:           s.lastError,
+		}
+		if !entry.Healthy {
+			until := s.unhealthyUntil
+			entry.UnhealthyUntil = &until
+		}
+		stats = append(stats, entry)
+	}
+
+	return stats
+}
+
+// stateLocked returns the state of the named provider; c.mu must be held
+func (c *Chain) stateLocked(name string) *state {
+	for _, s := range c.states {
+		if s.provider.Name == name {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/config"
+)
+
+// writeProviders writes a providers file and returns its path
+func writeProviders(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "providers.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write providers file: %v", err)
+	}
+	return path
+}
+
+// providerNames returns the names of providers in order
+func providerNames(providers []Provider) []string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func TestNewChain(t *testing.T) {
+	t.Setenv("BACKUP_KEY", "backup-secret")
+	t.Setenv("EMPTY_KEY", "")
+
+	tests := []struct {
+		name        string
+		content     string
+		wantNames   []string
+		wantOffline bool
+		wantErr     bool
+	}{
+		{
+			name:      "primary endpoint without a file",
+			wantNames: []string{PrimaryName},
+		},
+		{
+			name: "llm providers ending offline",
+			content: `[
+				{"name": "telkom-ai", "url": "http://primary"},
+				{"name": "backup", "type": "llm", "url": "http://backup", "api_key_env": "BACKUP_KEY"},
+				{"name": "offline", "type": "lexicon"}
+			]`,
+			wantNames:   []string{"telkom-ai", "backup"},
+			wantOffline: true,
+		},
+		{name: "empty chain", content: `[]`, wantErr: true},
+		{name: "invalid json", content: `{"name": "backup"}`, wantErr: true},
+		{name: "missing name", content: `[{"url": "http://backup"}]`, wantErr: true},
+		{name: "duplicate name", content: `[{"name": "a", "url": "http://a"}, {"name": "a", "url": "http://b"}]`, wantErr: true},
+		{name: "llm without url", content: `[{"name": "a"}]`, wantErr: true},
+		{name: "unknown type", content: `[{"name": "a", "type": "grpc", "url": "http://a"}]`, wantErr: true},
+		{name: "offline before llm", content: `[{"name": "offline", "type": "lexicon"}, {"name": "a", "url": "http://a"}]`, wantErr: true},
+		{name: "offline only", content: `[{"name": "offline", "type": "lexicon"}]`, wantErr: true},
+		{name: "unset api key variable", content: `[{"name": "a", "url": "http://a", "api_key_env": "MISSING_PROVIDER_KEY"}]`, wantErr: true},
+		{name: "empty api key variable", content: `[{"name": "a", "url": "http://a", "api_key_env": "EMPTY_KEY"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.LLMConfig{URL: "http://primary", APIKey: "primary-secret", FailureThreshold: 2, CooldownSeconds: 30}
+			if tt.content != "" {
+				cfg.ProvidersFile = writeProviders(t, tt.content)
+			}
+
+			chain, err := NewChain(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := providerNames(chain.LLMProviders()); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("LLMProviders() = %v, want %v", got, tt.wantNames)
+			}
+			if _, got := chain.Offline(); got != tt.wantOffline {
+				t.Errorf("Offline() = %v, want %v", got, tt.wantOffline)
+			}
+		})
+	}
+}
+
+func TestNewChainAPIKeys(t *testing.T) {
+	t.Setenv("BACKUP_KEY", "backup-secret")
+
+	chain, err := NewChain(config.LLMConfig{ProvidersFile: writeProviders(t, `[
+		{"name": "inline", "url": "http://inline", "api_key": "inline-secret"},
+		{"name": "backup", "url": "http://backup", "api_key_env": "BACKUP_KEY", "model": "backup-model"}
+	]`)})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+
+	inline, _ := chain.Get("inline")
+	backup, _ := chain.Get("backup")
+	if inline.APIKey != "inline-secret" || backup.APIKey != "backup-secret" {
+		t.Errorf("API keys = %q, %q, want the inline key and the variable's value", inline.APIKey, backup.APIKey)
+	}
+	if got := backup.ModelFor("telkom-ai-instruct"); got != "backup-model" {
+		t.Errorf("ModelFor() = %q, want the provider's model", got)
+	}
+	if got := inline.ModelFor("telkom-ai-instruct"); got != "telkom-ai-instruct" {
+		t.Errorf("ModelFor() = %q, want the requested model", got)
+	}
+	if _, ok := chain.Get("missing"); ok {
+		t.Error("Get() found a provider that is not in the chain")
+	}
+}
+
+func TestChainHealth(t *testing.T) {
+	chain, err := NewChain(config.LLMConfig{
+		ProvidersFile: writeProviders(t, `[
+			{"name": "primary", "url": "http://primary"},
+			{"name": "backup", "url": "http://backup"},
+			{"name": "offline", "type": "lexicon"}
+		]`),
+		FailureThreshold: 2,
+		CooldownSeconds:  30,
+	})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	chain.now = func() time.Time { return now }
+	failure := errors.New("status 503")
+
+	steps := []struct {
+		name            string
+		advance         time.Duration
+		report          func()
+		wantOrder       []string
+		wantConsecutive int
+		wantHealthy     bool
+	}{
+		{
+			name:        "starts healthy in chain order",
+			wantOrder:   []string{"primary", "backup"},
+			wantHealthy: true,
+		},
+		{
+			name:            "one failure stays below the threshold",
+			report:          func() { chain.ReportFailure("primary", failure) },
+			wantOrder:       []string{"primary", "backup"},
+			wantConsecutive: 1,
+			wantHealthy:     true,
+		},
+		{
+			name:            "reaching the threshold moves it last",
+			report:          func() { chain.ReportFailure("primary", failure) },
+			wantOrder:       []string{"backup", "primary"},
+			wantConsecutive: 2,
+		},
+		{
+			name:            "still cooling down",
+			advance:         29 * time.Second,
+			wantOrder:       []string{"backup", "primary"},
+			wantConsecutive: 2,
+		},
+		{
+			name:            "healthy again after the cooldown",
+			advance:         time.Second,
+			wantOrder:       []string{"primary", "backup"},
+			wantConsecutive: 2,
+			wantHealthy:     true,
+		},
+		{
+			name:            "a single failure after the cooldown trips it again",
+			report:          func() { chain.ReportFailure("primary", failure) },
+			wantOrder:       []string{"backup", "primary"},
+			wantConsecutive: 3,
+		},
+		{
+			name:        "success resets the streak",
+			report:      func() { chain.ReportSuccess("primary", 100*time.Millisecond) },
+			wantOrder:   []string{"primary", "backup"},
+			wantHealthy: true,
+		},
+		{
+			name: "unknown providers are ignored",
+			report: func() {
+				chain.ReportFailure("missing", failure)
+				chain.ReportSuccess("missing", time.Second)
+			},
+			wantOrder:   []string{"primary", "backup"},
+			wantHealthy: true,
+		},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if step.report != nil {
+			step.report()
+		}
+
+		if got := providerNames(chain.LLMProviders()); !reflect.DeepEqual(got, step.wantOrder) {
+			t.Errorf("%s: LLMProviders() = %v, want %v", step.name, got, step.wantOrder)
+		}
+
+		primary := chain.Stats()[0]
+		if primary.ConsecutiveFailures != step.wantConsecutive {
+			t.Errorf("%s: ConsecutiveFailures = %d, want %d", step.name, primary.ConsecutiveFailures, step.wantConsecutive)
+		}
+		if primary.Healthy != step.wantHealthy {
+			t.Errorf("%s: Healthy = %v, want %v", step.name, primary.Healthy, step.wantHealthy)
+		}
+		if !primary.Healthy && (primary.UnhealthyUntil == nil || !primary.UnhealthyUntil.After(now)) {
+			t.Errorf("%s: UnhealthyUntil = %v, want a time after %v", step.name, primary.UnhealthyUntil, now)
+		}
+	}
+
+	stats := chain.Stats()
+	var names []string
+	for _, entry := range stats {
+		names = append(names, entry.Name)
+	}
+	if want := []string{"primary", "backup", "offline"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Stats() order = %v, want %v", names, want)
+	}
+	if stats[0].Served != 1 || stats[0].Failures != 3 || stats[0].LastError != "status 503" {
+		t.Errorf("primary stats = %+v, want 1 served and 3 failures", stats[0])
+	}
+}
+
+func TestChainAverageLatency(t *testing.T) {
+	chain, err := NewChain(config.LLMConfig{URL: "http://primary"})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+
+	chain.ReportSuccess(PrimaryName, 100*time.Millisecond)
+	chain.ReportSuccess(PrimaryName, 200*time.Millisecond)
+
+	// The average weighs the previous value four times the latest latency
+	if got := chain.Stats()[0].AvgLatency; got != 120 {
+		t.Errorf("AvgLatency = %d, want 120", got)
+	}
+}
+
+func TestChainThresholdDefault(t *testing.T) {
+	chain, err := NewChain(config.LLMConfig{URL: "http://primary", CooldownSeconds: 30})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+
+	// A threshold below one trips the provider on its first failure
+	chain.ReportFailure(PrimaryName, errors.New("timeout"))
+	if chain.Stats()[0].Healthy {
+		t.Error("Healthy = true after one failure, want the threshold clamped to 1")
+	}
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/storage"
+)
+
+// maxExportRecords bounds how many records a single export may return
+const maxExportRecords = 100000
+
+// AnalysisService handles retrieval of stored analyses
+type AnalysisService struct {
+	repository   storage.AnalysisRepository
+	storeText    bool
+	labelSchemes *labels.Registry
+}
+
+// NewAnalysisService creates a new analysis service.
+// A nil repository makes every call return storage.ErrDisabled. Answer search
+// is only available when storeText is set, since answers are not stored otherwise.
+// Stored labels are scored with the schemes of labelSchemes, or the built-in
+// scheme when it is nil.
+func NewAnalysisService(repository storage.AnalysisRepository, storeText bool, labelSchemes *labels.Registry) *AnalysisService {
+	return &AnalysisService{
+		repository:   repository,
+		storeText:    storeText,
+		labelSchemes: labelSchemes,
+	}
+}
+
+// validateFilter rejects answer searches when answers are not stored
+func (s *AnalysisService) validateFilter(filter storage.AnalysisFilter) error {
+	if filter.Search != "" && !s.storeText {
+		return &ValidationError{Message: "q requires answer text storage (STORAGE_STORE_TEXT=true)"}
+	}
+	return nil
+}
+
+// ListAnalyses returns one page of stored analyses matching the filter
+func (s *AnalysisService) ListAnalyses(ctx context.Context, filter storage.AnalysisFilter) (*storage.AnalysisPage, error) {
+	if s.repository == nil {
+		return nil, storage.ErrDisabled
+	}
+	if err := s.validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+
+	return s.repository.Query(ctx, filter)
+}
+
+// ExportAnalyses walks every analysis matching the filter, calling emit for each record
+func (s *AnalysisService) ExportAnalyses(ctx context.Context, filter storage.AnalysisFilter, emit func(storage.AnalysisRecord) error) error {
+	if s.repository == nil {
+		return storage.ErrDisabled
+	}
+	if err := s.validateFilter(filter); err != nil {
+		return err
+	}
+
+	filter.Limit = 500
+	exported := 0
+
+	for {
+		page, err := s.repository.Query(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range page.Items {
+			if err := emit(record); err != nil {
+				return err
+			}
+			exported++
+			if exported >= maxExportRecords {
+				return nil
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// SummaryPeriod describes one period of a summary request.
+// From and To are the labels echoed back in the report.
+type SummaryPeriod struct {
+	Filter storage.AnalysisFilter
+	From   string
+	To     string
+}
+
+// Summarize aggregates stored analyses per survey and question with a trend over
+// time buckets. When baseline is not nil the two periods are compared.
+func (s *AnalysisService) Summarize(ctx context.Context, current SummaryPeriod, baseline *SummaryPeriod, bucket string) (*model.SummaryReport, error) {
+	if s.repository == nil {
+		return nil, storage.ErrDisabled
+	}
+	if err := s.validateFilter(current.Filter); err != nil {
+		return nil, err
+	}
+
+	report := &model.SummaryReport{Bucket: bucket}
+
+	currentSummary, err := s.summarizePeriod(ctx, current, bucket)
+	if err != nil {
+		return nil, err
+	}
+	report.Current = *currentSummary
+
+	if baseline != nil {
+		baselineSummary, err := s.summarizePeriod(ctx, *baseline, bucket)
+		if err != nil {
+			return nil, err
+		}
+		report.Baseline = baselineSummary
+		report.Comparison = compareSummaries(currentSummary, baselineSummary)
+	}
+
+	return report, nil
+}
+
+// summarizePeriod builds survey and question breakdowns for one period
+func (s *AnalysisService) summarizePeriod(ctx context.Context, period SummaryPeriod, bucket string) (*model.PeriodSummary, error) {
+	counts, err := s.repository.CountSentiments(ctx, period.Filter, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &model.PeriodSummary{
+		From:    period.From,
+		To:      period.To,
+		Surveys: []model.SurveySummary{},
+	}
+
+	surveyIndex := make(map[string]int)
+	questionIndex := make(map[[2]string]int)
+	surveyCounts := make(map[string]*labelTally)
+	questionCounts := make(map[[2]string]*labelTally)
+	bucketCounts := make(map[[2]string]map[string]*labelTally)
+	bucketOrder := make(map[[2]string][]string)
+
+	// Counts arrive ordered by survey, question, bucket and label
+	for _, count := range counts {
+		questionKey := [2]string{count.SurveyID, count.QuestionID}
+
+		if _, exists := surveyIndex[count.SurveyID]; !exists {
+			surveyIndex[count.SurveyID] = len(summary.Surveys)
+			summary.Surveys = append(summary.Surveys, model.SurveySummary{SurveyID: count.SurveyID})
+			surveyCounts[count.SurveyID] = newLabelTally()
+		}
+
+		if _, exists := questionIndex[questionKey]; !exists {
+			survey := &summary.Surveys[surveyIndex[count.SurveyID]]
+			questionIndex[questionKey] = len(survey.Questions)
+			survey.Questions = append(survey.Questions, model.QuestionSummary{QuestionID: count.QuestionID})
+			questionCounts[questionKey] = newLabelTally()
+			bucketCounts[questionKey] = make(map[string]*labelTally)
+		}
+
+		if _, exists := bucketCounts[questionKey][count.Bucket]; !exists {
+			bucketCounts[questionKey][count.Bucket] = newLabelTally()
+			bucketOrder[questionKey] = append(bucketOrder[questionKey], count.Bucket)
+		}
+
+		scheme := storedScheme(s.labelSchemes, count.LabelScheme)
+		surveyCounts[count.SurveyID].add(scheme, count.Sentiment, count.Count)
+		questionCounts[questionKey].add(scheme, count.Sentiment, count.Count)
+		bucketCounts[questionKey][count.Bucket].add(scheme, count.Sentiment, count.Count)
+	}
+
+	for i := range summary.Surveys {
+		survey := &summary.Surveys[i]
+		survey.SentimentBreakdown = surveyCounts[survey.SurveyID].breakdown()
+
+		for j := range survey.Questions {
+			question := &survey.Questions[j]
+			questionKey := [2]string{survey.SurveyID, question.QuestionID}
+			question.SentimentBreakdown = questionCounts[questionKey].breakdown()
+
+			for _, bucketName := range bucketOrder[questionKey] {
+				question.Trend = append(question.Trend, model.TrendPoint{
+					Bucket:             bucketName,
+					SentimentBreakdown: bucketCounts[questionKey][bucketName].breakdown(),
+				})
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// labelTally counts labels and their net polarity. Each label's polarity
+// comes from the scheme its analyses were produced with, since schemes with
+// more than three labels or other languages do not use Positif and Negatif.
+type labelTally struct {
+	counts map[string]int
+	net    int
+}
+
+// newLabelTally creates an empty tally
+func newLabelTally() *labelTally {
+	return &labelTally{counts: make(map[string]int)}
+}
+
+// add counts n analyses labelled label under scheme
+func (t *labelTally) add(scheme *labels.Scheme, label string, n int) {
+	t.counts[label] += n
+	switch polarity := scheme.Polarity(label); {
+	case polarity > 0:
+		t.net += n
+	case polarity < 0:
+		t.net -= n
+	}
+}
+
+// breakdown computes percentages and the net sentiment score, the share of
+// positive labels minus the share of negative labels
+func (t *labelTally) breakdown() model.SentimentBreakdown {
+	breakdown := model.SentimentBreakdown{
+		Counts:      t.counts,
+		Percentages: make(map[string]float64, len(t.counts)),
+	}
+
+	for _, count := range t.counts {
+		breakdown.Total += count
+	}
+
+	if breakdown.Total == 0 {
+		return breakdown
+	}
+
+	for label, count := range t.counts {
+		breakdown.Percentages[label] = roundPercentage(float64(count) / float64(breakdown.Total) * 100)
+	}
+	breakdown.NetSentimentScore = roundPercentage(float64(t.net) / float64(breakdown.Total) * 100)
+
+	return breakdown
+}
+
+// storedScheme returns the scheme a stored analysis was produced with.
+// Analyses stored without a scheme, or with one that is no longer
+// configured, use the default scheme.
+func storedScheme(registry *labels.Registry, id string) *labels.Scheme {
+	if registry == nil {
+		return labels.Builtin()
+	}
+	if scheme, err := registry.Get(id); err == nil {
+		return scheme
+	}
+	return registry.Default()
+}
+
+// compareSummaries computes per-survey and per-question deltas between two periods
+func compareSummaries(current, baseline *model.PeriodSummary) []model.SummaryDelta {
+	baselineSurveys := make(map[string]model.SentimentBreakdown)
+	baselineQuestions := make(map[[2]string]model.SentimentBreakdown)
+	for _, survey := range baseline.Surveys {
+		baselineSurveys[survey.SurveyID] = survey.SentimentBreakdown
+		for _, question := range survey.Questions {
+			baselineQuestions[[2]string{survey.SurveyID, question.QuestionID}] = question.SentimentBreakdown
+		}
+	}
+
+	var deltas []model.SummaryDelta
+	for _, survey := range current.Surveys {
+		previous, exists := baselineSurveys[survey.SurveyID]
+		deltas = append(deltas, newDelta(survey.SurveyID, "", survey.SentimentBreakdown, previous, exists))
+
+		for _, question := range survey.Questions {
+			previous, exists := baselineQuestions[[2]string{survey.SurveyID, question.QuestionID}]
+			deltas = append(deltas, newDelta(survey.SurveyID, question.QuestionID, question.SentimentBreakdown, previous, exists))
+		}
+	}
+
+	return deltas
+}
+
+// newDelta computes the difference between two breakdowns
+func newDelta(surveyID, questionID string, current, previous model.SentimentBreakdown, exists bool) model.SummaryDelta {
+	delta := model.SummaryDelta{
+		SurveyID:          surveyID,
+		QuestionID:        questionID,
+		NetScoreDelta:     roundPercentage(current.NetSentimentScore - previous.NetSentimentScore),
+		PercentageDeltas:  make(map[string]float64),
+		TotalDelta:        current.Total - previous.Total,
+		PresentInBaseline: exists,
+	}
+
+	for label, percentage := range current.Percentages {
+		delta.PercentageDeltas[label] = roundPercentage(percentage - previous.Percentages[label])
+	}
+	for label, percentage := range previous.Percentages {
+		if _, seen := current.Percentages[label]; !seen {
+			delta.PercentageDeltas[label] = roundPercentage(-percentage)
+		}
+	}
+
+	return delta
+}
+
+// roundPercentage rounds to two decimals
+func roundPercentage(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// SaveFeedback stores the correct label of an analysis. A non-empty clientID
+// restricts the update to that client's analyses. The label is normalized
+// against the scheme the analysis was produced with so feedback compares
+// with stored sentiments.
+func (s *AnalysisService) SaveFeedback(ctx context.Context, id int64, clientID, label string) error {
+	if s.repository == nil {
+		return storage.ErrDisabled
+	}
+
+	record, err := s.repository.Get(ctx, id, clientID)
+	if err != nil {
+		return err
+	}
+
+	scheme := storedScheme(s.labelSchemes, record.LabelScheme)
+	normalized, ok := scheme.Normalize(label)
+	if !ok && scheme.Mixed != "" && strings.EqualFold(strings.TrimSpace(label), scheme.Mixed) {
+		normalized, ok = scheme.Mixed, true
+	}
+	if !ok {
+		allowed := scheme.Names()
+		if scheme.Mixed != "" {
+			allowed = append(allowed, scheme.Mixed)
+		}
+		return &ValidationError{Message: fmt.Sprintf("sentiment must be one of the labels of scheme %s: %s", scheme.ID, strings.Join(allowed, ", "))}
+	}
+
+	return s.repository.SaveFeedback(ctx, id, clientID, normalized)
+}
+
+// ExperimentSummary reports label distribution, latency, parse failures and
+// feedback accuracy per variant of an experiment
+func (s *AnalysisService) ExperimentSummary(ctx context.Context, experiment string) (*model.ExperimentSummary, error) {
+	if s.repository == nil {
+		return nil, storage.ErrDisabled
+	}
+
+	counts, err := s.repository.CountVariants(ctx, experiment)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &model.ExperimentSummary{
+		Experiment: experiment,
+		Variants:   []model.VariantSummary{},
+	}
+
+	labelCounts := make(map[string]*labelTally)
+	latencies := make(map[string]int64)
+	var variants []string
+
+	for _, count := range counts {
+		if _, seen := labelCounts[count.Variant]; !seen {
+			labelCounts[count.Variant] = newLabelTally()
+			variants = append(variants, count.Variant)
+			summary.Variants = append(summary.Variants, model.VariantSummary{Variant: count.Variant})
+		}
+
+		variant := &summary.Variants[len(summary.Variants)-1]
+		labelCounts[count.Variant].add(storedScheme(s.labelSchemes, count.LabelScheme), count.Sentiment, count.Count)
+		latencies[count.Variant] += count.LatencyMsSum
+		variant.ParseFailures += count.ParseFailures
+		variant.FeedbackCount += count.Feedback
+		variant.FeedbackCorrect += count.FeedbackCorrect
+	}
+
+	for i, name := range variants {
+		variant := &summary.Variants[i]
+		variant.SentimentBreakdown = labelCounts[name].breakdown()
+		if variant.Total > 0 {
+			variant.AvgLatencyMs = math.Round(float64(latencies[name])/float64(variant.Total)*10) / 10
+			variant.ParseFailureRate = math.Round(float64(variant.ParseFailures)/float64(variant.Total)*1000) / 1000
+		}
+		if variant.FeedbackCount > 0 {
+			accuracy := math.Round(float64(variant.FeedbackCorrect)/float64(variant.FeedbackCount)*1000) / 1000
+			variant.FeedbackAccuracy = &accuracy
+		}
+	}
+
+	return summary, nil
+}
@@ -1,30 +1,78 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
 
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/budget"
 	"sentiment-api/internal/client"
+	"sentiment-api/internal/config"
+	"sentiment-api/internal/engine"
+	"sentiment-api/internal/evidence"
+	"sentiment-api/internal/experiment"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/language"
+	"sentiment-api/internal/lexicon"
 	"sentiment-api/internal/model"
+	"sentiment-api/internal/provider"
+	"sentiment-api/internal/shadow"
+	"sentiment-api/internal/storage"
+	"sentiment-api/internal/usage"
 	"sentiment-api/pkg/logger"
 
 	"github.com/sirupsen/logrus"
 )
 
+// experimentRoutePrefix prefixes the route of requests assigned to an experiment
+const experimentRoutePrefix = "experiment:"
+
 // SentimentService handles sentiment analysis business logic
 type SentimentService struct {
-	llmClient *client.LLMClient
+	llmClient      *client.LLMClient
+	lexicon        *lexicon.Analyzer
+	usageTracker   *usage.Tracker
+	budgetManager  *budget.Manager
+	repository     storage.AnalysisRepository
+	storeText      bool
+	aspectTaxonomy []string
+	labelLanguage  string
+	summaryChunk   int
+	labelSchemes   *labels.Registry
+	ensemble       *engine.Ensemble
+	router         *engine.Router
+	shadow         *shadow.Runner
+	experiments    *experiment.Registry
 }
 
-// NewSentimentService creates a new sentiment service
-func NewSentimentService(llmClient *client.LLMClient) *SentimentService {
+// NewSentimentService creates a new sentiment service.
+// The usage tracker, budget manager, repository, ensemble, shadow runner and
+// experiments are optional; without a label scheme registry only the built-in
+// scheme is available and without a router every request goes to the default
+// LLM model.
+func NewSentimentService(llmClient *client.LLMClient, usageTracker *usage.Tracker, budgetManager *budget.Manager, repository storage.AnalysisRepository, storeText bool, analysisConfig config.AnalysisConfig, labelSchemes *labels.Registry, ensemble *engine.Ensemble, router *engine.Router, shadowRunner *shadow.Runner, experiments *experiment.Registry) *SentimentService {
 	return &SentimentService{
-		llmClient: llmClient,
+		llmClient:      llmClient,
+		lexicon:        lexicon.NewAnalyzer(),
+		usageTracker:   usageTracker,
+		budgetManager:  budgetManager,
+		repository:     repository,
+		storeText:      storeText,
+		aspectTaxonomy: analysisConfig.AspectList(),
+		labelLanguage:  analysisConfig.LabelLanguage,
+		summaryChunk:   analysisConfig.SummaryChunkChars,
+		labelSchemes:   labelSchemes,
+		ensemble:       ensemble,
+		router:         router,
+		shadow:         shadowRunner,
+		experiments:    experiments,
 	}
 }
 
 // AnalyzeSentiment analyzes sentiment of the given text pair
-func (s *SentimentService) AnalyzeSentiment(req *model.SentimentRequest) (*model.SentimentResponse, error) {
+func (s *SentimentService) AnalyzeSentiment(ctx context.Context, req *model.SentimentRequest) (*model.SentimentResponse, error) {
 	logger.LogInfo("Starting sentiment analysis", logrus.Fields{
 		"text_pertanyaan_length": len(req.TextPertanyaan),
 		"text_jawaban_length":    len(req.TextJawaban),
@@ -42,16 +90,86 @@ func (s *SentimentService) AnalyzeSentiment(req *model.SentimentRequest) (*model
 	// Check if reasoning is requested
 	requestReasoning := req.Reasoning != nil && *req.Reasoning
 
+	// Mixed labels need the nuance prompt, which also flags sarcasm
+	allowMixed := req.AllowMixed != nil && *req.AllowMixed
+	detectNuance := allowMixed || (req.DetectNuance != nil && *req.DetectNuance)
+
+	// Detect the answer's language to pick the prompt and, if asked, the label language
+	detected := language.Detect(req.TextJawaban)
+
+	labelLanguage := req.LabelLanguage
+	if labelLanguage == "" {
+		labelLanguage = s.labelLanguage
+	}
+	if labelLanguage == "auto" {
+		labelLanguage = autoLabelLanguage(detected.Language)
+	}
+
+	scheme, err := s.resolveScheme(ctx, req.LabelScheme, labelLanguage)
+	if err != nil {
+		return nil, err
+	}
+	opts := client.AnalysisOptions{Scheme: scheme, Language: detected.Language}
+
+	// Check the client's spend before calling the LLM
+	clientID := auth.ClientID(ctx)
+	caps := budgetCaps(ctx)
+
+	useFallback, err := s.checkBudget(clientID, caps)
+	if err != nil {
+		return nil, err
+	}
+
 	// Perform sentiment analysis using LLM
-	var sentiment string
-	var reasoning *string
-	var err error
+	var result *client.SentimentResult
+	var ensembleVerdict *model.EnsembleVerdict
+	var routeName string
 
-	if requestReasoning {
-		sentiment, reasoning, err = s.llmClient.AnalyzeSentimentWithReasoning(req.TextPertanyaan, req.TextJawaban)
+	start := time.Now()
+	if useFallback {
+		result, err = s.analyzeWithFallback(req.TextJawaban, requestReasoning, detectNuance, scheme)
+	} else if req.Ensemble != nil && *req.Ensemble {
+		result, ensembleVerdict, err = s.analyzeWithEnsemble(ctx, clientID, req, opts, requestReasoning, caps)
+		if errors.Is(err, provider.ErrUnavailable) {
+			result, err = s.failOver(clientID, req.TextJawaban, requestReasoning, detectNuance, scheme, err)
+		}
 	} else {
-		sentiment, err = s.llmClient.AnalyzeSentiment(req.TextPertanyaan, req.TextJawaban)
+		route := s.route(clientID, req.TextJawaban, detected.Language, requestReasoning)
+
+		// An active experiment overrides the routing rules
+		assignment, assigned := s.assignExperiment(clientID, req)
+		if assigned {
+			route = engine.Route{Rule: experimentRoutePrefix + assignment.Experiment, Engine: assignment.Engine}
+		}
+		routeName = route.Rule
+
+		if detectNuance {
+			result, err = s.analyzeNuanced(ctx, route, req, opts, requestReasoning)
+		} else {
+			result, err = route.Engine.Analyze(ctx, req.TextPertanyaan, req.TextJawaban, opts, requestReasoning)
+		}
+		failedOver := false
+		if errors.Is(err, provider.ErrUnavailable) {
+			result, err = s.failOver(clientID, req.TextJawaban, requestReasoning, detectNuance, scheme, err)
+			failedOver = true
+		}
+		// Failed-over results come from the lexicon, not the variant's engine
+		if err == nil && assigned && !failedOver {
+			result.Experiment = assignment.Experiment
+			result.Variant = assignment.Variant
+		}
+
+		// Compare a sample of served results with the shadow candidate
+		if err == nil && s.shadow != nil && s.shadow.Sampled() {
+			s.shadow.Run(req.TextPertanyaan, req.TextJawaban, opts, requestReasoning, shadow.Primary{
+				Sentiment: result.Sentiment,
+				Model:     result.Model,
+				Usage:     result.Usage,
+				Latency:   time.Since(start),
+			})
+		}
 	}
+	latency := time.Since(start)
 
 	if err != nil {
 		logger.LogError("Failed to analyze sentiment", logrus.Fields{
@@ -60,46 +178,529 @@ func (s *SentimentService) AnalyzeSentiment(req *model.SentimentRequest) (*model
 		return nil, err
 	}
 
+	// Sarcastic answers only look mixed on the surface, so they keep their label
+	if allowMixed && result.Flags != nil && result.Flags.Mixed && !result.Flags.Sarcasm && scheme.Mixed != "" {
+		result.Sentiment = scheme.Mixed
+	}
+
+	// Ensemble members record their own usage under their models
+	if ensembleVerdict == nil {
+		s.recordUsage(clientID, result.Model, result.Usage, caps)
+	}
+	analysisID := s.saveResult(ctx, clientID, req, scheme, result, latency)
+
+	// Aspects and emotions are additional LLM calls, skipped when downgraded
+	var aspects []model.AspectSentiment
+	if req.Aspects != nil && *req.Aspects && !useFallback {
+		aspects = s.analyzeAspects(ctx, clientID, req, scheme, caps)
+	}
+
+	var emotions []model.EmotionScore
+	if req.Emotions != nil && *req.Emotions && !useFallback {
+		emotions = s.analyzeEmotions(ctx, clientID, req, caps)
+	}
+
+	// Evidence comes from the lexicon's matched words when downgraded
+	var spans []model.EvidenceSpan
+	if req.Evidence != nil && *req.Evidence {
+		if useFallback {
+			spans = lexiconEvidence(req.TextJawaban, s.lexicon.Analyze(req.TextJawaban))
+		} else {
+			spans = s.analyzeEvidence(ctx, clientID, req, result.Sentiment, caps)
+		}
+	}
+
 	logger.LogInfo("Sentiment analysis completed", logrus.Fields{
-		"sentiment":         sentiment,
-		"reasoning_present": reasoning != nil,
+		"sentiment":         result.Sentiment,
+		"label_scheme":      scheme.ID,
+		"detected_language": detected.Language,
+		"model":             result.Model,
+		"route":             routeName,
+		"provider":          result.Provider,
+		"reasoning_present": result.Reasoning != nil,
+		"latency_ms":        latency.Milliseconds(),
 	})
 
 	response := &model.SentimentResponse{
-		Sentiment: sentiment,
-		Reasoning: reasoning,
+		Sentiment:        result.Sentiment,
+		DetectedLanguage: detected.Language,
+		Reasoning:        result.Reasoning,
+		Aspects:          aspects,
+		Emotions:         emotions,
+		Flags:            result.Flags,
+		Evidence:         spans,
+		Ensemble:         ensembleVerdict,
+	}
+
+	if req.IncludeMetadata != nil && *req.IncludeMetadata {
+		response.Metadata = &model.AnalysisMetadata{
+			Model:       result.Model,
+			LatencyMs:   latency.Milliseconds(),
+			Usage:       result.Usage,
+			LabelScheme: scheme.ID,
+			Route:       routeName,
+			Provider:    result.Provider,
+			Experiment:  result.Experiment,
+			Variant:     result.Variant,
+			AnalysisID:  analysisID,
+		}
 	}
 
 	return response, nil
 }
 
+// analyzeAspects returns the per-aspect sentiment of the answer in the labels
+// of scheme; failures are logged and leave the overall result intact
+func (s *SentimentService) analyzeAspects(ctx context.Context, clientID string, req *model.SentimentRequest, scheme *labels.Scheme, caps budget.Caps) []model.AspectSentiment {
+	taxonomy := req.AspectTaxonomy
+	if len(taxonomy) == 0 {
+		taxonomy = s.aspectTaxonomy
+	}
+
+	result, err := s.llmClient.AnalyzeAspects(ctx, req.TextPertanyaan, req.TextJawaban, taxonomy, scheme)
+	if err != nil {
+		logger.LogError("Failed to analyze aspects", logrus.Fields{
+			"client_id": clientID,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+
+	s.recordUsage(clientID, result.Model, result.Usage, caps)
+	return result.Aspects
+}
+
+// analyzeEmotions returns the emotions expressed in the answer; failures are
+// logged and leave the overall result intact
+func (s *SentimentService) analyzeEmotions(ctx context.Context, clientID string, req *model.SentimentRequest, caps budget.Caps) []model.EmotionScore {
+	result, err := s.llmClient.AnalyzeEmotions(ctx, req.TextPertanyaan, req.TextJawaban)
+	if err != nil {
+		logger.LogError("Failed to analyze emotions", logrus.Fields{
+			"client_id": clientID,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+
+	s.recordUsage(clientID, result.Model, result.Usage, caps)
+	return result.Emotions
+}
+
+// analyzeEvidence returns the key phrases that drove the label; failures are
+// logged and leave the overall result intact
+func (s *SentimentService) analyzeEvidence(ctx context.Context, clientID string, req *model.SentimentRequest, sentiment string, caps budget.Caps) []model.EvidenceSpan {
+	result, err := s.llmClient.AnalyzeEvidence(ctx, req.TextPertanyaan, req.TextJawaban, sentiment)
+	if err != nil {
+		logger.LogError("Failed to analyze evidence", logrus.Fields{
+			"client_id": clientID,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+
+	s.recordUsage(clientID, result.Model, result.Usage, caps)
+	return result.Spans
+}
+
+// lexiconEvidence anchors the lexicon's matched words in the answer
+func lexiconEvidence(textJawaban string, lexiconResult *lexicon.Result) []model.EvidenceSpan {
+	candidates := make([]model.EvidenceSpan, 0, len(lexiconResult.Positive)+len(lexiconResult.Negative))
+	for _, word := range lexiconResult.Positive {
+		candidates = append(candidates, model.EvidenceSpan{Text: word, Contribution: 1})
+	}
+	for _, word := range lexiconResult.Negative {
+		candidates = append(candidates, model.EvidenceSpan{Text: word, Contribution: -1})
+	}
+	return evidence.Validate(textJawaban, candidates)
+}
+
+// checkBudget reports whether the client must use the fallback engine,
+// or returns budget.ErrBudgetExceeded when the request must be rejected
+func (s *SentimentService) checkBudget(clientID string, caps budget.Caps) (bool, error) {
+	if s.budgetManager == nil {
+		return false, nil
+	}
+
+	decision := s.budgetManager.Check(clientID, caps)
+	switch decision.Action {
+	case budget.ActionReject:
+		logger.LogWarn("Request rejected by budget", logrus.Fields{
+			"client_id":     clientID,
+			"reason":        decision.Reason,
+			"daily_spend":   decision.DailySpend,
+			"monthly_spend": decision.MonthlySpend,
+		})
+		return false, budget.ErrBudgetExceeded
+	case budget.ActionDowngrade:
+		logger.LogWarn("Request downgraded by budget", logrus.Fields{
+			"client_id": clientID,
+			"reason":    decision.Reason,
+			"engine":    s.budgetManager.FallbackEngine(),
+		})
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// recordUsage feeds token usage into usage accounting and the budget
+func (s *SentimentService) recordUsage(clientID, modelName string, tokenUsage *model.TokenUsage, caps budget.Caps) {
+	if s.usageTracker != nil {
+		s.usageTracker.Record(clientID, modelName, tokenUsage)
+	}
+
+	if s.budgetManager != nil && tokenUsage != nil {
+		s.budgetManager.Record(clientID, modelName, *tokenUsage, caps)
+	}
+}
+
+// route picks the engine for a request. The lexicon's matched words tell the
+// router whether the answer looks mixed before any LLM call is made.
+func (s *SentimentService) route(clientID, textJawaban, detectedLanguage string, requestReasoning bool) engine.Route {
+	if s.router == nil {
+		return engine.Route{Rule: engine.DefaultRouteName, Engine: engine.NewLLMEngine(s.llmClient, "")}
+	}
+
+	lexiconResult := s.lexicon.Analyze(textJawaban)
+	return s.router.Route(engine.RouteRequest{
+		TextJawaban: textJawaban,
+		Language:    detectedLanguage,
+		Mixed:       len(lexiconResult.Positive) > 0 && len(lexiconResult.Negative) > 0,
+		Tenant:      clientID,
+		Reasoning:   requestReasoning,
+	})
+}
+
+// analyzeNuanced runs the nuance prompt on the routed model; requests routed
+// to the lexicon only get the mixed flag
+func (s *SentimentService) analyzeNuanced(ctx context.Context, route engine.Route, req *model.SentimentRequest, opts client.AnalysisOptions, requestReasoning bool) (*client.SentimentResult, error) {
+	switch routed := route.Engine.(type) {
+	case *engine.LLMEngine:
+		opts.Model = routed.Model()
+		return s.llmClient.AnalyzeSentimentNuanced(ctx, req.TextPertanyaan, req.TextJawaban, opts, requestReasoning)
+	case *engine.NuanceEngine:
+		return routed.Analyze(ctx, req.TextPertanyaan, req.TextJawaban, opts, requestReasoning)
+	}
+	return s.analyzeWithLexicon(req.TextJawaban, requestReasoning, true, opts.Scheme)
+}
+
+// assignExperiment assigns the request to a variant of the active experiment.
+// Requests without a respondent ID are assigned by API key instead.
+func (s *SentimentService) assignExperiment(clientID string, req *model.SentimentRequest) (experiment.Assignment, bool) {
+	if s.experiments == nil {
+		return experiment.Assignment{}, false
+	}
+
+	active, ok := s.experiments.Active()
+	if !ok {
+		return experiment.Assignment{}, false
+	}
+
+	unit := clientID
+	if active.AssignBy == experiment.AssignByRespondentID && req.RespondentID != "" {
+		unit = req.RespondentID
+	}
+	return active.Assign(unit), true
+}
+
+// failOver answers offline when every LLM provider failed and the provider
+// chain ends with the offline engine; otherwise cause is returned
+func (s *SentimentService) failOver(clientID, textJawaban string, requestReasoning, detectNuance bool, scheme *labels.Scheme, cause error) (*client.SentimentResult, error) {
+	chain := s.llmClient.Providers()
+	offline, ok := chain.Offline()
+	if !ok {
+		return nil, cause
+	}
+
+	logger.LogWarn("LLM providers unavailable, analyzing offline", logrus.Fields{
+		"client_id": clientID,
+		"provider":  offline.Name,
+		"error":     cause.Error(),
+	})
+
+	start := time.Now()
+	result, err := s.analyzeWithLexicon(textJawaban, requestReasoning, detectNuance, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	chain.ReportSuccess(offline.Name, time.Since(start))
+	result.Provider = offline.Name
+	return result, nil
+}
+
+// analyzeWithFallback analyzes the answer with the budget fallback engine,
+// which budget.NewManager only accepts as the lexicon
+func (s *SentimentService) analyzeWithFallback(textJawaban string, requestReasoning, detectNuance bool, scheme *labels.Scheme) (*client.SentimentResult, error) {
+	return s.analyzeWithLexicon(textJawaban, requestReasoning, detectNuance, scheme)
+}
+
+// analyzeWithLexicon analyzes the answer offline with the lexicon engine.
+// The lexicon can flag mixed answers but never sarcasm.
+func (s *SentimentService) analyzeWithLexicon(textJawaban string, requestReasoning, detectNuance bool, scheme *labels.Scheme) (*client.SentimentResult, error) {
+	opts := client.AnalysisOptions{Scheme: scheme}
+	result, err := engine.NewLexiconEngine(s.lexicon).Analyze(context.Background(), "", textJawaban, opts, requestReasoning)
+	if err != nil {
+		return nil, err
+	}
+
+	if detectNuance {
+		lexiconResult := s.lexicon.Analyze(textJawaban)
+		result.Flags = &model.SentimentFlags{
+			Mixed: len(lexiconResult.Positive) > 0 && len(lexiconResult.Negative) > 0,
+		}
+	}
+
+	return result, nil
+}
+
+// analyzeWithEnsemble analyzes the answer with every ensemble member, records
+// each member's usage and returns the voted result with its agreement as confidence
+func (s *SentimentService) analyzeWithEnsemble(ctx context.Context, clientID string, req *model.SentimentRequest, opts client.AnalysisOptions, requestReasoning bool, caps budget.Caps) (*client.SentimentResult, *model.EnsembleVerdict, error) {
+	if s.ensemble == nil {
+		return nil, nil, &ValidationError{Message: "ensemble analysis is not configured"}
+	}
+
+	ensembleResult, err := s.ensemble.Analyze(ctx, req.TextPertanyaan, req.TextJawaban, opts, requestReasoning)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agreement := ensembleResult.Agreement
+	result := &client.SentimentResult{
+		Sentiment:     ensembleResult.Sentiment,
+		Reasoning:     ensembleResult.Reasoning,
+		Model:         "ensemble",
+		PromptVersion: engine.EnsemblePromptVersion,
+		Usage:         &model.TokenUsage{},
+		Confidence:    &agreement,
+	}
+
+	verdict := &model.EnsembleVerdict{
+		Strategy:    ensembleResult.Strategy,
+		Agreement:   agreement,
+		NeedsReview: ensembleResult.NeedsReview,
+		Members:     make([]model.EnsembleMember, 0, len(ensembleResult.Members)),
+	}
+
+	for _, member := range ensembleResult.Members {
+		entry := model.EnsembleMember{
+			Engine:    member.Engine,
+			Weight:    member.Weight,
+			Agrees:    member.Agrees,
+			LatencyMs: member.Latency.Milliseconds(),
+		}
+
+		if member.Err != nil {
+			entry.Error = member.Err.Error()
+			logger.LogWarn("Ensemble member failed", logrus.Fields{
+				"engine": member.Engine,
+				"error":  member.Err.Error(),
+			})
+		} else {
+			entry.Sentiment = member.Result.Sentiment
+			s.recordUsage(clientID, member.Result.Model, member.Result.Usage, caps)
+			if usage := member.Result.Usage; usage != nil {
+				result.Usage.PromptTokens += usage.PromptTokens
+				result.Usage.CompletionTokens += usage.CompletionTokens
+				result.Usage.TotalTokens += usage.TotalTokens
+			}
+		}
+
+		verdict.Members = append(verdict.Members, entry)
+	}
+
+	if verdict.NeedsReview {
+		logger.LogInfo("Ensemble verdict needs review", logrus.Fields{
+			"client_id": clientID,
+			"sentiment": result.Sentiment,
+			"agreement": agreement,
+		})
+	}
+
+	return result, verdict, nil
+}
+
+// saveResult persists an analysis and returns its ID, or 0 when it was not
+// stored; storage failures are logged and never fail the request
+func (s *SentimentService) saveResult(ctx context.Context, clientID string, req *model.SentimentRequest, scheme *labels.Scheme, result *client.SentimentResult, latency time.Duration) int64 {
+	if s.repository == nil {
+		return 0
+	}
+
+	record := &storage.AnalysisRecord{
+		ClientID:      clientID,
+		SurveyID:      req.SurveyID,
+		QuestionID:    req.QuestionID,
+		RespondentID:  req.RespondentID,
+		TextHash:      storage.HashText(req.TextPertanyaan, req.TextJawaban),
+		Sentiment:     result.Sentiment,
+		LabelScheme:   scheme.ID,
+		Reasoning:     result.Reasoning,
+		Confidence:    result.Confidence,
+		Model:         result.Model,
+		PromptVersion: result.PromptVersion,
+		LatencyMs:     latency.Milliseconds(),
+		Experiment:    result.Experiment,
+		Variant:       result.Variant,
+		ParseFailed:   result.ParseFailed,
+	}
+
+	if s.storeText {
+		record.TextPertanyaan = &req.TextPertanyaan
+		record.TextJawaban = &req.TextJawaban
+	}
+
+	if err := s.repository.Save(ctx, record); err != nil {
+		logger.LogError("Failed to persist analysis result", logrus.Fields{
+			"client_id": clientID,
+			"error":     err.Error(),
+		})
+		return 0
+	}
+	return record.ID
+}
+
+// budgetCaps returns the spend caps of the authenticated principal
+func budgetCaps(ctx context.Context) budget.Caps {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return budget.Caps{}
+	}
+	return budget.Caps{
+		Daily:   principal.DailyBudget,
+		Monthly: principal.MonthlyBudget,
+	}
+}
+
+// autoLabelLanguage picks English labels for English answers and Indonesian
+// labels for everything else
+func autoLabelLanguage(detected string) string {
+	if detected == language.English {
+		return language.English
+	}
+	return language.Indonesian
+}
+
+// ValidationError reports a request that failed validation
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
 // validateRequest validates the sentiment analysis request
 func (s *SentimentService) validateRequest(req *model.SentimentRequest) error {
 	if req == nil {
-		return errors.New("request cannot be nil")
+		return &ValidationError{Message: "request cannot be nil"}
 	}
 
 	if strings.TrimSpace(req.TextPertanyaan) == "" {
-		return errors.New("text_pertanyaan cannot be empty")
+		return &ValidationError{Message: "text_pertanyaan cannot be empty"}
 	}
 
 	if strings.TrimSpace(req.TextJawaban) == "" {
-		return errors.New("text_jawaban cannot be empty")
+		return &ValidationError{Message: "text_jawaban cannot be empty"}
 	}
 
 	// Optional: Add length validation
 	if len(req.TextPertanyaan) > 1000 {
-		return errors.New("text_pertanyaan exceeds maximum length of 1000 characters")
+		return &ValidationError{Message: "text_pertanyaan exceeds maximum length of 1000 characters"}
 	}
 
 	if len(req.TextJawaban) > 2000 {
-		return errors.New("text_jawaban exceeds maximum length of 2000 characters")
+		return &ValidationError{Message: "text_jawaban exceeds maximum length of 2000 characters"}
+	}
+
+	switch req.LabelLanguage {
+	case "", "id", "en", "auto":
+	default:
+		return &ValidationError{Message: "label_language must be one of id, en or auto"}
+	}
+
+	if len(req.AspectTaxonomy) > 20 {
+		return &ValidationError{Message: "aspect_taxonomy cannot contain more than 20 aspects"}
+	}
+
+	for _, aspect := range req.AspectTaxonomy {
+		if strings.TrimSpace(aspect) == "" || len(aspect) > 50 {
+			return &ValidationError{Message: "aspect_taxonomy entries must be between 1 and 50 characters"}
+		}
 	}
 
 	return nil
 }
 
-// GetSupportedSentiments returns list of supported sentiment values
+// GetSupportedSentiments returns the labels of the default label scheme
 func (s *SentimentService) GetSupportedSentiments() []string {
-	return []string{"Positif", "Negatif", "Netral"}
+	if s.labelSchemes == nil {
+		return labels.Builtin().Names()
+	}
+	return s.labelSchemes.Default().Names()
+}
+
+// GetSentimentTypes describes the labels of the requested scheme, or of the
+// caller's default scheme when schemeID is empty
+func (s *SentimentService) GetSentimentTypes(ctx context.Context, schemeID string) (*model.SentimentTypes, error) {
+	labelLanguage := s.labelLanguage
+	if labelLanguage == "auto" {
+		labelLanguage = ""
+	}
+
+	scheme, err := s.resolveScheme(ctx, schemeID, labelLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	types := &model.SentimentTypes{
+		Scheme:      scheme.ID,
+		Description: scheme.Description,
+		Labels:      make([]model.SentimentLabel, 0, len(scheme.Labels)),
+		MixedLabel:  scheme.Mixed,
+		Schemes:     []string{labels.DefaultSchemeID},
+	}
+	if s.labelSchemes != nil {
+		types.Schemes = s.labelSchemes.IDs()
+	}
+
+	for _, label := range scheme.Labels {
+		types.Labels = append(types.Labels, model.SentimentLabel{
+			Name:        label.Name,
+			Description: label.Description,
+			Synonyms:    label.Synonyms,
+			Polarity:    label.Polarity,
+		})
+	}
+
+	return types, nil
+}
+
+// resolveScheme returns the requested label scheme, falling back to the
+// principal's scheme and then to the registry's scheme for labelLanguage
+func (s *SentimentService) resolveScheme(ctx context.Context, schemeID, labelLanguage string) (*labels.Scheme, error) {
+	if schemeID == "" {
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			schemeID = principal.LabelScheme
+		}
+	}
+
+	if schemeID == "" && s.labelSchemes != nil {
+		return s.labelSchemes.ForLanguage(labelLanguage), nil
+	}
+
+	if s.labelSchemes == nil {
+		if schemeID != "" && schemeID != labels.DefaultSchemeID {
+			return nil, &ValidationError{Message: "unknown label scheme: " + schemeID}
+		}
+		return labels.Builtin(), nil
+	}
+
+	scheme, err := s.labelSchemes.Get(schemeID)
+	if err != nil {
+		return nil, &ValidationError{Message: "unknown label scheme: " + schemeID}
+	}
+	return scheme, nil
 }
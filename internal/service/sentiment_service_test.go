@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/experiment"
+	"sentiment-api/internal/lexicon"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	os.Exit(m.Run())
+}
+
+// newExperimentRegistry loads experiments from content written to a temporary file
+func newExperimentRegistry(t *testing.T, content string) *experiment.Registry {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "experiments.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write experiments file: %v", err)
+	}
+
+	registry, err := experiment.NewRegistry(path, &client.LLMClient{}, lexicon.NewAnalyzer())
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	return registry
+}
+
+func TestAssignExperiment(t *testing.T) {
+	byRespondent := newExperimentRegistry(t, `[{"name": "prompt", "active": true, "assign_by": "respondent_id", "variants": [
+		{"name": "control", "weight": 1, "engine": "llm"},
+		{"name": "nuance", "weight": 1, "engine": "llm", "prompt": "nuance"}
+	]}]`)
+	byAPIKey := newExperimentRegistry(t, `[{"name": "engine", "active": true, "variants": [
+		{"name": "lexicon", "weight": 1, "engine": "lexicon"},
+		{"name": "llm", "weight": 1, "engine": "llm"}
+	]}]`)
+	inactive := newExperimentRegistry(t, `[{"name": "finished", "variants": [
+		{"name": "lexicon", "weight": 1, "engine": "lexicon"},
+		{"name": "llm", "weight": 1, "engine": "llm"}
+	]}]`)
+
+	const clientID = "team"
+	respondentActive, _ := byRespondent.Active()
+	apiKeyActive, _ := byAPIKey.Active()
+
+	// Pick a respondent whose variant differs from the API key's, so the
+	// tests can tell which unit was used
+	respondentID := ""
+	for i := 0; respondentID == ""; i++ {
+		candidate := fmt.Sprintf("respondent-%d", i)
+		if respondentActive.Assign(candidate).Variant != respondentActive.Assign(clientID).Variant &&
+			apiKeyActive.Assign(candidate).Variant != apiKeyActive.Assign(clientID).Variant {
+			respondentID = candidate
+		}
+	}
+
+	tests := []struct {
+		name         string
+		experiments  *experiment.Registry
+		respondentID string
+		wantAssigned bool
+		wantVariant  string
+	}{
+		{
+			name:         "no experiments configured",
+			respondentID: respondentID,
+		},
+		{
+			name:         "no active experiment",
+			experiments:  inactive,
+			respondentID: respondentID,
+		},
+		{
+			name:         "by respondent",
+			experiments:  byRespondent,
+			respondentID: respondentID,
+			wantAssigned: true,
+			wantVariant:  respondentActive.Assign(respondentID).Variant,
+		},
+		{
+			name:         "by respondent falls back to the API key",
+			experiments:  byRespondent,
+			wantAssigned: true,
+			wantVariant:  respondentActive.Assign(clientID).Variant,
+		},
+		{
+			name:         "by API key ignores the respondent",
+			experiments:  byAPIKey,
+			respondentID: respondentID,
+			wantAssigned: true,
+			wantVariant:  apiKeyActive.Assign(clientID).Variant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SentimentService{experiments: tt.experiments}
+
+			req := &model.SentimentRequest{RespondentID: tt.respondentID}
+			first, assigned := s.assignExperiment(clientID, req)
+			if assigned != tt.wantAssigned {
+				t.Fatalf("assignExperiment() assigned = %v, want %v", assigned, tt.wantAssigned)
+			}
+			if !assigned {
+				return
+			}
+			if first.Variant != tt.wantVariant {
+				t.Errorf("Variant = %q, want %q", first.Variant, tt.wantVariant)
+			}
+
+			if again, _ := s.assignExperiment(clientID, req); again.Variant != first.Variant {
+				t.Errorf("assignExperiment() = %q then %q, want a stable variant", first.Variant, again.Variant)
+			}
+		})
+	}
+}
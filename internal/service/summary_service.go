@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/budget"
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxSummaryAnswers bounds the number of answers summarized in one request
+	maxSummaryAnswers = 5000
+	// maxSummaryAnswerLength bounds each answer quoted in a summary prompt
+	maxSummaryAnswerLength = 500
+	// minSummaryChunkChars is the smallest usable chunk size
+	minSummaryChunkChars = 1000
+	// summaryWorkers bounds concurrent summary calls of one request
+	summaryWorkers = 4
+)
+
+// SummarizeAnswers writes an Indonesian executive summary of the answers to
+// one question. Answers are split into chunks that fit one prompt, each chunk
+// is summarized (map) and the partial summaries are merged until one remains
+// (reduce), so the number of answers is not limited by the context window.
+func (s *SentimentService) SummarizeAnswers(ctx context.Context, req *model.AnswerSummaryRequest) (*model.AnswerSummaryResponse, error) {
+	if err := validateSummaryRequest(req); err != nil {
+		return nil, err
+	}
+
+	answers, err := s.questionAnswers(ctx, req.Answers, req.SurveyID, req.QuestionID, maxSummaryAnswers)
+	if err != nil {
+		return nil, err
+	}
+	if len(answers) == 0 {
+		return nil, &ValidationError{Message: "no answers to summarize; supply answers or store answer text for the question"}
+	}
+
+	clientID := auth.ClientID(ctx)
+	caps := budgetCaps(ctx)
+
+	if err := s.checkSummaryBudget(clientID, caps); err != nil {
+		return nil, err
+	}
+
+	tally := newLabelTally()
+	texts := make([]string, len(answers))
+	for i, answer := range answers {
+		sentiment, schemeID := answer.Sentiment, answer.LabelScheme
+		if sentiment == "" {
+			sentiment, schemeID = s.lexicon.Analyze(answer.TextJawaban).Sentiment, labels.DefaultSchemeID
+		}
+		tally.add(storedScheme(s.labelSchemes, schemeID), sentiment, 1)
+		texts[i] = answer.TextJawaban
+	}
+
+	chunks := chunkAnswers(texts, s.chunkChars())
+	usage := &model.TokenUsage{}
+
+	partials, modelName, err := s.mapSummaries(ctx, clientID, caps, len(chunks), usage, func(ctx context.Context, i int) (*client.SummaryResult, error) {
+		return s.llmClient.SummarizeAnswers(ctx, req.TextPertanyaan, chunks[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge partial summaries in groups that fit one prompt until one remains
+	for len(partials) > 1 {
+		// Long reductions can cross a budget limit between rounds
+		if err := s.checkSummaryBudget(clientID, caps); err != nil {
+			return nil, err
+		}
+
+		groups := groupSummaries(partials, s.chunkChars())
+		partials, _, err = s.mapSummaries(ctx, clientID, caps, len(groups), usage, func(ctx context.Context, i int) (*client.SummaryResult, error) {
+			if len(groups[i]) == 1 {
+				return &client.SummaryResult{AnswerSummary: groups[i][0]}, nil
+			}
+			return s.llmClient.MergeSummaries(ctx, req.TextPertanyaan, groups[i])
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logger.LogInfo("Answer summary completed", logrus.Fields{
+		"survey_id":   req.SurveyID,
+		"question_id": req.QuestionID,
+		"answers":     len(answers),
+		"chunks":      len(chunks),
+		"tokens":      usage.TotalTokens,
+	})
+
+	return &model.AnswerSummaryResponse{
+		SurveyID:           req.SurveyID,
+		QuestionID:         req.QuestionID,
+		TotalAnswers:       len(answers),
+		Summary:            partials[0].Summary,
+		PositivePoints:     nonNil(partials[0].PositivePoints),
+		NegativePoints:     nonNil(partials[0].NegativePoints),
+		SentimentBreakdown: tally.breakdown(),
+		Chunks:             len(chunks),
+		Model:              modelName,
+		Usage:              usage,
+	}, nil
+}
+
+// checkSummaryBudget checks the client's spend before summary calls.
+// Summaries have no offline engine, so a downgrade is a rejection.
+func (s *SentimentService) checkSummaryBudget(clientID string, caps budget.Caps) error {
+	useFallback, err := s.checkBudget(clientID, caps)
+	if err != nil {
+		return err
+	}
+	if useFallback {
+		return budget.ErrBudgetExceeded
+	}
+	return nil
+}
+
+// mapSummaries runs n summary calls with bounded concurrency. The usage of
+// each call, failed ones included, is recorded as soon as it returns and added
+// to usage. The first failure cancels the calls that have not finished.
+func (s *SentimentService) mapSummaries(ctx context.Context, clientID string, caps budget.Caps, n int, usage *model.TokenUsage, call func(ctx context.Context, i int) (*client.SummaryResult, error)) ([]client.AnswerSummary, string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*client.SummaryResult, n)
+
+	var mu sync.Mutex
+	var firstErr error
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, summaryWorkers)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+			if ctx.Err() != nil {
+				return
+			}
+
+			result, err := call(ctx, i)
+			if result != nil && result.Usage != nil {
+				s.recordUsage(clientID, result.Model, result.Usage, caps)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if result != nil && result.Usage != nil {
+				usage.PromptTokens += result.Usage.PromptTokens
+				usage.CompletionTokens += result.Usage.CompletionTokens
+				usage.TotalTokens += result.Usage.TotalTokens
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, "", firstErr
+	}
+	// Only reachable when the caller's context ended before any call failed
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	modelName := ""
+	summaries := make([]client.AnswerSummary, n)
+	for i, result := range results {
+		summaries[i] = result.AnswerSummary
+		if result.Model != "" {
+			modelName = result.Model
+		}
+	}
+
+	return summaries, modelName, nil
+}
+
+// chunkChars returns the configured chunk size, never below minSummaryChunkChars
+func (s *SentimentService) chunkChars() int {
+	if s.summaryChunk < minSummaryChunkChars {
+		return minSummaryChunkChars
+	}
+	return s.summaryChunk
+}
+
+// chunkAnswers splits answers into chunks of at most maxChars characters,
+// truncating overly long answers
+func chunkAnswers(answers []string, maxChars int) [][]string {
+	var chunks [][]string
+	var current []string
+	size := 0
+
+	for _, answer := range answers {
+		answer = strings.TrimSpace(answer)
+		if runes := []rune(answer); len(runes) > maxSummaryAnswerLength {
+			answer = string(runes[:maxSummaryAnswerLength]) + "..."
+		}
+
+		if len(current) > 0 && size+len(answer) > maxChars {
+			chunks = append(chunks, current)
+			current, size = nil, 0
+		}
+		current = append(current, answer)
+		size += len(answer)
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// groupSummaries splits partial summaries into groups of at most maxChars
+// characters with at least two summaries each, so every reduce round shrinks
+func groupSummaries(summaries []client.AnswerSummary, maxChars int) [][]client.AnswerSummary {
+	var groups [][]client.AnswerSummary
+	var current []client.AnswerSummary
+	size := 0
+
+	for _, summary := range summaries {
+		length := len(summary.Summary)
+		for _, point := range summary.PositivePoints {
+			length += len(point)
+		}
+		for _, point := range summary.NegativePoints {
+			length += len(point)
+		}
+
+		if len(current) >= 2 && size+length > maxChars {
+			groups = append(groups, current)
+			current, size = nil, 0
+		}
+		current = append(current, summary)
+		size += length
+	}
+
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// nonNil returns an empty slice instead of nil so JSON renders []
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// validateSummaryRequest validates an answer summary request
+func validateSummaryRequest(req *model.AnswerSummaryRequest) error {
+	if len(req.Answers) > maxSummaryAnswers {
+		return &ValidationError{Message: "answers cannot contain more than " + strconv.Itoa(maxSummaryAnswers) + " items"}
+	}
+
+	if len(req.Answers) == 0 && (req.SurveyID == "" || req.QuestionID == "") {
+		return &ValidationError{Message: "answers or survey_id and question_id are required"}
+	}
+
+	return nil
+}
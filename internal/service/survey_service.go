@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/language"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxSurveyAnswers bounds the number of answers in one submission
+const maxSurveyAnswers = 50
+
+// surveyWorkers bounds how many answers of one submission are analyzed concurrently
+const surveyWorkers = 4
+
+// AnalyzeSurvey analyzes every answer of a survey submission and derives the
+// respondent's overall sentiment. With cross-question context enabled all answers
+// are sent in one LLM call; if that call cannot be parsed the answers are analyzed
+// one by one instead.
+func (s *SentimentService) AnalyzeSurvey(ctx context.Context, submission *model.SurveySubmission) (*model.SurveySubmissionResponse, error) {
+	logger.LogInfo("Starting survey analysis", logrus.Fields{
+		"survey_id":     submission.SurveyID,
+		"respondent_id": submission.RespondentID,
+		"answers":       len(submission.Answers),
+	})
+
+	if err := s.validateSubmission(submission); err != nil {
+		logger.LogError("Survey validation failed", logrus.Fields{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	scheme, err := s.surveyScheme(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission.CrossQuestionContext != nil && *submission.CrossQuestionContext {
+		useFallback, err := s.checkBudget(auth.ClientID(ctx), budgetCaps(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		if !useFallback {
+			response, err := s.analyzeSurveyInOneCall(ctx, submission, scheme)
+			if err == nil {
+				return response, nil
+			}
+
+			var parseErr *surveyParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+
+			logger.LogWarn("Cross-question analysis failed, analyzing answers individually", logrus.Fields{
+				"survey_id": submission.SurveyID,
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	return s.analyzeSurveyPerQuestion(ctx, submission, scheme)
+}
+
+// surveyScheme resolves the label scheme of a submission the way single
+// analyses do. Every answer uses it so the overall sentiment can combine
+// them; with automatic label language the language of all answers decides.
+func (s *SentimentService) surveyScheme(ctx context.Context, submission *model.SurveySubmission) (*labels.Scheme, error) {
+	labelLanguage := submission.LabelLanguage
+	if labelLanguage == "" {
+		labelLanguage = s.labelLanguage
+	}
+	if labelLanguage == "auto" {
+		texts := make([]string, len(submission.Answers))
+		for i, answer := range submission.Answers {
+			texts[i] = answer.TextJawaban
+		}
+		labelLanguage = autoLabelLanguage(language.Detect(strings.Join(texts, "\n")).Language)
+	}
+
+	return s.resolveScheme(ctx, submission.LabelScheme, labelLanguage)
+}
+
+// surveyParseError reports an unusable cross-question LLM response
+type surveyParseError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *surveyParseError) Error() string {
+	return e.err.Error()
+}
+
+// analyzeSurveyInOneCall analyzes all answers with a single cross-question LLM call
+func (s *SentimentService) analyzeSurveyInOneCall(ctx context.Context, submission *model.SurveySubmission, scheme *labels.Scheme) (*model.SurveySubmissionResponse, error) {
+	clientID := auth.ClientID(ctx)
+	requestReasoning := submission.Reasoning != nil && *submission.Reasoning
+
+	start := time.Now()
+	result, err := s.llmClient.AnalyzeSurvey(ctx, submission.Answers, requestReasoning, scheme)
+	latency := time.Since(start)
+
+	if err != nil {
+		if errors.Is(err, client.ErrInvalidResponse) {
+			return nil, &surveyParseError{err: err}
+		}
+		return nil, err
+	}
+
+	s.recordUsage(clientID, result.Model, result.Usage, budgetCaps(ctx))
+
+	response := &model.SurveySubmissionResponse{
+		SurveyID:           submission.SurveyID,
+		RespondentID:       submission.RespondentID,
+		RespondentMetadata: submission.Metadata,
+		Questions:          make([]model.QuestionSentiment, 0, len(submission.Answers)),
+		OverallSentiment:   result.OverallSentiment,
+		OverallReasoning:   result.OverallReasoning,
+		Metadata: &model.AnalysisMetadata{
+			Model:       result.Model,
+			LatencyMs:   latency.Milliseconds(),
+			Usage:       result.Usage,
+			LabelScheme: scheme.ID,
+		},
+	}
+
+	for _, answer := range submission.Answers {
+		if _, ok := result.Questions[answer.QuestionID]; !ok {
+			return nil, &surveyParseError{err: fmt.Errorf("LLM response is missing question %q", answer.QuestionID)}
+		}
+	}
+
+	for _, answer := range submission.Answers {
+		questionResult := result.Questions[answer.QuestionID]
+		questionResult.Model = result.Model
+		questionResult.PromptVersion = result.PromptVersion
+
+		response.Questions = append(response.Questions, model.QuestionSentiment{
+			QuestionID: answer.QuestionID,
+			Sentiment:  questionResult.Sentiment,
+			Reasoning:  questionResult.Reasoning,
+		})
+
+		s.saveResult(ctx, clientID, surveyRequest(submission, answer, scheme.ID), scheme, &questionResult, latency)
+	}
+
+	logger.LogInfo("Survey analysis completed", logrus.Fields{
+		"survey_id":         submission.SurveyID,
+		"overall_sentiment": response.OverallSentiment,
+		"mode":              "cross_question",
+		"latency_ms":        latency.Milliseconds(),
+	})
+
+	return response, nil
+}
+
+// analyzeSurveyPerQuestion analyzes each answer independently
+func (s *SentimentService) analyzeSurveyPerQuestion(ctx context.Context, submission *model.SurveySubmission, scheme *labels.Scheme) (*model.SurveySubmissionResponse, error) {
+	start := time.Now()
+	results := make([]*model.SentimentResponse, len(submission.Answers))
+	errs := make([]error, len(submission.Answers))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, surveyWorkers)
+
+	for i, answer := range submission.Answers {
+		wg.Add(1)
+		go func(i int, answer model.SurveyAnswer) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i], errs[i] = s.AnalyzeSentiment(ctx, surveyRequest(submission, answer, scheme.ID))
+		}(i, answer)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := &model.SurveySubmissionResponse{
+		SurveyID:           submission.SurveyID,
+		RespondentID:       submission.RespondentID,
+		RespondentMetadata: submission.Metadata,
+		Questions:          make([]model.QuestionSentiment, 0, len(submission.Answers)),
+		Metadata:           &model.AnalysisMetadata{LabelScheme: scheme.ID},
+	}
+
+	usage := &model.TokenUsage{}
+	sentiments := make([]string, 0, len(results))
+	for i, result := range results {
+		response.Questions = append(response.Questions, model.QuestionSentiment{
+			QuestionID: submission.Answers[i].QuestionID,
+			Sentiment:  result.Sentiment,
+			Reasoning:  result.Reasoning,
+		})
+		sentiments = append(sentiments, result.Sentiment)
+
+		if result.Metadata != nil {
+			response.Metadata.Model = result.Metadata.Model
+			if result.Metadata.Usage != nil {
+				usage.PromptTokens += result.Metadata.Usage.PromptTokens
+				usage.CompletionTokens += result.Metadata.Usage.CompletionTokens
+				usage.TotalTokens += result.Metadata.Usage.TotalTokens
+			}
+		}
+	}
+
+	response.OverallSentiment = overallSentiment(scheme, sentiments)
+	response.Metadata.LatencyMs = time.Since(start).Milliseconds()
+	response.Metadata.Usage = usage
+
+	logger.LogInfo("Survey analysis completed", logrus.Fields{
+		"survey_id":         submission.SurveyID,
+		"overall_sentiment": response.OverallSentiment,
+		"mode":              "per_question",
+		"latency_ms":        response.Metadata.LatencyMs,
+	})
+
+	return response, nil
+}
+
+// validateSubmission validates a survey submission and each of its answers
+func (s *SentimentService) validateSubmission(submission *model.SurveySubmission) error {
+	if len(submission.Answers) == 0 {
+		return &ValidationError{Message: "answers cannot be empty"}
+	}
+
+	if len(submission.Answers) > maxSurveyAnswers {
+		return &ValidationError{Message: fmt.Sprintf("answers exceeds maximum of %d entries", maxSurveyAnswers)}
+	}
+
+	seen := make(map[string]bool, len(submission.Answers))
+	for _, answer := range submission.Answers {
+		if seen[answer.QuestionID] {
+			return &ValidationError{Message: fmt.Sprintf("duplicate question_id %q", answer.QuestionID)}
+		}
+		seen[answer.QuestionID] = true
+
+		if err := s.validateRequest(surveyRequest(submission, answer, submission.LabelScheme)); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("question %s: %s", answer.QuestionID, err.Error())}
+		}
+	}
+
+	return nil
+}
+
+// surveyRequest converts one answer of a submission into a single analysis
+// request labelled with the submission's scheme
+func surveyRequest(submission *model.SurveySubmission, answer model.SurveyAnswer, schemeID string) *model.SentimentRequest {
+	includeMetadata := true
+	return &model.SentimentRequest{
+		TextPertanyaan:  answer.TextPertanyaan,
+		TextJawaban:     answer.TextJawaban,
+		Reasoning:       submission.Reasoning,
+		IncludeMetadata: &includeMetadata,
+		SurveyID:        submission.SurveyID,
+		QuestionID:      answer.QuestionID,
+		RespondentID:    submission.RespondentID,
+		LabelScheme:     schemeID,
+		LabelLanguage:   submission.LabelLanguage,
+	}
+}
+
+// overallSentiment derives a respondent's overall label from the polarity of
+// the per-question labels: the scheme's label for the sign of their sum
+func overallSentiment(scheme *labels.Scheme, sentiments []string) string {
+	score := 0
+	for _, sentiment := range sentiments {
+		switch polarity := scheme.Polarity(sentiment); {
+		case polarity > 0:
+			score++
+		case polarity < 0:
+			score--
+		}
+	}
+
+	switch {
+	case score > 0:
+		return scheme.ForPolarity(1)
+	case score < 0:
+		return scheme.ForPolarity(-1)
+	default:
+		return scheme.ForPolarity(0)
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sentiment-api/internal/auth"
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/labels"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/storage"
+	"sentiment-api/internal/topics"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMaxTopics is used when a topic request does not set max_topics
+	defaultMaxTopics = 8
+	// maxTopicsLimit bounds max_topics
+	maxTopicsLimit = 20
+	// maxTopicAnswers bounds the number of answers clustered in one request
+	maxTopicAnswers = 500
+	// maxLLMTopicAnswers is the largest batch sent to the LLM in one prompt;
+	// larger batches are clustered locally
+	maxLLMTopicAnswers = 200
+	// topicExamples is the number of example answers returned per theme
+	topicExamples = 3
+)
+
+// AnalyzeTopics groups a batch of answers into themes, each with its size,
+// example answers and sentiment distribution. The LLM engine falls back to
+// local keyword clustering when it cannot be used or its response is unusable.
+func (s *SentimentService) AnalyzeTopics(ctx context.Context, req *model.TopicRequest) (*model.TopicResponse, error) {
+	if err := validateTopicRequest(req); err != nil {
+		return nil, err
+	}
+
+	answers, err := s.questionAnswers(ctx, req.Answers, req.SurveyID, req.QuestionID, maxTopicAnswers)
+	if err != nil {
+		return nil, err
+	}
+	if len(answers) == 0 {
+		return nil, &ValidationError{Message: "no answers to cluster; supply answers or store answer text for the question"}
+	}
+
+	maxTopics := req.MaxTopics
+	if maxTopics == 0 {
+		maxTopics = defaultMaxTopics
+	}
+
+	texts := make([]string, len(answers))
+	for i, answer := range answers {
+		texts[i] = answer.TextJawaban
+		if answer.Sentiment == "" {
+			answers[i].Sentiment = s.lexicon.Analyze(answer.TextJawaban).Sentiment
+			answers[i].LabelScheme = labels.DefaultSchemeID
+		}
+	}
+
+	response := &model.TopicResponse{
+		SurveyID:     req.SurveyID,
+		QuestionID:   req.QuestionID,
+		Engine:       "local",
+		TotalAnswers: len(answers),
+	}
+
+	var clusters []topics.Cluster
+	if req.Engine == "" || req.Engine == "llm" {
+		clusters = s.clusterTopicsWithLLM(ctx, req.TextPertanyaan, texts, maxTopics, response)
+	}
+	if clusters == nil {
+		clusters = topics.KeywordClusters(texts, maxTopics)
+	}
+
+	response.Topics = buildTopics(clusters, answers, s.labelSchemes)
+
+	logger.LogInfo("Topic analysis completed", logrus.Fields{
+		"survey_id":   req.SurveyID,
+		"question_id": req.QuestionID,
+		"answers":     len(answers),
+		"topics":      len(response.Topics),
+		"engine":      response.Engine,
+	})
+
+	return response, nil
+}
+
+// clusterTopicsWithLLM labels themes with the LLM and records the engine,
+// model and usage on response. It returns nil when the local engine must be used.
+func (s *SentimentService) clusterTopicsWithLLM(ctx context.Context, textPertanyaan string, texts []string, maxTopics int, response *model.TopicResponse) []topics.Cluster {
+	if len(texts) > maxLLMTopicAnswers {
+		logger.LogInfo("Too many answers for one topic prompt, clustering locally", logrus.Fields{
+			"answers": len(texts),
+		})
+		return nil
+	}
+
+	clientID := auth.ClientID(ctx)
+	caps := budgetCaps(ctx)
+
+	useFallback, err := s.checkBudget(clientID, caps)
+	if err != nil || useFallback {
+		return nil
+	}
+
+	result, err := s.llmClient.ClusterTopics(ctx, textPertanyaan, texts, maxTopics)
+	if err != nil {
+		logger.LogWarn("LLM topic clustering failed, clustering locally", logrus.Fields{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	s.recordUsage(clientID, result.Model, result.Usage, caps)
+	response.Engine = "llm"
+	response.Model = result.Model
+	response.Usage = result.Usage
+
+	return capClusters(result.Topics, len(texts), maxTopics)
+}
+
+// capClusters keeps the maxTopics largest LLM themes and merges the smaller
+// ones, a theme the model itself named Lainnya and unassigned answers into
+// one OtherLabel theme
+func capClusters(llmTopics []client.TopicCluster, answerCount, maxTopics int) []topics.Cluster {
+	ranked := make([]client.TopicCluster, 0, len(llmTopics))
+	for _, topic := range llmTopics {
+		if !strings.EqualFold(topic.Label, topics.OtherLabel) {
+			ranked = append(ranked, topic)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return len(ranked[i].Members) > len(ranked[j].Members)
+	})
+	if len(ranked) > maxTopics {
+		ranked = ranked[:maxTopics]
+	}
+
+	assigned := make([]bool, answerCount)
+	clusters := make([]topics.Cluster, 0, len(ranked)+1)
+	for _, topic := range ranked {
+		for _, member := range topic.Members {
+			assigned[member] = true
+		}
+		clusters = append(clusters, topics.Cluster{Label: topic.Label, Members: topic.Members})
+	}
+
+	other := topics.Cluster{Label: topics.OtherLabel}
+	for i := 0; i < answerCount; i++ {
+		if !assigned[i] {
+			other.Members = append(other.Members, i)
+		}
+	}
+	if len(other.Members) > 0 {
+		clusters = append(clusters, other)
+	}
+
+	return clusters
+}
+
+// questionAnswers returns the supplied answers, or up to limit stored
+// answers of the survey question visible to the caller
+func (s *SentimentService) questionAnswers(ctx context.Context, supplied []model.TopicAnswer, surveyID, questionID string, limit int) ([]model.TopicAnswer, error) {
+	if len(supplied) > 0 {
+		return supplied, nil
+	}
+	if s.repository == nil {
+		return nil, storage.ErrDisabled
+	}
+
+	// Read in pages no larger than the list endpoint allows
+	filter := storage.AnalysisFilter{
+		SurveyID:   surveyID,
+		QuestionID: questionID,
+		Limit:      limit,
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.HasScope(auth.ScopeAdmin) {
+		filter.ClientID = principal.ID
+	}
+
+	var answers []model.TopicAnswer
+	for len(answers) < limit {
+		page, err := s.repository.Query(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range page.Items {
+			if record.TextJawaban == nil || len(answers) >= limit {
+				continue
+			}
+			id := record.RespondentID
+			if id == "" {
+				id = strconv.FormatInt(record.ID, 10)
+			}
+			answers = append(answers, model.TopicAnswer{
+				ID:          id,
+				TextJawaban: *record.TextJawaban,
+				Sentiment:   record.Sentiment,
+				LabelScheme: record.LabelScheme,
+			})
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	return answers, nil
+}
+
+// buildTopics turns clusters into themes ordered by size, with Lainnya last.
+// Sentiments are scored with the scheme each answer names.
+func buildTopics(clusters []topics.Cluster, answers []model.TopicAnswer, schemes *labels.Registry) []model.Topic {
+	result := make([]model.Topic, 0, len(clusters))
+	for _, cluster := range clusters {
+		topic := model.Topic{
+			Label:    cluster.Label,
+			Keywords: cluster.Keywords,
+			Size:     len(cluster.Members),
+			Share:    roundPercentage(float64(len(cluster.Members)) / float64(len(answers)) * 100),
+			Examples: make([]string, 0, topicExamples),
+		}
+
+		tally := newLabelTally()
+		for _, member := range cluster.Members {
+			answer := answers[member]
+			tally.add(storedScheme(schemes, answer.LabelScheme), answer.Sentiment, 1)
+			if answer.ID != "" {
+				topic.AnswerIDs = append(topic.AnswerIDs, answer.ID)
+			}
+			if len(topic.Examples) < topicExamples {
+				topic.Examples = append(topic.Examples, answer.TextJawaban)
+			}
+		}
+		topic.SentimentBreakdown = tally.breakdown()
+
+		result = append(result, topic)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if (result[i].Label == topics.OtherLabel) != (result[j].Label == topics.OtherLabel) {
+			return result[j].Label == topics.OtherLabel
+		}
+		return result[i].Size > result[j].Size
+	})
+
+	return result
+}
+
+// validateTopicRequest validates a topic clustering request
+func validateTopicRequest(req *model.TopicRequest) error {
+	switch req.Engine {
+	case "", "llm", "local":
+	default:
+		return &ValidationError{Message: "engine must be llm or local"}
+	}
+
+	if req.MaxTopics < 0 || req.MaxTopics > maxTopicsLimit {
+		return &ValidationError{Message: "max_topics must be between 1 and " + strconv.Itoa(maxTopicsLimit)}
+	}
+
+	if len(req.Answers) > maxTopicAnswers {
+		return &ValidationError{Message: "answers cannot contain more than " + strconv.Itoa(maxTopicAnswers) + " items"}
+	}
+
+	if len(req.Answers) == 0 && (req.SurveyID == "" || req.QuestionID == "") {
+		return &ValidationError{Message: "answers or survey_id and question_id are required"}
+	}
+
+	for _, answer := range req.Answers {
+		if len(answer.TextJawaban) > 2000 {
+			return &ValidationError{Message: "text_jawaban exceeds maximum length of 2000 characters"}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/lexicon"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/topics"
+)
+
+func TestCapClusters(t *testing.T) {
+	tests := []struct {
+		name        string
+		llmTopics   []client.TopicCluster
+		answerCount int
+		maxTopics   int
+		want        []topics.Cluster
+	}{
+		{
+			name: "largest themes first",
+			llmTopics: []client.TopicCluster{
+				{Label: "Harga", Members: []int{0}},
+				{Label: "Pengiriman", Members: []int{1, 2, 3}},
+			},
+			answerCount: 4,
+			maxTopics:   8,
+			want: []topics.Cluster{
+				{Label: "Pengiriman", Members: []int{1, 2, 3}},
+				{Label: "Harga", Members: []int{0}},
+			},
+		},
+		{
+			name: "unassigned answers go to Lainnya",
+			llmTopics: []client.TopicCluster{
+				{Label: "Pengiriman", Members: []int{0, 2}},
+			},
+			answerCount: 4,
+			maxTopics:   8,
+			want: []topics.Cluster{
+				{Label: "Pengiriman", Members: []int{0, 2}},
+				{Label: topics.OtherLabel, Members: []int{1, 3}},
+			},
+		},
+		{
+			name: "themes beyond max_topics merge into Lainnya",
+			llmTopics: []client.TopicCluster{
+				{Label: "Harga", Members: []int{4}},
+				{Label: "Pengiriman", Members: []int{0, 1, 2}},
+				{Label: "Aplikasi", Members: []int{3, 5}},
+				{Label: "Kemasan", Members: []int{6}},
+			},
+			answerCount: 7,
+			maxTopics:   2,
+			want: []topics.Cluster{
+				{Label: "Pengiriman", Members: []int{0, 1, 2}},
+				{Label: "Aplikasi", Members: []int{3, 5}},
+				{Label: topics.OtherLabel, Members: []int{4, 6}},
+			},
+		},
+		{
+			name: "equal sizes keep the model's order",
+			llmTopics: []client.TopicCluster{
+				{Label: "Harga", Members: []int{0, 1}},
+				{Label: "Pengiriman", Members: []int{2, 3}},
+				{Label: "Aplikasi", Members: []int{4, 5}},
+			},
+			answerCount: 6,
+			maxTopics:   2,
+			want: []topics.Cluster{
+				{Label: "Harga", Members: []int{0, 1}},
+				{Label: "Pengiriman", Members: []int{2, 3}},
+				{Label: topics.OtherLabel, Members: []int{4, 5}},
+			},
+		},
+		{
+			name: "the model's own Lainnya does not use a slot",
+			llmTopics: []client.TopicCluster{
+				{Label: "lainnya", Members: []int{0, 1, 2}},
+				{Label: "Harga", Members: []int{3}},
+			},
+			answerCount: 5,
+			maxTopics:   1,
+			want: []topics.Cluster{
+				{Label: "Harga", Members: []int{3}},
+				{Label: topics.OtherLabel, Members: []int{0, 1, 2, 4}},
+			},
+		},
+		{
+			name: "no Lainnya when every answer has a theme",
+			llmTopics: []client.TopicCluster{
+				{Label: "Harga", Members: []int{0, 1}},
+			},
+			answerCount: 2,
+			maxTopics:   8,
+			want:        []topics.Cluster{{Label: "Harga", Members: []int{0, 1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := capClusters(tt.llmTopics, tt.answerCount, tt.maxTopics)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("capClusters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTopicsLocal(t *testing.T) {
+	s := &SentimentService{lexicon: lexicon.NewAnalyzer()}
+	answers := []model.TopicAnswer{
+		{ID: "R-1", TextJawaban: "Pengiriman lambat sekali"},
+		{ID: "R-2", TextJawaban: "Pengiriman barang lambat"},
+		{ID: "R-3", TextJawaban: "pengiriman cepat"},
+		{ID: "R-4", TextJawaban: "Harga terlalu mahal"},
+		{ID: "R-5", TextJawaban: "harga mahal untuk kualitasnya"},
+		{ID: "R-6", TextJawaban: "Aplikasi sering error"},
+	}
+
+	tests := []struct {
+		name       string
+		maxTopics  int
+		wantLabels []string
+		wantSizes  []int
+	}{
+		{name: "default max_topics", wantLabels: []string{"pengiriman lambat", "harga mahal", topics.OtherLabel}, wantSizes: []int{3, 2, 1}},
+		{name: "max_topics 1", maxTopics: 1, wantLabels: []string{"pengiriman lambat", topics.OtherLabel}, wantSizes: []int{3, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := s.AnalyzeTopics(context.Background(), &model.TopicRequest{
+				Answers:   answers,
+				MaxTopics: tt.maxTopics,
+				Engine:    "local",
+			})
+			if err != nil {
+				t.Fatalf("AnalyzeTopics() error = %v", err)
+			}
+			if response.Engine != "local" || response.TotalAnswers != len(answers) {
+				t.Errorf("Engine = %q, TotalAnswers = %d, want local and %d", response.Engine, response.TotalAnswers, len(answers))
+			}
+
+			var labels []string
+			var sizes []int
+			for _, topic := range response.Topics {
+				labels = append(labels, topic.Label)
+				sizes = append(sizes, topic.Size)
+			}
+			if !reflect.DeepEqual(labels, tt.wantLabels) || !reflect.DeepEqual(sizes, tt.wantSizes) {
+				t.Errorf("topics = %v %v, want %v %v", labels, sizes, tt.wantLabels, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTopicsMaxTopicsValidation(t *testing.T) {
+	s := &SentimentService{lexicon: lexicon.NewAnalyzer()}
+	answers := []model.TopicAnswer{{TextJawaban: "Pengiriman lambat"}}
+
+	for _, maxTopics := range []int{-1, maxTopicsLimit + 1} {
+		_, err := s.AnalyzeTopics(context.Background(), &model.TopicRequest{Answers: answers, MaxTopics: maxTopics, Engine: "local"})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("AnalyzeTopics(max_topics %d) error = %v, want a ValidationError", maxTopics, err)
+		}
+	}
+}
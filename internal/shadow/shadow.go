@@ -0,0 +1,247 @@
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/budget"
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/config"
+	"sentiment-api/internal/engine"
+	"sentiment-api/internal/lexicon"
+	"sentiment-api/internal/limiter"
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/provider"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// callTimeout bounds one shadow call, which no client is waiting for
+const callTimeout = 60 * time.Second
+
+// Primary is the served result a shadow run is compared against
+type Primary struct {
+	Sentiment string
+	Model     string
+	Usage     *model.TokenUsage
+	Latency   time.Duration
+}
+
+// Runner sends a sample of requests to a candidate engine in the background
+// and aggregates how the candidate compares with the served results.
+// Shadow calls never change a response, are not charged to clients and are
+// not reported to the provider chain's health tracking.
+type Runner struct {
+	candidate  engine.Engine
+	provider   string
+	sampleRate float64
+	slots      chan struct{}
+	costs      *budget.Manager
+
+	mu     sync.Mutex
+	stats  stats
+	random *rand.Rand
+}
+
+// stats holds the running totals of a runner
+type stats struct {
+	since          time.Time
+	samples        int64
+	errors         int64
+	dropped        int64
+	agreements     int64
+	primaryLatency time.Duration
+	shadowLatency  time.Duration
+	primaryTokens  int64
+	shadowTokens   int64
+	primaryCost    float64
+	shadowCost     float64
+	transitions    map[[2]string]int64
+}
+
+// NewRunner creates a shadow runner from the shadow configuration.
+// The budget manager is optional and only used to price token usage.
+func NewRunner(cfg config.ShadowConfig, llmClient *client.LLMClient, analyzer *lexicon.Analyzer, costs *budget.Manager) (*Runner, error) {
+	parsed, err := engine.Parse(cfg.Engine, llmClient, analyzer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow engine: %w", err)
+	}
+
+	candidate, err := engine.WithPrompt(parsed, cfg.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow prompt: %w", err)
+	}
+
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("shadow sample rate must be between 0 and 1")
+	}
+
+	if cfg.Provider != "" {
+		p, ok := llmClient.Providers().Get(cfg.Provider)
+		if !ok {
+			return nil, fmt.Errorf("unknown shadow provider %q", cfg.Provider)
+		}
+		if _, isLLM := parsed.(*engine.LLMEngine); !isLLM || p.Type != provider.TypeLLM {
+			return nil, fmt.Errorf("shadow provider %q needs an LLM engine and LLM provider", cfg.Provider)
+		}
+	}
+
+	maxInFlight := cfg.MaxInFlight
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+
+	return &Runner{
+		candidate:  candidate,
+		provider:   cfg.Provider,
+		sampleRate: cfg.SampleRate,
+		slots:      make(chan struct{}, maxInFlight),
+		costs:      costs,
+		stats:      stats{since: time.Now().UTC(), transitions: make(map[[2]string]int64)},
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Candidate describes the candidate engine, prompt and provider
+func (r *Runner) Candidate() string {
+	if r.provider != "" {
+		return r.candidate.Name() + "@" + r.provider
+	}
+	return r.candidate.Name()
+}
+
+// Sampled reports whether the current request should be shadowed
+func (r *Runner) Sampled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.sampleRate > 0 && r.random.Float64() < r.sampleRate
+}
+
+// Run analyzes the answer with the candidate in the background and records
+// the comparison. Requests are dropped when too many shadow calls are in flight.
+func (r *Runner) Run(textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool, primary Primary) {
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		r.mu.Lock()
+		r.stats.dropped++
+		r.mu.Unlock()
+		return
+	}
+
+	go func() {
+		defer func() { <-r.slots }()
+
+		ctx, cancel := context.WithTimeout(limiter.WithPriority(context.Background(), limiter.PriorityBatch), callTimeout)
+		defer cancel()
+
+		opts.Provider = r.provider
+		opts.Shadow = true
+
+		start := time.Now()
+		result, err := r.candidate.Analyze(ctx, textPertanyaan, textJawaban, opts, withReasoning)
+		latency := time.Since(start)
+
+		if err != nil {
+			logger.LogWarn("Shadow analysis failed", logrus.Fields{
+				"candidate": r.Candidate(),
+				"error":     err.Error(),
+			})
+			r.mu.Lock()
+			r.stats.errors++
+			r.mu.Unlock()
+			return
+		}
+
+		r.record(primary, result, latency)
+	}()
+}
+
+// record adds one comparison to the running totals
+func (r *Runner) record(primary Primary, result *client.SentimentResult, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.samples++
+	if result.Sentiment == primary.Sentiment {
+		r.stats.agreements++
+	} else {
+		r.stats.transitions[[2]string{primary.Sentiment, result.Sentiment}]++
+	}
+
+	r.stats.primaryLatency += primary.Latency
+	r.stats.shadowLatency += latency
+
+	if primary.Usage != nil {
+		r.stats.primaryTokens += int64(primary.Usage.TotalTokens)
+		r.stats.primaryCost += r.cost(primary.Model, *primary.Usage)
+	}
+	if result.Usage != nil {
+		r.stats.shadowTokens += int64(result.Usage.TotalTokens)
+		r.stats.shadowCost += r.cost(result.Model, *result.Usage)
+	}
+}
+
+// cost prices token usage, or returns 0 without a budget manager
+func (r *Runner) cost(modelName string, usage model.TokenUsage) float64 {
+	if r.costs == nil {
+		return 0
+	}
+	return r.costs.Cost(modelName, usage)
+}
+
+// Report summarizes the comparisons recorded since the runner started
+func (r *Runner) Report() *model.ShadowReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &model.ShadowReport{
+		Candidate:     r.Candidate(),
+		SampleRate:    r.sampleRate,
+		Since:         r.stats.since,
+		Samples:       r.stats.samples,
+		Errors:        r.stats.errors,
+		Dropped:       r.stats.dropped,
+		Agreements:    r.stats.agreements,
+		PrimaryTokens: r.stats.primaryTokens,
+		ShadowTokens:  r.stats.shadowTokens,
+		PrimaryCost:   r.stats.primaryCost,
+		ShadowCost:    r.stats.shadowCost,
+		CostDiff:      r.stats.shadowCost - r.stats.primaryCost,
+		Disagreements: make([]model.ShadowDisagreement, 0, len(r.stats.transitions)),
+	}
+
+	if r.stats.samples > 0 {
+		n := float64(r.stats.samples)
+		report.AgreementRate = float64(r.stats.agreements) / n
+		report.PrimaryAvgLatencyMs = float64(r.stats.primaryLatency.Milliseconds()) / n
+		report.ShadowAvgLatencyMs = float64(r.stats.shadowLatency.Milliseconds()) / n
+		report.LatencyDiffMs = report.ShadowAvgLatencyMs - report.PrimaryAvgLatencyMs
+	}
+
+	for transition, count := range r.stats.transitions {
+		report.Disagreements = append(report.Disagreements, model.ShadowDisagreement{
+			Primary: transition[0],
+			Shadow:  transition[1],
+			Count:   count,
+		})
+	}
+	sort.Slice(report.Disagreements, func(i, j int) bool {
+		a, b := report.Disagreements[i], report.Disagreements[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Primary != b.Primary {
+			return a.Primary < b.Primary
+		}
+		return a.Shadow < b.Shadow
+	})
+
+	return report
+}
@@ -0,0 +1,227 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/client"
+	"sentiment-api/internal/model"
+	"sentiment-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	os.Exit(m.Run())
+}
+
+// stubEngine labels answers from a fixed map and fails on unknown answers.
+// Calls block until release is closed when it is set.
+type stubEngine struct {
+	labels  map[string]string
+	release chan struct{}
+
+	mu   sync.Mutex
+	opts []client.AnalysisOptions
+}
+
+func (e *stubEngine) Name() string {
+	return "llm:candidate"
+}
+
+func (e *stubEngine) Analyze(ctx context.Context, textPertanyaan, textJawaban string, opts client.AnalysisOptions, withReasoning bool) (*client.SentimentResult, error) {
+	e.mu.Lock()
+	e.opts = append(e.opts, opts)
+	e.mu.Unlock()
+
+	if e.release != nil {
+		<-e.release
+	}
+
+	label, ok := e.labels[textJawaban]
+	if !ok {
+		return nil, errors.New("candidate failed")
+	}
+	return &client.SentimentResult{
+		Sentiment: label,
+		Model:     "candidate",
+		Usage:     &model.TokenUsage{TotalTokens: 20},
+	}, nil
+}
+
+// newTestRunner creates a runner around candidate
+func newTestRunner(candidate *stubEngine, sampleRate float64, maxInFlight int) *Runner {
+	return &Runner{
+		candidate:  candidate,
+		sampleRate: sampleRate,
+		slots:      make(chan struct{}, maxInFlight),
+		stats:      stats{since: time.Now().UTC(), transitions: make(map[[2]string]int64)},
+		random:     rand.New(rand.NewSource(1)),
+	}
+}
+
+// waitForCalls waits until the runner finished n shadow calls
+func waitForCalls(t *testing.T, runner *Runner, n int64) *model.ShadowReport {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		report := runner.Report()
+		if report.Samples+report.Errors >= n {
+			return report
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("finished %d shadow calls, want %d", report.Samples+report.Errors, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunnerSampled(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampleRate float64
+		wantMin    int
+		wantMax    int
+	}{
+		{name: "disabled", sampleRate: 0, wantMin: 0, wantMax: 0},
+		{name: "every request", sampleRate: 1, wantMin: 10000, wantMax: 10000},
+		{name: "a quarter", sampleRate: 0.25, wantMin: 2300, wantMax: 2700},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := newTestRunner(&stubEngine{}, tt.sampleRate, 1)
+
+			sampled := 0
+			for i := 0; i < 10000; i++ {
+				if runner.Sampled() {
+					sampled++
+				}
+			}
+			if sampled < tt.wantMin || sampled > tt.wantMax {
+				t.Errorf("sampled %d of 10000 requests, want %d to %d", sampled, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRunnerDropsWhenSlotsAreFull(t *testing.T) {
+	candidate := &stubEngine{labels: map[string]string{"bagus": "Positif"}, release: make(chan struct{})}
+	runner := newTestRunner(candidate, 1, 2)
+
+	for i := 0; i < 5; i++ {
+		runner.Run("", "bagus", client.AnalysisOptions{}, false, Primary{Sentiment: "Positif"})
+	}
+	if report := runner.Report(); report.Dropped != 3 || report.Samples != 0 {
+		t.Fatalf("Dropped = %d, Samples = %d while calls are in flight, want 3 and 0", report.Dropped, report.Samples)
+	}
+
+	close(candidate.release)
+	waitForCalls(t, runner, 2)
+
+	// Finished calls free their slots
+	runner.Run("", "bagus", client.AnalysisOptions{}, false, Primary{Sentiment: "Positif"})
+	report := waitForCalls(t, runner, 3)
+	if report.Samples != 3 || report.Dropped != 3 {
+		t.Errorf("Samples = %d, Dropped = %d, want 3 and 3", report.Samples, report.Dropped)
+	}
+}
+
+func TestRunnerMarksShadowCalls(t *testing.T) {
+	candidate := &stubEngine{labels: map[string]string{"bagus": "Positif"}}
+	runner := newTestRunner(candidate, 1, 1)
+	runner.provider = "backup"
+
+	runner.Run("", "bagus", client.AnalysisOptions{Provider: "primary"}, false, Primary{Sentiment: "Positif"})
+	waitForCalls(t, runner, 1)
+
+	if len(candidate.opts) != 1 || !candidate.opts[0].Shadow || candidate.opts[0].Provider != "backup" {
+		t.Errorf("candidate options = %+v, want a shadow call pinned to the backup provider", candidate.opts)
+	}
+	if got := runner.Candidate(); got != "llm:candidate@backup" {
+		t.Errorf("Candidate() = %q, want %q", got, "llm:candidate@backup")
+	}
+}
+
+func TestRunnerReport(t *testing.T) {
+	candidate := &stubEngine{labels: map[string]string{
+		"puas":        "Positif",
+		"lumayan":     "Netral",
+		"biasa":       "Netral",
+		"cukup":       "Positif",
+		"tidak jelas": "Positif",
+	}}
+	runner := newTestRunner(candidate, 0.5, 16)
+
+	runs := []struct {
+		answer  string
+		primary string
+	}{
+		{answer: "puas", primary: "Positif"},
+		{answer: "puas", primary: "Positif"},
+		{answer: "lumayan", primary: "Positif"},
+		{answer: "biasa", primary: "Negatif"},
+		{answer: "biasa", primary: "Negatif"},
+		{answer: "cukup", primary: "Netral"},
+		{answer: "tidak jelas", primary: "Negatif"},
+		{answer: "gagal", primary: "Negatif"},
+	}
+	for _, run := range runs {
+		runner.Run("", run.answer, client.AnalysisOptions{}, false, Primary{
+			Sentiment: run.primary,
+			Model:     "primary",
+			Usage:     &model.TokenUsage{TotalTokens: 10},
+			Latency:   100 * time.Millisecond,
+		})
+	}
+
+	report := waitForCalls(t, runner, int64(len(runs)))
+
+	if report.Candidate != "llm:candidate" || report.SampleRate != 0.5 {
+		t.Errorf("Candidate = %q, SampleRate = %v", report.Candidate, report.SampleRate)
+	}
+	if report.Samples != 7 || report.Errors != 1 || report.Dropped != 0 || report.Agreements != 2 {
+		t.Errorf("Samples = %d, Errors = %d, Dropped = %d, Agreements = %d, want 7, 1, 0, 2",
+			report.Samples, report.Errors, report.Dropped, report.Agreements)
+	}
+	if report.AgreementRate != 2.0/7 {
+		t.Errorf("AgreementRate = %v, want %v", report.AgreementRate, 2.0/7)
+	}
+	if report.PrimaryAvgLatencyMs != 100 {
+		t.Errorf("PrimaryAvgLatencyMs = %v, want 100", report.PrimaryAvgLatencyMs)
+	}
+	if report.PrimaryTokens != 70 || report.ShadowTokens != 140 {
+		t.Errorf("PrimaryTokens = %d, ShadowTokens = %d, want 70 and 140", report.PrimaryTokens, report.ShadowTokens)
+	}
+	if report.PrimaryCost != 0 || report.ShadowCost != 0 || report.CostDiff != 0 {
+		t.Errorf("costs = %v, %v, %v, want none without a budget manager", report.PrimaryCost, report.ShadowCost, report.CostDiff)
+	}
+
+	// Most frequent transitions first, then by primary and shadow label
+	want := []model.ShadowDisagreement{
+		{Primary: "Negatif", Shadow: "Netral", Count: 2},
+		{Primary: "Negatif", Shadow: "Positif", Count: 1},
+		{Primary: "Netral", Shadow: "Positif", Count: 1},
+		{Primary: "Positif", Shadow: "Netral", Count: 1},
+	}
+	if !reflect.DeepEqual(report.Disagreements, want) {
+		t.Errorf("Disagreements = %+v, want %+v", report.Disagreements, want)
+	}
+}
+
+func TestRunnerReportEmpty(t *testing.T) {
+	report := newTestRunner(&stubEngine{}, 0.1, 1).Report()
+
+	if report.Samples != 0 || report.AgreementRate != 0 || report.PrimaryAvgLatencyMs != 0 {
+		t.Errorf("report = %+v, want no samples", report)
+	}
+	if report.Disagreements == nil || len(report.Disagreements) != 0 {
+		t.Errorf("Disagreements = %v, want an empty list", report.Disagreements)
+	}
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// migration represents one schema change
+type migration struct {
+	version     int
+	description string
+	// requires names a SQLite compile option, without its ENABLE_ prefix,
+	// that the migration depends on; the migration is skipped while it is missing
+	requires   string
+	statements []string
+}
+
+// migrations lists every schema change in order; never edit an applied entry
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create analyses table",
+		statements: []string{
+			`CREATE TABLE analyses (
+				id              INTEGER PRIMARY KEY AUTOINCREMENT,
+				client_id       TEXT NOT NULL,
+				text_hash       TEXT NOT NULL,
+				text_pertanyaan TEXT,
+				text_jawaban    TEXT,
+				sentiment       TEXT NOT NULL,
+				reasoning       TEXT,
+				confidence      REAL,
+				model           TEXT NOT NULL,
+				prompt_version  TEXT NOT NULL,
+				latency_ms      INTEGER NOT NULL,
+				created_at      TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX idx_analyses_created_at ON analyses (created_at)`,
+			`CREATE INDEX idx_analyses_text_hash ON analyses (text_hash)`,
+		},
+	},
+	{
+		version:     2,
+		description: "add survey, question and respondent identifiers",
+		statements: []string{
+			`ALTER TABLE analyses ADD COLUMN survey_id TEXT`,
+			`ALTER TABLE analyses ADD COLUMN question_id TEXT`,
+			`ALTER TABLE analyses ADD COLUMN respondent_id TEXT`,
+			`CREATE INDEX idx_analyses_survey_question ON analyses (survey_id, question_id, created_at)`,
+		},
+	},
+	{
+		version:     3,
+		description: "add experiment variants, parse failures and feedback",
+		statements: []string{
+			`ALTER TABLE analyses ADD COLUMN experiment TEXT`,
+			`ALTER TABLE analyses ADD COLUMN variant TEXT`,
+			`ALTER TABLE analyses ADD COLUMN parse_failed INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE analyses ADD COLUMN feedback_label TEXT`,
+			`ALTER TABLE analyses ADD COLUMN feedback_at TIMESTAMP`,
+			`CREATE INDEX idx_analyses_experiment ON analyses (experiment, variant)`,
+		},
+	},
+	{
+		// Builds without the sqlite_fts5 tag of go-sqlite3 search answers with LIKE instead
+		version:     4,
+		description: "add full-text index on answers",
+		requires:    "FTS5",
+		statements: []string{
+			`CREATE VIRTUAL TABLE analyses_fts USING fts5(text_jawaban, content='analyses', content_rowid='id')`,
+			`CREATE TRIGGER analyses_fts_insert AFTER INSERT ON analyses WHEN new.text_jawaban IS NOT NULL BEGIN
+				INSERT INTO analyses_fts (rowid, text_jawaban) VALUES (new.id, new.text_jawaban);
+			END`,
+			`CREATE TRIGGER analyses_fts_delete AFTER DELETE ON analyses WHEN old.text_jawaban IS NOT NULL BEGIN
+				INSERT INTO analyses_fts (analyses_fts, rowid, text_jawaban) VALUES ('delete', old.id, old.text_jawaban);
+			END`,
+			`CREATE TRIGGER analyses_fts_update_delete AFTER UPDATE OF text_jawaban ON analyses WHEN old.text_jawaban IS NOT NULL BEGIN
+				INSERT INTO analyses_fts (analyses_fts, rowid, text_jawaban) VALUES ('delete', old.id, old.text_jawaban);
+			END`,
+			`CREATE TRIGGER analyses_fts_update_insert AFTER UPDATE OF text_jawaban ON analyses WHEN new.text_jawaban IS NOT NULL BEGIN
+				INSERT INTO analyses_fts (rowid, text_jawaban) VALUES (new.id, new.text_jawaban);
+			END`,
+			`INSERT INTO analyses_fts (analyses_fts) VALUES ('rebuild')`,
+		},
+	},
+	{
+		version:     5,
+		description: "add label scheme",
+		statements: []string{
+			`ALTER TABLE analyses ADD COLUMN label_scheme TEXT`,
+		},
+	},
+	{
+		version:     6,
+		description: "add daily token usage",
+		statements: []string{
+			`CREATE TABLE usage_daily (
+				client_id         TEXT NOT NULL,
+				date              TEXT NOT NULL,
+				model             TEXT NOT NULL,
+				requests          INTEGER NOT NULL,
+				prompt_tokens     INTEGER NOT NULL,
+				completion_tokens INTEGER NOT NULL,
+				total_tokens      INTEGER NOT NULL,
+				PRIMARY KEY (client_id, date, model)
+			)`,
+			`CREATE INDEX idx_usage_daily_date ON usage_daily (date)`,
+		},
+	},
+}
+
+// migrate applies pending migrations inside one transaction each. A migration
+// whose compile option is missing is skipped and applied by the first build
+// that has it; a database already using it cannot be opened without it.
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		available := true
+		if m.requires != "" {
+			if available, err = compileOption(ctx, db, m.requires); err != nil {
+				return err
+			}
+		}
+
+		if applied[m.version] {
+			if !available {
+				return fmt.Errorf("database uses migration %d (%s), which needs SQLite with %s; build with -tags sqlite_%s",
+					m.version, m.description, m.requires, strings.ToLower(m.requires))
+			}
+			continue
+		}
+		if !available {
+			logger.LogWarn("Skipped database migration, SQLite lacks a required feature", logrus.Fields{
+				"version":     m.version,
+				"description": m.description,
+				"requires":    m.requires,
+			})
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+		}
+
+		for _, statement := range m.statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, m.version); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+		}
+
+		logger.LogInfo("Applied database migration", logrus.Fields{
+			"version":     m.version,
+			"description": m.description,
+		})
+	}
+
+	return nil
+}
+
+// appliedMigrations returns the versions recorded in schema_migrations
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to read schema version: %w", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// compileOption reports whether SQLite was compiled with ENABLE_<option>
+func compileOption(ctx context.Context, db *sql.DB, option string) (bool, error) {
+	var used bool
+	if err := db.QueryRowContext(ctx, `SELECT sqlite_compileoption_used(?)`, "ENABLE_"+option).Scan(&used); err != nil {
+		return false, fmt.Errorf("failed to read SQLite compile options: %w", err)
+	}
+	return used, nil
+}
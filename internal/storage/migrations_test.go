@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSQLiteRepositoryMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analyses.db")
+
+	repository, err := NewSQLiteRepository(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+
+	want := map[int]bool{1: true, 2: true, 3: true, 5: true, 6: true}
+	if repository.fullText {
+		want[4] = true
+	}
+
+	applied, err := appliedMigrations(context.Background(), repository.db)
+	if err != nil {
+		t.Fatalf("appliedMigrations() error = %v", err)
+	}
+	if !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied migrations = %v, want %v", applied, want)
+	}
+	repository.Close()
+
+	// Reopening must not apply anything twice
+	repository, err = NewSQLiteRepository(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() on a migrated database error = %v", err)
+	}
+	defer repository.Close()
+
+	applied, err = appliedMigrations(context.Background(), repository.db)
+	if err != nil {
+		t.Fatalf("appliedMigrations() error = %v", err)
+	}
+	if !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied migrations after reopening = %v, want %v", applied, want)
+	}
+}
+
+func TestMigrateRequiredFeature(t *testing.T) {
+	original := migrations
+	t.Cleanup(func() { migrations = original })
+
+	migrations = []migration{
+		{
+			version:     1,
+			description: "create table",
+			statements:  []string{`CREATE TABLE items (id INTEGER PRIMARY KEY)`},
+		},
+		{
+			version:     2,
+			description: "needs a missing feature",
+			requires:    "MISSING_FEATURE",
+			statements:  []string{`CREATE TABLE extras (id INTEGER PRIMARY KEY)`},
+		},
+	}
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "features.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() error = %v, want the migration to be skipped", err)
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		t.Fatalf("appliedMigrations() error = %v", err)
+	}
+	if want := map[int]bool{1: true}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied migrations = %v, want %v", applied, want)
+	}
+
+	// A database migrated by a build that had the feature cannot be opened without it
+	if _, err := db.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (2)`); err != nil {
+		t.Fatalf("failed to record migration: %v", err)
+	}
+
+	err = migrate(ctx, db)
+	if err == nil || !strings.Contains(err.Error(), "-tags sqlite_missing_feature") {
+		t.Errorf("migrate() error = %v, want a hint to build with the missing feature", err)
+	}
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrDisabled is returned when analysis storage is not configured
+var ErrDisabled = errors.New("analysis storage is disabled")
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrNotFound is returned when a stored analysis does not exist or belongs to another client
+var ErrNotFound = errors.New("analysis not found")
+
+// AnalysisRecord represents one persisted sentiment analysis
+type AnalysisRecord struct {
+	ID             int64     `json:"id"`
+	ClientID       string    `json:"client_id"`
+	SurveyID       string    `json:"survey_id,omitempty"`
+	QuestionID     string    `json:"question_id,omitempty"`
+	RespondentID   string    `json:"respondent_id,omitempty"`
+	TextHash       string    `json:"text_hash"`
+	TextPertanyaan *string   `json:"text_pertanyaan,omitempty"`
+	TextJawaban    *string   `json:"text_jawaban,omitempty"`
+	Sentiment      string    `json:"sentiment"`
+	LabelScheme    string    `json:"label_scheme,omitempty"`
+	Reasoning      *string   `json:"reasoning,omitempty"`
+	Confidence     *float64  `json:"confidence,omitempty"`
+	Model          string    `json:"model"`
+	PromptVersion  string    `json:"prompt_version"`
+	LatencyMs      int64     `json:"latency_ms"`
+	Experiment     string    `json:"experiment,omitempty"`
+	Variant        string    `json:"variant,omitempty"`
+	ParseFailed    bool      `json:"parse_failed,omitempty"`
+	FeedbackLabel  *string   `json:"feedback_label,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AnalysisFilter selects stored analyses. Zero values disable a filter.
+type AnalysisFilter struct {
+	ClientID      string
+	Sentiment     string
+	SurveyID      string
+	QuestionID    string
+	Model         string
+	Experiment    string
+	From          time.Time
+	To            time.Time
+	MinConfidence *float64
+	MaxConfidence *float64
+	Search        string
+	Cursor        string
+	Limit         int
+}
+
+// AnalysisPage represents one page of stored analyses
+type AnalysisPage struct {
+	Items      []AnalysisRecord `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// SentimentCount represents the number of analyses with one label in one group and time bucket.
+// LabelScheme is empty for analyses stored before schemes were recorded.
+type SentimentCount struct {
+	SurveyID    string
+	QuestionID  string
+	Bucket      string
+	LabelScheme string
+	Sentiment   string
+	Count       int
+}
+
+// VariantCount represents the analyses of one experiment variant with one label
+type VariantCount struct {
+	Variant         string
+	LabelScheme     string
+	Sentiment       string
+	Count           int
+	LatencyMsSum    int64
+	ParseFailures   int
+	Feedback        int
+	FeedbackCorrect int
+}
+
+// UsageRecord represents the token usage of one client and model on one UTC day.
+// Date uses the YYYY-MM-DD layout.
+type UsageRecord struct {
+	ClientID         string
+	Date             string
+	Model            string
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// UsageRepository persists daily token usage for chargeback and budgets
+type UsageRepository interface {
+	// AddUsage adds a record to the stored totals of its client, date and model
+	AddUsage(ctx context.Context, record UsageRecord) error
+	// QueryUsage returns stored usage per client, date and model between
+	// fromDate and toDate inclusive. A non-empty clientID restricts it to that client.
+	QueryUsage(ctx context.Context, fromDate, toDate, clientID string) ([]UsageRecord, error)
+}
+
+// AnalysisRepository persists analysis results
+type AnalysisRepository interface {
+	// Save stores a record and sets its ID
+	Save(ctx context.Context, record *AnalysisRecord) error
+	// Query returns analyses matching the filter, newest first
+	Query(ctx context.Context, filter AnalysisFilter) (*AnalysisPage, error)
+	// Get returns one analysis. A non-empty clientID restricts the lookup to
+	// that client's analyses; ErrNotFound is returned when none matched.
+	Get(ctx context.Context, id int64, clientID string) (*AnalysisRecord, error)
+	// CountSentiments groups matching analyses by survey, question, time bucket, scheme and label.
+	// Bucket is one of "day", "week" or "month".
+	CountSentiments(ctx context.Context, filter AnalysisFilter, bucket string) ([]SentimentCount, error)
+	// SaveFeedback stores the correct label of an analysis. A non-empty clientID
+	// restricts the update to that client's analyses; ErrNotFound is returned
+	// when no analysis matched.
+	SaveFeedback(ctx context.Context, id int64, clientID, label string) error
+	// CountVariants groups the analyses of an experiment by variant, scheme and label
+	CountVariants(ctx context.Context, experiment string) ([]VariantCount, error)
+	// DeleteOlderThan removes analyses created before the cutoff
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	// Close releases the underlying resources
+	Close() error
+}
+
+// HashText returns the hex SHA-256 of a question and answer pair
+func HashText(textPertanyaan, textJawaban string) string {
+	hash := sha256.New()
+	hash.Write([]byte(textPertanyaan))
+	hash.Write([]byte{0})
+	hash.Write([]byte(textJawaban))
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StartRetention periodically deletes records older than the retention period.
+// It returns immediately when retentionDays is not positive.
+func StartRetention(ctx context.Context, repository AnalysisRepository, retentionDays int, interval time.Duration) {
+	if retentionDays <= 0 {
+		return
+	}
+
+	purge := func() {
+		cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+		deleted, err := repository.DeleteOlderThan(ctx, cutoff)
+		if err != nil {
+			logger.LogError("Failed to apply retention policy", logrus.Fields{
+				"error": err.Error(),
+			})
+			return
+		}
+		if deleted > 0 {
+			logger.LogInfo("Retention policy removed old analyses", logrus.Fields{
+				"deleted": deleted,
+				"cutoff":  cutoff.Format(time.RFC3339),
+			})
+		}
+	}
+
+	go func() {
+		purge()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purge()
+			}
+		}
+	}()
+}
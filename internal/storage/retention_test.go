@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartRetention(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name          string
+		retentionDays int
+		wantRemaining int
+	}{
+		{name: "deletes records past the period", retentionDays: 7, wantRemaining: 1},
+		{name: "disabled", retentionDays: 0, wantRemaining: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newTestRepository(t)
+			saveRecords(t, repository, []AnalysisRecord{
+				{ClientID: "team", Sentiment: "Positif", CreatedAt: now.AddDate(0, 0, -8)},
+				{ClientID: "team", Sentiment: "Positif", CreatedAt: now.AddDate(0, 0, -6)},
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The first purge runs right away, well before the interval elapses
+			StartRetention(ctx, repository, tt.retentionDays, time.Hour)
+
+			var remaining int
+			deadline := time.Now().Add(2 * time.Second)
+			for {
+				page, err := repository.Query(context.Background(), AnalysisFilter{})
+				if err != nil {
+					t.Fatalf("Query() error = %v", err)
+				}
+				remaining = len(page.Items)
+				if remaining == tt.wantRemaining || time.Now().After(deadline) {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if remaining != tt.wantRemaining {
+				t.Errorf("remaining records = %d, want %d", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
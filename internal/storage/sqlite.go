@@ -0,0 +1,434 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"sentiment-api/pkg/logger"
+
+	_ "github.com/mattn/go-sqlite3" // Register the sqlite3 driver
+)
+
+// SQLiteRepository stores analyses in a local SQLite database
+type SQLiteRepository struct {
+	db *sql.DB
+	// fullText is set when answers are searched through the FTS5 index
+	fullText bool
+}
+
+// NewSQLiteRepository opens the database at path and applies pending migrations.
+// Answer search uses an FTS5 index when go-sqlite3 is built with -tags
+// sqlite_fts5 and falls back to substring matching otherwise.
+func NewSQLiteRepository(path string) (*SQLiteRepository, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer; serialize access through one connection
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	fullText, err := compileOption(ctx, db, "FTS5")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !fullText {
+		logger.LogWarn("SQLite lacks FTS5, answer search falls back to substring matching; build with -tags sqlite_fts5 for the full-text index", nil)
+	}
+
+	return &SQLiteRepository{db: db, fullText: fullText}, nil
+}
+
+// Save stores a record and sets its ID
+func (r *SQLiteRepository) Save(ctx context.Context, record *AnalysisRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := r.db.ExecContext(ctx, `INSERT INTO analyses (
+		client_id, survey_id, question_id, respondent_id, text_hash, text_pertanyaan, text_jawaban,
+		sentiment, label_scheme, reasoning, confidence, model, prompt_version, latency_ms, experiment, variant, parse_failed, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.ClientID, nullString(record.SurveyID), nullString(record.QuestionID), nullString(record.RespondentID),
+		record.TextHash, record.TextPertanyaan, record.TextJawaban, record.Sentiment, nullString(record.LabelScheme), record.Reasoning,
+		record.Confidence, record.Model, record.PromptVersion, record.LatencyMs,
+		nullString(record.Experiment), nullString(record.Variant), record.ParseFailed, record.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+
+	record.ID, err = result.LastInsertId()
+	return err
+}
+
+// Query returns analyses matching the filter, newest first
+func (r *SQLiteRepository) Query(ctx context.Context, filter AnalysisFilter) (*AnalysisPage, error) {
+	where, args, err := r.buildWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `SELECT ` + recordColumns + ` FROM analyses` + where + ` ORDER BY id DESC LIMIT ?`
+
+	// Fetch one extra row to know whether another page exists
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	page := &AnalysisPage{Items: []AnalysisRecord{}}
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read analysis: %w", err)
+		}
+		page.Items = append(page.Items, *record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read analyses: %w", err)
+	}
+
+	if len(page.Items) > limit {
+		page.Items = page.Items[:limit]
+		page.NextCursor = encodeCursor(page.Items[limit-1].ID)
+	}
+
+	return page, nil
+}
+
+// Get returns one analysis, restricted to clientID when it is not empty
+func (r *SQLiteRepository) Get(ctx context.Context, id int64, clientID string) (*AnalysisRecord, error) {
+	query := `SELECT ` + recordColumns + ` FROM analyses WHERE id = ?`
+	args := []interface{}{id}
+	if clientID != "" {
+		query += ` AND client_id = ?`
+		args = append(args, clientID)
+	}
+
+	record, err := scanRecord(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis: %w", err)
+	}
+	return record, nil
+}
+
+// recordColumns lists the analyses columns read by scanRecord, in order
+const recordColumns = `id, client_id, survey_id, question_id, respondent_id, text_hash, text_pertanyaan, text_jawaban,
+	sentiment, label_scheme, reasoning, confidence, model, prompt_version, latency_ms, experiment, variant, parse_failed,
+	feedback_label, created_at`
+
+// scanRecord reads one row selected with recordColumns
+func scanRecord(row interface {
+	Scan(dest ...interface{}) error
+}) (*AnalysisRecord, error) {
+	var record AnalysisRecord
+	var surveyID, questionID, respondentID, labelScheme, experiment, variant sql.NullString
+
+	if err := row.Scan(
+		&record.ID, &record.ClientID, &surveyID, &questionID, &respondentID, &record.TextHash,
+		&record.TextPertanyaan, &record.TextJawaban, &record.Sentiment, &labelScheme, &record.Reasoning, &record.Confidence,
+		&record.Model, &record.PromptVersion, &record.LatencyMs, &experiment, &variant, &record.ParseFailed,
+		&record.FeedbackLabel, &record.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	record.SurveyID = surveyID.String
+	record.QuestionID = questionID.String
+	record.RespondentID = respondentID.String
+	record.LabelScheme = labelScheme.String
+	record.Experiment = experiment.String
+	record.Variant = variant.String
+	return &record, nil
+}
+
+// bucketFormats maps bucket names to SQLite strftime formats
+var bucketFormats = map[string]string{
+	"day":   "%Y-%m-%d",
+	"week":  "%Y-W%W",
+	"month": "%Y-%m",
+}
+
+// CountSentiments groups matching analyses by survey, question, time bucket and label
+func (r *SQLiteRepository) CountSentiments(ctx context.Context, filter AnalysisFilter, bucket string) ([]SentimentCount, error) {
+	format, ok := bucketFormats[bucket]
+	if !ok {
+		return nil, fmt.Errorf("unsupported bucket %q", bucket)
+	}
+
+	filter.Cursor = ""
+	where, args, err := r.buildWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT COALESCE(survey_id, ''), COALESCE(question_id, ''), strftime(?, created_at) AS bucket,
+		COALESCE(label_scheme, ''), sentiment, COUNT(*)
+		FROM analyses` + where + ` GROUP BY 1, 2, 3, 4, 5 ORDER BY 1, 2, 3, 4, 5`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{format}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []SentimentCount
+	for rows.Next() {
+		var count SentimentCount
+		if err := rows.Scan(&count.SurveyID, &count.QuestionID, &count.Bucket, &count.LabelScheme, &count.Sentiment, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to read aggregate: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// SaveFeedback stores the correct label of an analysis
+func (r *SQLiteRepository) SaveFeedback(ctx context.Context, id int64, clientID, label string) error {
+	query := `UPDATE analyses SET feedback_label = ?, feedback_at = ? WHERE id = ?`
+	args := []interface{}{label, time.Now().UTC(), id}
+	if clientID != "" {
+		query += ` AND client_id = ?`
+		args = append(args, clientID)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	if updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CountVariants groups the analyses of an experiment by variant and label
+func (r *SQLiteRepository) CountVariants(ctx context.Context, experiment string) ([]VariantCount, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT variant, COALESCE(label_scheme, ''), sentiment, COUNT(*), SUM(latency_ms),
+		SUM(parse_failed), COUNT(feedback_label), SUM(CASE WHEN feedback_label = sentiment THEN 1 ELSE 0 END)
+		FROM analyses WHERE experiment = ? GROUP BY 1, 2, 3 ORDER BY 1, 2, 3`, experiment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate experiment: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []VariantCount
+	for rows.Next() {
+		var count VariantCount
+		if err := rows.Scan(&count.Variant, &count.LabelScheme, &count.Sentiment, &count.Count, &count.LatencyMsSum,
+			&count.ParseFailures, &count.Feedback, &count.FeedbackCorrect); err != nil {
+			return nil, fmt.Errorf("failed to read experiment aggregate: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// AddUsage adds a record to the stored totals of its client, date and model
+func (r *SQLiteRepository) AddUsage(ctx context.Context, record UsageRecord) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO usage_daily (
+		client_id, date, model, requests, prompt_tokens, completion_tokens, total_tokens
+	) VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (client_id, date, model) DO UPDATE SET
+		requests = requests + excluded.requests,
+		prompt_tokens = prompt_tokens + excluded.prompt_tokens,
+		completion_tokens = completion_tokens + excluded.completion_tokens,
+		total_tokens = total_tokens + excluded.total_tokens`,
+		record.ClientID, record.Date, record.Model, record.Requests,
+		record.PromptTokens, record.CompletionTokens, record.TotalTokens,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save usage: %w", err)
+	}
+	return nil
+}
+
+// QueryUsage returns stored usage per client, date and model between fromDate
+// and toDate inclusive, restricted to clientID when it is not empty
+func (r *SQLiteRepository) QueryUsage(ctx context.Context, fromDate, toDate, clientID string) ([]UsageRecord, error) {
+	query := `SELECT client_id, date, model, requests, prompt_tokens, completion_tokens, total_tokens
+		FROM usage_daily WHERE date >= ? AND date <= ?`
+	args := []interface{}{fromDate, toDate}
+	if clientID != "" {
+		query += ` AND client_id = ?`
+		args = append(args, clientID)
+	}
+	query += ` ORDER BY date, client_id, model`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var records []UsageRecord
+	for rows.Next() {
+		var record UsageRecord
+		if err := rows.Scan(&record.ClientID, &record.Date, &record.Model, &record.Requests,
+			&record.PromptTokens, &record.CompletionTokens, &record.TotalTokens); err != nil {
+			return nil, fmt.Errorf("failed to read usage: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	return records, nil
+}
+
+// DeleteOlderThan removes analyses created before the cutoff. Daily usage is
+// kept for chargeback.
+func (r *SQLiteRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM analyses WHERE created_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply retention: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Close closes the database
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+// buildWhere translates a filter into a WHERE clause and its arguments
+func (r *SQLiteRepository) buildWhere(filter AnalysisFilter) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, values ...interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+
+	if filter.ClientID != "" {
+		add("client_id = ?", filter.ClientID)
+	}
+	if filter.Sentiment != "" {
+		add("sentiment = ?", filter.Sentiment)
+	}
+	if filter.SurveyID != "" {
+		add("survey_id = ?", filter.SurveyID)
+	}
+	if filter.QuestionID != "" {
+		add("question_id = ?", filter.QuestionID)
+	}
+	if filter.Model != "" {
+		add("model = ?", filter.Model)
+	}
+	if filter.Experiment != "" {
+		add("experiment = ?", filter.Experiment)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		add("created_at < ?", filter.To.UTC())
+	}
+	if filter.MinConfidence != nil {
+		add("confidence >= ?", *filter.MinConfidence)
+	}
+	if filter.MaxConfidence != nil {
+		add("confidence <= ?", *filter.MaxConfidence)
+	}
+	if r.fullText {
+		if query := matchQuery(filter.Search); query != "" {
+			add("id IN (SELECT rowid FROM analyses_fts WHERE analyses_fts MATCH ?)", query)
+		}
+	} else {
+		for _, term := range strings.Fields(filter.Search) {
+			add(`text_jawaban LIKE ? ESCAPE '\'`, "%"+escapeLike(term)+"%")
+		}
+	}
+	if filter.Cursor != "" {
+		id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		add("id < ?", id)
+	}
+
+	if len(conditions) == 0 {
+		return "", args, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// matchQuery turns search words into an FTS5 query requiring every word.
+// Words are quoted so FTS5 operators in user input are matched literally.
+func matchQuery(search string) string {
+	terms := strings.Fields(search)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(terms, " ")
+}
+
+// escapeLike escapes LIKE wildcards in a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+// encodeCursor encodes the last seen record ID as an opaque cursor
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeCursor decodes a cursor produced by encodeCursor
+func decodeCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
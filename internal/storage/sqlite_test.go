@@ -0,0 +1,586 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sentiment-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	os.Exit(m.Run())
+}
+
+// newTestRepository opens a migrated repository in a temporary directory
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+
+	repository, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "analyses.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	t.Cleanup(func() { repository.Close() })
+	return repository
+}
+
+// saveRecords stores records in order and returns their IDs
+func saveRecords(t *testing.T, repository *SQLiteRepository, records []AnalysisRecord) []int64 {
+	t.Helper()
+
+	ids := make([]int64, len(records))
+	for i := range records {
+		if records[i].TextHash == "" {
+			records[i].TextHash = HashText("", derefString(records[i].TextJawaban))
+		}
+		if records[i].Model == "" {
+			records[i].Model = "gpt-4o-mini"
+		}
+		if records[i].PromptVersion == "" {
+			records[i].PromptVersion = "v1"
+		}
+		if err := repository.Save(context.Background(), &records[i]); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		ids[i] = records[i].ID
+	}
+	return ids
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+// recordIDs returns the IDs of a page in order
+func recordIDs(records []AnalysisRecord) []int64 {
+	ids := make([]int64, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+func TestSaveAndGet(t *testing.T) {
+	repository := newTestRepository(t)
+	ctx := context.Background()
+
+	record := AnalysisRecord{
+		ClientID:       "team",
+		SurveyID:       "survey-1",
+		QuestionID:     "q1",
+		RespondentID:   "r1",
+		TextPertanyaan: stringPtr("Bagaimana layanan kami?"),
+		TextJawaban:    stringPtr("Sangat memuaskan"),
+		Sentiment:      "Positif",
+		LabelScheme:    "default",
+		Reasoning:      stringPtr("Pujian"),
+		Confidence:     floatPtr(0.9),
+		LatencyMs:      120,
+		Experiment:     "prompt-test",
+		Variant:        "b",
+		CreatedAt:      time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+	saveRecords(t, repository, []AnalysisRecord{record})
+
+	if _, err := repository.Get(ctx, 1, "other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() for another client error = %v, want ErrNotFound", err)
+	}
+	if _, err := repository.Get(ctx, 2, ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() for a missing ID error = %v, want ErrNotFound", err)
+	}
+
+	for _, clientID := range []string{"team", ""} {
+		got, err := repository.Get(ctx, 1, clientID)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", clientID, err)
+		}
+
+		want := record
+		want.ID = 1
+		want.TextHash = HashText("", "Sangat memuaskan")
+		want.Model = "gpt-4o-mini"
+		want.PromptVersion = "v1"
+		got.CreatedAt = got.CreatedAt.UTC()
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("Get(%q) = %+v, want %+v", clientID, *got, want)
+		}
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	repository := newTestRepository(t)
+	day := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	saveRecords(t, repository, []AnalysisRecord{
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q1", Sentiment: "Positif", Confidence: floatPtr(0.9), CreatedAt: day.AddDate(0, 0, -2)},
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q2", Sentiment: "Negatif", Confidence: floatPtr(0.6), CreatedAt: day.AddDate(0, 0, -1)},
+		{ClientID: "boss", SurveyID: "s2", QuestionID: "q1", Sentiment: "Positif", Confidence: floatPtr(0.4), Model: "gemini", CreatedAt: day},
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q1", Sentiment: "Netral", Experiment: "prompt-test", Variant: "a", CreatedAt: day},
+	})
+
+	tests := []struct {
+		name   string
+		filter AnalysisFilter
+		want   []int64
+	}{
+		{name: "no filter", want: []int64{4, 3, 2, 1}},
+		{name: "client", filter: AnalysisFilter{ClientID: "team"}, want: []int64{4, 2, 1}},
+		{name: "label", filter: AnalysisFilter{Sentiment: "Positif"}, want: []int64{3, 1}},
+		{name: "survey and question", filter: AnalysisFilter{SurveyID: "s1", QuestionID: "q1"}, want: []int64{4, 1}},
+		{name: "model", filter: AnalysisFilter{Model: "gemini"}, want: []int64{3}},
+		{name: "experiment", filter: AnalysisFilter{Experiment: "prompt-test"}, want: []int64{4}},
+		{name: "from is inclusive", filter: AnalysisFilter{From: day.AddDate(0, 0, -1)}, want: []int64{4, 3, 2}},
+		{name: "to is exclusive", filter: AnalysisFilter{To: day}, want: []int64{2, 1}},
+		{name: "confidence range skips unscored", filter: AnalysisFilter{MinConfidence: floatPtr(0.5), MaxConfidence: floatPtr(0.6)}, want: []int64{2}},
+		{name: "client, label and period", filter: AnalysisFilter{ClientID: "team", Sentiment: "Positif", From: day.AddDate(0, 0, -2), To: day}, want: []int64{1}},
+		{name: "no match", filter: AnalysisFilter{ClientID: "boss", Sentiment: "Negatif"}, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repository.Query(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := recordIDs(page.Items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() IDs = %v, want %v", got, tt.want)
+			}
+			if page.NextCursor != "" {
+				t.Errorf("NextCursor = %q, want none", page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestQueryPagination(t *testing.T) {
+	repository := newTestRepository(t)
+	saveRecords(t, repository, []AnalysisRecord{
+		{ClientID: "team", Sentiment: "Positif"},
+		{ClientID: "team", Sentiment: "Positif"},
+		{ClientID: "boss", Sentiment: "Positif"},
+		{ClientID: "team", Sentiment: "Negatif"},
+		{ClientID: "team", Sentiment: "Positif"},
+	})
+
+	tests := []struct {
+		name      string
+		filter    AnalysisFilter
+		wantPages [][]int64
+	}{
+		{
+			name:      "full pages",
+			filter:    AnalysisFilter{Limit: 2},
+			wantPages: [][]int64{{5, 4}, {3, 2}, {1}},
+		},
+		{
+			name:      "last page exactly full",
+			filter:    AnalysisFilter{ClientID: "team", Limit: 2},
+			wantPages: [][]int64{{5, 4}, {2, 1}},
+		},
+		{
+			name:      "filter applies across pages",
+			filter:    AnalysisFilter{Sentiment: "Positif", Limit: 1},
+			wantPages: [][]int64{{5}, {3}, {2}, {1}},
+		},
+		{
+			name:      "default limit",
+			wantPages: [][]int64{{5, 4, 3, 2, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			var pages [][]int64
+
+			for {
+				page, err := repository.Query(context.Background(), filter)
+				if err != nil {
+					t.Fatalf("Query() error = %v", err)
+				}
+				pages = append(pages, recordIDs(page.Items))
+				if page.NextCursor == "" {
+					break
+				}
+				if len(pages) > len(tt.wantPages) {
+					t.Fatalf("Query() returned more than %d pages", len(tt.wantPages))
+				}
+				filter.Cursor = page.NextCursor
+			}
+
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("pages = %v, want %v", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestQueryInvalidCursor(t *testing.T) {
+	repository := newTestRepository(t)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "not a number", cursor: "YWJj"},
+		{name: "zero ID", cursor: encodeCursor(0)},
+		{name: "negative ID", cursor: encodeCursor(-3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repository.Query(context.Background(), AnalysisFilter{Cursor: tt.cursor}); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Query() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestCountSentiments(t *testing.T) {
+	repository := newTestRepository(t)
+
+	// 2026-10-12 is a Monday, so the first three records share a week
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	saveRecords(t, repository, []AnalysisRecord{
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q1", Sentiment: "Positif", LabelScheme: "default", CreatedAt: monday},
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q1", Sentiment: "Positif", LabelScheme: "default", CreatedAt: monday.Add(2 * time.Hour)},
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q1", Sentiment: "Negatif", CreatedAt: monday.AddDate(0, 0, 6)},
+		{ClientID: "team", SurveyID: "s1", QuestionID: "q1", Sentiment: "Positif", LabelScheme: "default", CreatedAt: monday.AddDate(0, 0, 7)},
+		{ClientID: "boss", SurveyID: "s2", Sentiment: "Netral", LabelScheme: "default", CreatedAt: monday.AddDate(0, 1, 0)},
+	})
+
+	tests := []struct {
+		name    string
+		filter  AnalysisFilter
+		bucket  string
+		want    []SentimentCount
+		wantErr bool
+	}{
+		{
+			name:   "day",
+			filter: AnalysisFilter{ClientID: "team"},
+			bucket: "day",
+			want: []SentimentCount{
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-10-12", LabelScheme: "default", Sentiment: "Positif", Count: 2},
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-10-18", Sentiment: "Negatif", Count: 1},
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-10-19", LabelScheme: "default", Sentiment: "Positif", Count: 1},
+			},
+		},
+		{
+			name:   "week",
+			filter: AnalysisFilter{ClientID: "team"},
+			bucket: "week",
+			want: []SentimentCount{
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-W41", Sentiment: "Negatif", Count: 1},
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-W41", LabelScheme: "default", Sentiment: "Positif", Count: 2},
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-W42", LabelScheme: "default", Sentiment: "Positif", Count: 1},
+			},
+		},
+		{
+			name:   "month groups surveys",
+			bucket: "month",
+			want: []SentimentCount{
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-10", Sentiment: "Negatif", Count: 1},
+				{SurveyID: "s1", QuestionID: "q1", Bucket: "2026-10", LabelScheme: "default", Sentiment: "Positif", Count: 3},
+				{SurveyID: "s2", Bucket: "2026-11", LabelScheme: "default", Sentiment: "Netral", Count: 1},
+			},
+		},
+		{
+			name:   "cursor is ignored",
+			filter: AnalysisFilter{SurveyID: "s2", Cursor: encodeCursor(1)},
+			bucket: "month",
+			want: []SentimentCount{
+				{SurveyID: "s2", Bucket: "2026-11", LabelScheme: "default", Sentiment: "Netral", Count: 1},
+			},
+		},
+		{
+			name:   "no match",
+			filter: AnalysisFilter{SurveyID: "missing"},
+			bucket: "day",
+		},
+		{
+			name:    "unknown bucket",
+			bucket:  "year",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repository.CountSentiments(context.Background(), tt.filter, tt.bucket)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CountSentiments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountSentiments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveFeedbackAndCountVariants(t *testing.T) {
+	repository := newTestRepository(t)
+	ctx := context.Background()
+
+	saveRecords(t, repository, []AnalysisRecord{
+		{ClientID: "team", Sentiment: "Positif", LatencyMs: 100, Experiment: "prompt-test", Variant: "a"},
+		{ClientID: "team", Sentiment: "Positif", LatencyMs: 300, Experiment: "prompt-test", Variant: "a"},
+		{ClientID: "boss", Sentiment: "Negatif", LatencyMs: 200, Experiment: "prompt-test", Variant: "b", ParseFailed: true},
+		{ClientID: "team", Sentiment: "Positif", LatencyMs: 50, Experiment: "other", Variant: "a"},
+	})
+
+	feedback := []struct {
+		name     string
+		id       int64
+		clientID string
+		label    string
+		wantErr  error
+	}{
+		{name: "own analysis", id: 1, clientID: "team", label: "Positif"},
+		{name: "admin corrects any analysis", id: 2, label: "Negatif"},
+		{name: "overwrite", id: 3, clientID: "boss", label: "Netral"},
+		{name: "another client", id: 3, clientID: "team", label: "Negatif", wantErr: ErrNotFound},
+		{name: "missing analysis", id: 9, label: "Positif", wantErr: ErrNotFound},
+	}
+	for _, tt := range feedback {
+		if err := repository.SaveFeedback(ctx, tt.id, tt.clientID, tt.label); !errors.Is(err, tt.wantErr) {
+			t.Errorf("SaveFeedback() %s error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if err := repository.SaveFeedback(ctx, 3, "", "Negatif"); err != nil {
+		t.Fatalf("SaveFeedback() error = %v", err)
+	}
+
+	stored, err := repository.Get(ctx, 3, "")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := derefString(stored.FeedbackLabel); got != "Negatif" {
+		t.Errorf("FeedbackLabel = %q, want the last correction", got)
+	}
+
+	got, err := repository.CountVariants(ctx, "prompt-test")
+	if err != nil {
+		t.Fatalf("CountVariants() error = %v", err)
+	}
+	want := []VariantCount{
+		{Variant: "a", Sentiment: "Positif", Count: 2, LatencyMsSum: 400, Feedback: 2, FeedbackCorrect: 1},
+		{Variant: "b", Sentiment: "Negatif", Count: 1, LatencyMsSum: 200, ParseFailures: 1, Feedback: 1, FeedbackCorrect: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CountVariants() = %+v, want %+v", got, want)
+	}
+
+	if got, err := repository.CountVariants(ctx, "missing"); err != nil || got != nil {
+		t.Errorf("CountVariants() for a missing experiment = %+v, %v, want none", got, err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	repository := newTestRepository(t)
+	ctx := context.Background()
+
+	for _, record := range []UsageRecord{
+		{ClientID: "team", Date: "2026-10-17", Model: "gpt-4o-mini", Requests: 1, PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110},
+		{ClientID: "team", Date: "2026-10-18", Model: "gpt-4o-mini", Requests: 1, PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110},
+		{ClientID: "team", Date: "2026-10-18", Model: "gpt-4o-mini", Requests: 2, PromptTokens: 200, CompletionTokens: 20, TotalTokens: 220},
+		{ClientID: "team", Date: "2026-10-18", Model: "gemini", Requests: 1, PromptTokens: 50, CompletionTokens: 5, TotalTokens: 55},
+		{ClientID: "boss", Date: "2026-10-18", Model: "gpt-4o-mini", Requests: 1, PromptTokens: 10, CompletionTokens: 1, TotalTokens: 11},
+		{ClientID: "team", Date: "2026-10-19", Model: "gpt-4o-mini", Requests: 1, PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110},
+	} {
+		if err := repository.AddUsage(ctx, record); err != nil {
+			t.Fatalf("AddUsage() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		clientID string
+		want     []UsageRecord
+	}{
+		{
+			name: "all clients on one day",
+			from: "2026-10-18",
+			to:   "2026-10-18",
+			want: []UsageRecord{
+				{ClientID: "boss", Date: "2026-10-18", Model: "gpt-4o-mini", Requests: 1, PromptTokens: 10, CompletionTokens: 1, TotalTokens: 11},
+				{ClientID: "team", Date: "2026-10-18", Model: "gemini", Requests: 1, PromptTokens: 50, CompletionTokens: 5, TotalTokens: 55},
+				{ClientID: "team", Date: "2026-10-18", Model: "gpt-4o-mini", Requests: 3, PromptTokens: 300, CompletionTokens: 30, TotalTokens: 330},
+			},
+		},
+		{
+			name:     "one client over an inclusive range",
+			from:     "2026-10-17",
+			to:       "2026-10-18",
+			clientID: "team",
+			want: []UsageRecord{
+				{ClientID: "team", Date: "2026-10-17", Model: "gpt-4o-mini", Requests: 1, PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110},
+				{ClientID: "team", Date: "2026-10-18", Model: "gemini", Requests: 1, PromptTokens: 50, CompletionTokens: 5, TotalTokens: 55},
+				{ClientID: "team", Date: "2026-10-18", Model: "gpt-4o-mini", Requests: 3, PromptTokens: 300, CompletionTokens: 30, TotalTokens: 330},
+			},
+		},
+		{
+			name: "empty range",
+			from: "2026-11-01",
+			to:   "2026-11-30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repository.QueryUsage(ctx, tt.from, tt.to, tt.clientID)
+			if err != nil {
+				t.Fatalf("QueryUsage() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryUsage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteOlderThan(t *testing.T) {
+	repository := newTestRepository(t)
+	ctx := context.Background()
+	cutoff := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	saveRecords(t, repository, []AnalysisRecord{
+		{ClientID: "team", Sentiment: "Positif", CreatedAt: cutoff.Add(-time.Second)},
+		{ClientID: "team", Sentiment: "Positif", CreatedAt: cutoff},
+		{ClientID: "team", Sentiment: "Positif", CreatedAt: cutoff.Add(time.Hour)},
+	})
+	if err := repository.AddUsage(ctx, UsageRecord{ClientID: "team", Date: "2026-10-01", Model: "gpt-4o-mini", Requests: 1}); err != nil {
+		t.Fatalf("AddUsage() error = %v", err)
+	}
+
+	deleted, err := repository.DeleteOlderThan(ctx, cutoff)
+	if err != nil {
+		t.Fatalf("DeleteOlderThan() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteOlderThan() deleted %d, want 1", deleted)
+	}
+
+	page, err := repository.Query(ctx, AnalysisFilter{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got, want := recordIDs(page.Items), []int64{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("remaining IDs = %v, want %v", got, want)
+	}
+
+	usage, err := repository.QueryUsage(ctx, "2026-10-01", "2026-10-31", "")
+	if err != nil {
+		t.Fatalf("QueryUsage() error = %v", err)
+	}
+	if len(usage) != 1 {
+		t.Errorf("QueryUsage() = %+v, want usage kept after retention", usage)
+	}
+}
+
+func TestMatchQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		want   string
+	}{
+		{name: "empty", search: "", want: ""},
+		{name: "whitespace only", search: "  \t ", want: ""},
+		{name: "single word", search: "cepat", want: `"cepat"`},
+		{name: "every word is required", search: " pengiriman   cepat ", want: `"pengiriman" "cepat"`},
+		{name: "operators are quoted", search: "bagus OR NOT buruk", want: `"bagus" "OR" "NOT" "buruk"`},
+		{name: "quotes are doubled", search: `"murah"`, want: `"""murah"""`},
+		{name: "syntax characters", search: "harga* (murah) col:x", want: `"harga*" "(murah)" "col:x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchQuery(tt.search); got != tt.want {
+				t.Errorf("matchQuery(%q) = %q, want %q", tt.search, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestQuerySearch expects the same results from the FTS5 index and the LIKE
+// fallback, so it passes with and without -tags sqlite_fts5
+func TestQuerySearch(t *testing.T) {
+	repository := newTestRepository(t)
+	saveRecords(t, repository, []AnalysisRecord{
+		{ClientID: "team", Sentiment: "Positif", TextJawaban: stringPtr("Pengiriman cepat sekali")},
+		{ClientID: "team", Sentiment: "Negatif", TextJawaban: stringPtr("pengiriman lambat")},
+		{ClientID: "boss", Sentiment: "Positif", TextJawaban: stringPtr(`harga "murah" sekali`)},
+		{ClientID: "team", Sentiment: "Netral", TextJawaban: stringPtr("NOT bad at all")},
+		{ClientID: "team", Sentiment: "Positif", TextJawaban: stringPtr("diskon 100% oke")},
+		{ClientID: "team", Sentiment: "Positif", TextJawaban: stringPtr("kode_promo berlaku")},
+		{ClientID: "team", Sentiment: "Positif"},
+	})
+
+	tests := []struct {
+		name   string
+		filter AnalysisFilter
+		want   []int64
+	}{
+		{name: "single word ignores case", filter: AnalysisFilter{Search: "PENGIRIMAN"}, want: []int64{2, 1}},
+		{name: "every word must match", filter: AnalysisFilter{Search: "pengiriman cepat"}, want: []int64{1}},
+		{name: "quoted word", filter: AnalysisFilter{Search: `"murah"`}, want: []int64{3}},
+		{name: "operator as a word", filter: AnalysisFilter{Search: "NOT"}, want: []int64{4}},
+		{name: "operator between words", filter: AnalysisFilter{Search: "bad OR"}, want: []int64{}},
+		{name: "percent sign", filter: AnalysisFilter{Search: "100%"}, want: []int64{5}},
+		{name: "underscore", filter: AnalysisFilter{Search: "kode_promo"}, want: []int64{6}},
+		{name: "search with filters", filter: AnalysisFilter{Search: "sekali", ClientID: "team", Sentiment: "Positif"}, want: []int64{1}},
+		{name: "empty result", filter: AnalysisFilter{Search: "kecewa"}, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repository.Query(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := recordIDs(page.Items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("cursor continuation", func(t *testing.T) {
+		filter := AnalysisFilter{Search: "sekali", Limit: 1}
+
+		first, err := repository.Query(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if got := recordIDs(first.Items); !reflect.DeepEqual(got, []int64{3}) || first.NextCursor == "" {
+			t.Fatalf("first page = %v, cursor %q, want [3] and a cursor", got, first.NextCursor)
+		}
+
+		filter.Cursor = first.NextCursor
+		second, err := repository.Query(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if got := recordIDs(second.Items); !reflect.DeepEqual(got, []int64{1}) || second.NextCursor != "" {
+			t.Errorf("second page = %v, cursor %q, want [1] and no cursor", got, second.NextCursor)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package topics
+
+import (
+	"strings"
+
+	"sentiment-api/internal/lexicon"
+)
+
+// OtherLabel names the theme holding answers that fit no other theme
+const OtherLabel = "Lainnya"
+
+// stopwords lists Indonesian and English function words that never name a theme
+var stopwords = map[string]bool{
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "ini": true, "itu": true,
+	"dengan": true, "untuk": true, "pada": true, "adalah": true, "saya": true, "kami": true,
+	"kita": true, "anda": true, "aku": true, "kamu": true, "nya": true, "sudah": true, "belum": true,
+	"akan": true, "bisa": true, "juga": true, "karena": true, "tapi": true, "tetapi": true,
+	"namun": true, "jadi": true, "ada": true, "tidak": true, "tak": true, "gak": true, "nggak": true,
+	"sangat": true, "sekali": true, "banget": true, "lebih": true, "kurang": true, "agak": true,
+	"cukup": true, "lagi": true, "masih": true, "hanya": true, "saja": true, "aja": true, "sih": true,
+	"dong": true, "kok": true, "ya": true, "yg": true, "dgn": true, "utk": true, "atau": true,
+	"oleh": true, "seperti": true, "kalau": true, "jika": true, "bila": true, "agar": true,
+	"supaya": true, "semua": true, "setiap": true, "para": true, "the": true, "and": true,
+	"is": true, "are": true, "was": true, "it": true, "to": true, "of": true, "for": true,
+	"but": true, "very": true, "not": true, "this": true, "that": true, "with": true,
+}
+
+// Cluster represents one theme found by keyword clustering
+type Cluster struct {
+	Label    string
+	Keywords []string
+	Members  []int
+}
+
+// KeywordClusters groups texts by their most frequent shared keywords.
+// It is deterministic: the keyword shared by the most unassigned texts seeds
+// the next theme (ties broken alphabetically) until maxTopics themes exist or
+// no keyword is shared by two texts. Leftover texts go to an OtherLabel theme.
+func KeywordClusters(texts []string, maxTopics int) []Cluster {
+	keywords := make([]map[string]bool, len(texts))
+	for i, text := range texts {
+		keywords[i] = make(map[string]bool)
+		for _, token := range lexicon.Tokenize(text) {
+			if len(token) > 2 && !stopwords[token] {
+				keywords[i][token] = true
+			}
+		}
+	}
+
+	assigned := make([]bool, len(texts))
+	var clusters []Cluster
+
+	for len(clusters) < maxTopics {
+		seed, count := topKeyword(keywords, assigned, nil, "")
+		if count < 2 {
+			break
+		}
+
+		cluster := Cluster{Keywords: []string{seed}}
+		for i := range texts {
+			if !assigned[i] && keywords[i][seed] {
+				cluster.Members = append(cluster.Members, i)
+			}
+		}
+
+		// A second keyword shared by most members makes the label more telling
+		if second, secondCount := topKeyword(keywords, assigned, cluster.Members, seed); second != "" && secondCount*2 >= len(cluster.Members) {
+			cluster.Keywords = append(cluster.Keywords, second)
+		}
+		cluster.Label = strings.Join(cluster.Keywords, " ")
+
+		for _, member := range cluster.Members {
+			assigned[member] = true
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	other := Cluster{Label: OtherLabel}
+	for i := range texts {
+		if !assigned[i] {
+			other.Members = append(other.Members, i)
+		}
+	}
+	if len(other.Members) > 0 {
+		clusters = append(clusters, other)
+	}
+
+	return clusters
+}
+
+// topKeyword returns the keyword other than exclude shared by the most
+// unassigned texts, counting only members when members is not nil
+func topKeyword(keywords []map[string]bool, assigned []bool, members []int, exclude string) (string, int) {
+	if members == nil {
+		for i := range keywords {
+			if !assigned[i] {
+				members = append(members, i)
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, member := range members {
+		for word := range keywords[member] {
+			if word != exclude {
+				counts[word]++
+			}
+		}
+	}
+
+	best, bestCount := "", 0
+	for word, count := range counts {
+		if count > bestCount || (count == bestCount && word < best) {
+			best, bestCount = word, count
+		}
+	}
+	return best, bestCount
+}
@@ -0,0 +1,93 @@
+package topics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeywordClusters(t *testing.T) {
+	answers := []string{
+		"Pengiriman lambat sekali",
+		"Pengiriman barang lambat",
+		"pengiriman cepat",
+		"Harga terlalu mahal",
+		"harga mahal untuk kualitasnya",
+		"Aplikasi sering error",
+		"ok",
+	}
+
+	tests := []struct {
+		name      string
+		texts     []string
+		maxTopics int
+		want      []Cluster
+	}{
+		{
+			name:      "themes by shared keywords",
+			texts:     answers,
+			maxTopics: 8,
+			want: []Cluster{
+				{Label: "pengiriman lambat", Keywords: []string{"pengiriman", "lambat"}, Members: []int{0, 1, 2}},
+				// harga and mahal tie, so the alphabetically first seeds the theme
+				{Label: "harga mahal", Keywords: []string{"harga", "mahal"}, Members: []int{3, 4}},
+				{Label: OtherLabel, Members: []int{5, 6}},
+			},
+		},
+		{
+			name:      "max topics moves the rest to Lainnya",
+			texts:     answers,
+			maxTopics: 1,
+			want: []Cluster{
+				{Label: "pengiriman lambat", Keywords: []string{"pengiriman", "lambat"}, Members: []int{0, 1, 2}},
+				{Label: OtherLabel, Members: []int{3, 4, 5, 6}},
+			},
+		},
+		{
+			name:      "second keyword shared by fewer than half the members",
+			texts:     []string{"pengiriman cepat", "pengiriman lambat", "pengiriman aman"},
+			maxTopics: 8,
+			want: []Cluster{
+				{Label: "pengiriman", Keywords: []string{"pengiriman"}, Members: []int{0, 1, 2}},
+			},
+		},
+		{
+			name:      "short words and stopwords never seed a theme",
+			texts:     []string{"ok yang sangat", "OK yang sangat"},
+			maxTopics: 8,
+			want:      []Cluster{{Label: OtherLabel, Members: []int{0, 1}}},
+		},
+		{
+			name:      "repeated words count once per answer",
+			texts:     []string{"mahal mahal mahal", "murah"},
+			maxTopics: 8,
+			want:      []Cluster{{Label: OtherLabel, Members: []int{0, 1}}},
+		},
+		{
+			name:      "no answers",
+			maxTopics: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KeywordClusters(tt.texts, tt.maxTopics)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KeywordClusters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordClustersDeterministic(t *testing.T) {
+	texts := []string{"harga mahal", "mahal harga", "layanan ramah", "ramah layanan", "aplikasi lambat", "lambat aplikasi"}
+
+	first := KeywordClusters(texts, 2)
+	for i := 0; i < 20; i++ {
+		if got := KeywordClusters(texts, 2); !reflect.DeepEqual(got, first) {
+			t.Fatalf("KeywordClusters() = %+v, then %+v", first, got)
+		}
+	}
+	if first[0].Label != "aplikasi lambat" || first[1].Label != "harga mahal" {
+		t.Errorf("labels = %q, %q, want the alphabetically first ties", first[0].Label, first[1].Label)
+	}
+}
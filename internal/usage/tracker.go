@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DateFormat is the layout used for daily usage buckets
+const DateFormat = "2006-01-02"
+
+// Listener is notified of every recorded usage
+type Listener func(clientID, modelName string, usage model.TokenUsage)
+
+// bucketKey identifies the usage bucket of one client on one day
+type bucketKey struct {
+	clientID string
+	date     string
+}
+
+// Tracker aggregates token usage per client and per UTC day. Usage is
+// persisted when a repository is configured so reports survive restarts;
+// otherwise it is kept in memory.
+type Tracker struct {
+	mu         sync.RWMutex
+	repository storage.UsageRepository
+	buckets    map[bucketKey]*model.UsageEntry
+	listeners  []Listener
+	now        func() time.Time
+}
+
+// NewTracker creates a new usage tracker. A nil repository keeps usage in memory.
+func NewTracker(repository storage.UsageRepository) *Tracker {
+	return &Tracker{
+		repository: repository,
+		buckets:    make(map[bucketKey]*model.UsageEntry),
+		now:        time.Now,
+	}
+}
+
+// OnRecord registers a listener called after usage is recorded
+func (t *Tracker) OnRecord(listener Listener) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.listeners = append(t.listeners, listener)
+}
+
+// Record adds one request and its token usage to the client's daily bucket
+func (t *Tracker) Record(clientID, modelName string, usage *model.TokenUsage) {
+	var recorded model.TokenUsage
+	if usage != nil {
+		recorded = *usage
+	}
+	date := t.now().UTC().Format(DateFormat)
+
+	if t.repository != nil {
+		err := t.repository.AddUsage(context.Background(), storage.UsageRecord{
+			ClientID:         clientID,
+			Date:             date,
+			Model:            modelName,
+			Requests:         1,
+			PromptTokens:     recorded.PromptTokens,
+			CompletionTokens: recorded.CompletionTokens,
+			TotalTokens:      recorded.TotalTokens,
+		})
+		if err != nil {
+			logger.LogError("Failed to persist token usage", logrus.Fields{
+				"client_id": clientID,
+				"model":     modelName,
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	t.mu.Lock()
+	if t.repository == nil {
+		addUsage(t.buckets, clientID, date, 1, recorded)
+	}
+	listeners := t.listeners
+	t.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(clientID, modelName, recorded)
+	}
+}
+
+// Report returns usage between from and to (inclusive dates).
+// An empty clientID reports every client.
+func (t *Tracker) Report(ctx context.Context, from, to time.Time, clientID string) (*model.UsageReport, error) {
+	fromDate := from.UTC().Format(DateFormat)
+	toDate := to.UTC().Format(DateFormat)
+
+	report := &model.UsageReport{
+		From:    fromDate,
+		To:      toDate,
+		Entries: []model.UsageEntry{},
+		Totals:  model.UsageEntry{ClientID: clientID},
+	}
+
+	buckets := make(map[bucketKey]*model.UsageEntry)
+	if t.repository != nil {
+		records, err := t.repository.QueryUsage(ctx, fromDate, toDate, clientID)
+		if err != nil {
+			return nil, err
+		}
+		// Stored usage is kept per model; reports show one entry per client and day
+		for _, record := range records {
+			addUsage(buckets, record.ClientID, record.Date, record.Requests, model.TokenUsage{
+				PromptTokens:     record.PromptTokens,
+				CompletionTokens: record.CompletionTokens,
+				TotalTokens:      record.TotalTokens,
+			})
+		}
+	} else {
+		t.mu.RLock()
+		for key, entry := range t.buckets {
+			if key.date < fromDate || key.date > toDate {
+				continue
+			}
+			if clientID != "" && key.clientID != clientID {
+				continue
+			}
+			copied := *entry
+			buckets[key] = &copied
+		}
+		t.mu.RUnlock()
+	}
+
+	for _, entry := range buckets {
+		report.Entries = append(report.Entries, *entry)
+		report.Totals.Requests += entry.Requests
+		report.Totals.PromptTokens += entry.PromptTokens
+		report.Totals.CompletionTokens += entry.CompletionTokens
+		report.Totals.TotalTokens += entry.TotalTokens
+	}
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Date != report.Entries[j].Date {
+			return report.Entries[i].Date < report.Entries[j].Date
+		}
+		return report.Entries[i].ClientID < report.Entries[j].ClientID
+	})
+
+	return report, nil
+}
+
+// addUsage adds requests and token usage to the bucket of a client and day
+func addUsage(buckets map[bucketKey]*model.UsageEntry, clientID, date string, requests int, usage model.TokenUsage) {
+	key := bucketKey{clientID: clientID, date: date}
+	entry, exists := buckets[key]
+	if !exists {
+		entry = &model.UsageEntry{ClientID: clientID, Date: date}
+		buckets[key] = entry
+	}
+
+	entry.Requests += requests
+	entry.PromptTokens += usage.PromptTokens
+	entry.CompletionTokens += usage.CompletionTokens
+	entry.TotalTokens += usage.TotalTokens
+}
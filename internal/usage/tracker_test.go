@@ -0,0 +1,92 @@
+package usage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sentiment-api/internal/model"
+	"sentiment-api/internal/storage"
+	"sentiment-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger("error", "json")
+	os.Exit(m.Run())
+}
+
+func TestTrackerReport(t *testing.T) {
+	day := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	previousDay := day.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name        string
+		persistent  bool
+		from        time.Time
+		clientID    string
+		wantEntries []model.UsageEntry
+		wantTotals  model.UsageEntry
+	}{
+		{
+			name: "memory",
+			from: previousDay,
+			wantEntries: []model.UsageEntry{
+				{ClientID: "team", Date: "2026-10-17", Requests: 1, PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110},
+				{ClientID: "boss", Date: "2026-10-18", Requests: 1, PromptTokens: 50, CompletionTokens: 5, TotalTokens: 55},
+				{ClientID: "team", Date: "2026-10-18", Requests: 2, PromptTokens: 300, CompletionTokens: 30, TotalTokens: 330},
+			},
+			wantTotals: model.UsageEntry{Requests: 4, PromptTokens: 450, CompletionTokens: 45, TotalTokens: 495},
+		},
+		{
+			name:       "persistent merges models per day",
+			persistent: true,
+			from:       day,
+			clientID:   "team",
+			wantEntries: []model.UsageEntry{
+				{ClientID: "team", Date: "2026-10-18", Requests: 2, PromptTokens: 300, CompletionTokens: 30, TotalTokens: 330},
+			},
+			wantTotals: model.UsageEntry{ClientID: "team", Requests: 2, PromptTokens: 300, CompletionTokens: 30, TotalTokens: 330},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var repository storage.UsageRepository
+			if tt.persistent {
+				sqliteRepository, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "usage.db"))
+				if err != nil {
+					t.Fatalf("NewSQLiteRepository() error = %v", err)
+				}
+				defer sqliteRepository.Close()
+				repository = sqliteRepository
+			}
+
+			tracker := NewTracker(repository)
+			tracker.now = func() time.Time { return previousDay }
+			tracker.Record("team", "telkom-ai-instruct", &model.TokenUsage{PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110})
+			tracker.now = func() time.Time { return day }
+			tracker.Record("team", "telkom-ai-instruct", &model.TokenUsage{PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110})
+			tracker.Record("team", "telkom-ai-reasoning", &model.TokenUsage{PromptTokens: 200, CompletionTokens: 20, TotalTokens: 220})
+			tracker.Record("boss", "telkom-ai-instruct", &model.TokenUsage{PromptTokens: 50, CompletionTokens: 5, TotalTokens: 55})
+
+			if tt.persistent {
+				// A new tracker over the same database sees the recorded usage
+				tracker = NewTracker(repository)
+			}
+
+			report, err := tracker.Report(context.Background(), tt.from, day, tt.clientID)
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			if !reflect.DeepEqual(report.Entries, tt.wantEntries) {
+				t.Errorf("Entries = %+v, want %+v", report.Entries, tt.wantEntries)
+			}
+			if report.Totals != tt.wantTotals {
+				t.Errorf("Totals = %+v, want %+v", report.Totals, tt.wantTotals)
+			}
+		})
+	}
+}